@@ -13,6 +13,12 @@ const (
 	tagManagedBy     = "ManagedBy"
 	appName          = "stefunny"
 	defaultAliasName = "current"
+
+	// tagManagedStateMachine is the tag of the resource owned by each state machine, e.g. schedule group.
+	tagManagedStateMachine = "ManagedStateMachine"
+
+	// tagManagedArchive is the tag of the rule that names the archive of the rule, because archives can not be tagged.
+	tagManagedArchive = "ManagedArchive"
 )
 
 type App struct {
@@ -20,6 +26,8 @@ type App struct {
 	sfnSvc         SFnService
 	eventbridgeSvc EventBridgeService
 	schedulerSvc   SchedulerService
+	pipesSvc       PipesService
+	cfnClient      CloudFormationClient
 	aliasName      string
 }
 
@@ -29,6 +37,8 @@ type newAppOptions struct {
 	sfnSvc         SFnService
 	eventbridgeSvc EventBridgeService
 	schedulerSvc   SchedulerService
+	pipesSvc       PipesService
+	cfnClient      CloudFormationClient
 	awsCfg         *aws.Config
 }
 
@@ -60,7 +70,9 @@ func (o *newAppOptions) GetEventBridgeService(ctx context.Context) (EventBridgeS
 		return nil, err
 	}
 	client := o.cfg.NewEventBridgeClientFromConfig(awsCfg)
-	o.eventbridgeSvc = NewEventBridgeService(client)
+	svc := NewEventBridgeService(client)
+	svc.SetStateMachineQualifiersClient(o.cfg.NewStepFunctionsClientFromConfig(awsCfg))
+	o.eventbridgeSvc = svc
 	return o.eventbridgeSvc, nil
 }
 
@@ -79,6 +91,21 @@ func (o *newAppOptions) GetSchedulerService(ctx context.Context) (SchedulerServi
 	return o.schedulerSvc, nil
 }
 
+func (o *newAppOptions) GetPipesService(ctx context.Context) (PipesService, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.pipesSvc != nil {
+		return o.pipesSvc, nil
+	}
+	awsCfg, err := o.cfg.LoadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := o.cfg.NewPipesClientFromConfig(awsCfg)
+	o.pipesSvc = NewPipesService(client)
+	return o.pipesSvc, nil
+}
+
 // WithSFNClient sets the SFn client for New(ctx, cfg, opts...)
 // this is for testing
 func WithSFnClient(sfnClient SFnClient) NewAppOption {
@@ -121,6 +148,29 @@ func WithEventBridgeService(eventBridgeService EventBridgeService) NewAppOption
 	}
 }
 
+// WithPipesClient sets the EventBridge Pipes client for New(ctx, cfg, opts...)
+// this is for testing
+func WithPipesClient(pipesClient PipesClient) NewAppOption {
+	return func(o *newAppOptions) {
+		o.pipesSvc = NewPipesService(pipesClient)
+	}
+}
+
+// WithPipesService sets the EventBridge Pipes service for New(ctx, cfg, opts...)
+func WithPipesService(pipesService PipesService) NewAppOption {
+	return func(o *newAppOptions) {
+		o.pipesSvc = pipesService
+	}
+}
+
+// WithCloudFormationClient sets the CloudFormation client for New(ctx, cfg, opts...)
+// it is used to templateize the stack outputs of the cfn section by init and pull.
+func WithCloudFormationClient(cfnClient CloudFormationClient) NewAppOption {
+	return func(o *newAppOptions) {
+		o.cfnClient = cfnClient
+	}
+}
+
 // WithAWSConfig sets the AWS config for New(ctx, cfg, opts...)
 // this is for testing
 func WithAWSConfig(awsCfg aws.Config) NewAppOption {
@@ -162,11 +212,17 @@ func New(ctx context.Context, cfg *Config, opts ...NewAppOption) (*App, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Scheduler client: %w", err)
 	}
+	pipesSvc, err := o.GetPipesService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EventBridge Pipes client: %w", err)
+	}
 	app := &App{
 		cfg:            cfg,
 		sfnSvc:         sfnSvc,
 		eventbridgeSvc: eventbridgeSvc,
 		schedulerSvc:   scheduelrSvc,
+		pipesSvc:       pipesSvc,
+		cfnClient:      o.cfnClient,
 	}
 	app.SetAliasName("")
 	return app, nil
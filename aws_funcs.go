@@ -0,0 +1,204 @@
+package stefunny
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// secretMask replaces secure values in the output of diff and render.
+const secretMask = "********"
+
+// minSecretLength is the length of values to mask, shorter values are too common to replace in the output.
+const minSecretLength = 4
+
+// awsTemplateFuncs are template functions that read values from AWS, the values are cached per load.
+type awsTemplateFuncs struct {
+	ctx      context.Context
+	cfg      *Config
+	ssm      SSMClient
+	sm       SecretsManagerClient
+	cfn      CloudFormationClient
+	sts      STSClient
+	mu       sync.Mutex
+	awsCfg   *aws.Config
+	cache    map[string]string
+	jsonKeys map[string]map[string]any
+	outputs  map[string]map[string]string // stack name => output key => value
+	exports  map[string]string
+}
+
+// appendAWSTemplateFuncs appends the template functions that read values from AWS, such as ssm and secretsmanager.
+func (l *ConfigLoader) appendAWSTemplateFuncs(ctx context.Context, cfg *Config) error {
+	f := &awsTemplateFuncs{
+		ctx:      ctx,
+		cfg:      cfg,
+		ssm:      l.ssmClient,
+		sm:       l.smClient,
+		cfn:      l.cfnClient,
+		sts:      l.stsClient,
+		cache:    make(map[string]string),
+		jsonKeys: make(map[string]map[string]any),
+		outputs:  make(map[string]map[string]string),
+	}
+	l.awsFuncs = f
+	funcMap := template.FuncMap{}
+	for name, fn := range map[string]any{
+		"ssm":            f.ssmParameter,
+		"secretsmanager": f.secretsManagerValue,
+		"aws_account_id": f.awsAccountID,
+		"aws_region":     f.awsRegion,
+		"aws_partition":  f.awsPartition,
+		"arn":            f.arn,
+	} {
+		if _, ok := l.funcMap[name]; ok {
+			log.Printf("[debug] template func `%s` is already defined, skip", name)
+			continue
+		}
+		funcMap[name] = fn
+	}
+	return l.AppendFuncMap("", funcMap)
+}
+
+// loadAWSConfig loads the AWS config for the template functions.
+// The config is not rendered yet, so aws_region with templates is ignored.
+func (f *awsTemplateFuncs) loadAWSConfig() (aws.Config, error) {
+	if f.awsCfg != nil {
+		return *f.awsCfg, nil
+	}
+	var opts []func(*awsconfig.LoadOptions) error
+	if region := f.cfg.AWSRegion; region != "" && !strings.Contains(region, "{{") {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(f.ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	f.awsCfg = &awsCfg
+	return awsCfg, nil
+}
+
+func (f *awsTemplateFuncs) ssmParameter(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := "ssm " + name
+	if value, ok := f.cache[key]; ok {
+		return value, nil
+	}
+	if f.ssm == nil {
+		awsCfg, err := f.loadAWSConfig()
+		if err != nil {
+			return "", err
+		}
+		f.ssm = f.cfg.NewSSMClientFromConfig(awsCfg)
+	}
+	out, err := f.ssm.GetParameter(f.ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get parameter `%s`: %w", name, err)
+	}
+	if out.Parameter == nil {
+		return "", fmt.Errorf("parameter `%s` is not found", name)
+	}
+	value := aws.ToString(out.Parameter.Value)
+	if out.Parameter.Type == ssmtypes.ParameterTypeSecureString {
+		f.cfg.SecretValues.Set(key, value)
+	}
+	log.Printf("[debug] read ssm parameter `%s`", name)
+	f.cache[key] = value
+	return value, nil
+}
+
+func (f *awsTemplateFuncs) secretsManagerValue(id string, keys ...string) (string, error) {
+	if len(keys) > 1 {
+		return "", fmt.Errorf("too many number of arguments: %d", len(keys)+1)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := "secretsmanager " + id
+	value, ok := f.cache[key]
+	if !ok {
+		if f.sm == nil {
+			awsCfg, err := f.loadAWSConfig()
+			if err != nil {
+				return "", err
+			}
+			f.sm = f.cfg.NewSecretsManagerClientFromConfig(awsCfg)
+		}
+		out, err := f.sm.GetSecretValue(f.ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(id),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get secret value `%s`: %w", id, err)
+		}
+		if out.SecretString == nil {
+			return "", fmt.Errorf("secret `%s` has no string value, binary secrets are not supported", id)
+		}
+		value = *out.SecretString
+		log.Printf("[debug] read secret value `%s`", id)
+		f.cache[key] = value
+		f.cfg.SecretValues.Set(key, value)
+	}
+	if len(keys) == 0 {
+		return value, nil
+	}
+	values, ok := f.jsonKeys[id]
+	if !ok {
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			return "", fmt.Errorf("secret `%s` is not a JSON object: %w", id, err)
+		}
+		f.jsonKeys[id] = values
+	}
+	v, ok := values[keys[0]]
+	if !ok {
+		return "", fmt.Errorf("secret `%s` has no key `%s`", id, keys[0])
+	}
+	s, ok := v.(string)
+	if !ok {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("secret `%s` key `%s`: %w", id, keys[0], err)
+		}
+		s = string(bs)
+	}
+	f.cfg.SecretValues.Set(key+" "+keys[0], s)
+	return s, nil
+}
+
+// MaskSecrets replaces secure values read by the template functions, such as SecureString parameters and secrets, with the mask.
+func (cfg *Config) MaskSecrets(s string) string {
+	if cfg.SecretValues == nil || cfg.SecretValues.Len() == 0 {
+		return s
+	}
+	var values []string
+	for _, value := range cfg.SecretValues.Values() {
+		if len(value) < minSecretLength {
+			continue
+		}
+		values = append(values, value)
+		if escaped, err := jsonEscape(value); err == nil && escaped != value {
+			values = append(values, escaped)
+		}
+	}
+	// longer values first, not to leave a part of the secret that contains another one.
+	sort.SliceStable(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		s = strings.ReplaceAll(s, value, secretMask)
+	}
+	return s
+}
@@ -0,0 +1,167 @@
+package stefunny
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// AWSIdentity is the account and partition of the caller, and the region of the AWS config.
+// It is referred by the aws_account_id, aws_region, aws_partition and arn template functions.
+type AWSIdentity struct {
+	AccountID string
+	Partition string
+	Region    string
+}
+
+func newAWSIdentity(out *sts.GetCallerIdentityOutput, region string) (*AWSIdentity, error) {
+	callerArn, err := arn.Parse(aws.ToString(out.Arn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse caller identity arn: %w", err)
+	}
+	return &AWSIdentity{
+		AccountID: aws.ToString(out.Account),
+		Partition: callerArn.Partition,
+		Region:    region,
+	}, nil
+}
+
+// arnWithoutRegion are the services whose ARNs have no region, such as arn:aws:iam::012345678901:role/foo.
+var arnWithoutRegion = map[string]bool{
+	"iam":           true,
+	"s3":            true,
+	"cloudfront":    true,
+	"route53":       true,
+	"organizations": true,
+}
+
+// arnWithoutAccount are the services whose ARNs have no account, such as arn:aws:s3:::bucket.
+var arnWithoutAccount = map[string]bool{
+	"s3": true,
+}
+
+// ARN builds the ARN of the service and the resource in the account and the region.
+func (id *AWSIdentity) ARN(service string, resource string) string {
+	a := arn.ARN{
+		Partition: id.Partition,
+		Service:   service,
+		Region:    id.Region,
+		AccountID: id.AccountID,
+		Resource:  resource,
+	}
+	if arnWithoutRegion[service] {
+		a.Region = ""
+	}
+	if arnWithoutAccount[service] {
+		a.AccountID = ""
+	}
+	return a.String()
+}
+
+// arnPattern matches ARNs in strings, the resource ends at characters that are not in ARNs of templates and definitions.
+var arnPattern = regexp.MustCompile("arn:[a-z-]+:([a-z0-9-]+):[a-z0-9-]*:[0-9]*:([^\\s\"'`{}(),]+)")
+
+// Templateize replaces ARNs, account ids and regions in the string with the template functions.
+// Account ids and regions in the resources of the replaced ARNs are kept, they are rendered back as they are.
+func (id *AWSIdentity) Templateize(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range arnPattern.FindAllStringSubmatchIndex(s, -1) {
+		if m[1] < len(s) && (s[m[1]] == '{' || s[m[1]] == '}') {
+			// the resource continues with the template, such as function:{{ env `NAME` }}
+			continue
+		}
+		service, resource := s[m[2]:m[3]], s[m[4]:m[5]]
+		if id.ARN(service, resource) != s[m[0]:m[1]] {
+			continue
+		}
+		b.WriteString(id.templateizeIdentity(s[last:m[0]]))
+		fmt.Fprintf(&b, "{{ arn `%s` `%s` }}", service, resource)
+		last = m[1]
+	}
+	b.WriteString(id.templateizeIdentity(s[last:]))
+	return b.String()
+}
+
+// templateizeIdentity replaces account ids and regions in the string with the template functions.
+func (id *AWSIdentity) templateizeIdentity(s string) string {
+	if id.AccountID != "" {
+		s = strings.ReplaceAll(s, id.AccountID, "{{ aws_account_id }}")
+	}
+	if id.Region != "" {
+		s = strings.ReplaceAll(s, id.Region, "{{ aws_region }}")
+	}
+	return s
+}
+
+func (f *awsTemplateFuncs) callerIdentity() (*AWSIdentity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cfg.AWSIdentity != nil {
+		return f.cfg.AWSIdentity, nil
+	}
+	awsCfg, err := f.loadAWSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if f.sts == nil {
+		f.sts = f.cfg.NewStsClientFromConfig(awsCfg)
+	}
+	out, err := f.sts.GetCallerIdentity(f.ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	id, err := newAWSIdentity(out, awsCfg.Region)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[debug] caller identity: %s", aws.ToString(out.Arn))
+	f.cfg.AWSIdentity = id
+	return id, nil
+}
+
+func (f *awsTemplateFuncs) awsAccountID() (string, error) {
+	id, err := f.callerIdentity()
+	if err != nil {
+		return "", err
+	}
+	return id.AccountID, nil
+}
+
+func (f *awsTemplateFuncs) awsRegion() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	awsCfg, err := f.loadAWSConfig()
+	if err != nil {
+		return "", err
+	}
+	if awsCfg.Region == "" {
+		return "", errors.New("aws region is not set, set AWS_REGION or --region")
+	}
+	return awsCfg.Region, nil
+}
+
+func (f *awsTemplateFuncs) awsPartition() (string, error) {
+	id, err := f.callerIdentity()
+	if err != nil {
+		return "", err
+	}
+	return id.Partition, nil
+}
+
+func (f *awsTemplateFuncs) arn(service string, resource string) (string, error) {
+	id, err := f.callerIdentity()
+	if err != nil {
+		return "", err
+	}
+	if id.Region == "" && !arnWithoutRegion[service] {
+		return "", fmt.Errorf("aws region is not set for the arn of `%s`", service)
+	}
+	return id.ARN(service, resource), nil
+}
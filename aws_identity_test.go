@@ -0,0 +1,87 @@
+package stefunny_test
+
+import (
+	"testing"
+
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSIdentityTemplateize(t *testing.T) {
+	id := &stefunny.AWSIdentity{
+		AccountID: "012345678901",
+		Partition: "aws",
+		Region:    "ap-northeast-1",
+	}
+	cases := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "regional",
+			value:    "arn:aws:lambda:ap-northeast-1:012345678901:function:hello:$LATEST",
+			expected: "{{ arn `lambda` `function:hello:$LATEST` }}",
+		},
+		{
+			name:     "global",
+			value:    "arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role",
+			expected: "{{ arn `iam` `role/service-role/StepFunctions-Hello-role` }}",
+		},
+		{
+			name:     "without account",
+			value:    "arn:aws:s3:::hello-bucket",
+			expected: "{{ arn `s3` `hello-bucket` }}",
+		},
+		{
+			name:     "service integration",
+			value:    "arn:aws:states:::lambda:invoke",
+			expected: "arn:aws:states:::lambda:invoke",
+		},
+		{
+			name:     "other region",
+			value:    "arn:aws:sns:us-east-1:012345678901:hello",
+			expected: "arn:aws:sns:us-east-1:{{ aws_account_id }}:hello",
+		},
+		{
+			name:     "other account",
+			value:    "arn:aws:sns:ap-northeast-1:123456789012:hello",
+			expected: "arn:aws:sns:{{ aws_region }}:123456789012:hello",
+		},
+		{
+			name:     "followed by template",
+			value:    "arn:aws:lambda:ap-northeast-1:012345678901:function:{{ env `NAME` }}",
+			expected: "arn:aws:lambda:{{ aws_region }}:{{ aws_account_id }}:function:{{ env `NAME` }}",
+		},
+		{
+			name:     "in intrinsic function",
+			value:    "States.Format('arn:aws:sqs:ap-northeast-1:012345678901:{}', $.name)",
+			expected: "States.Format('arn:aws:sqs:{{ aws_region }}:{{ aws_account_id }}:{}', $.name)",
+		},
+		{
+			name:     "multiple",
+			value:    "arn:aws:sns:ap-northeast-1:012345678901:a,arn:aws:sns:ap-northeast-1:012345678901:b",
+			expected: "{{ arn `sns` `a` }},{{ arn `sns` `b` }}",
+		},
+		{
+			name:     "region in resource",
+			value:    "arn:aws:lambda:ap-northeast-1:012345678901:function:proc-ap-northeast-1",
+			expected: "{{ arn `lambda` `function:proc-ap-northeast-1` }}",
+		},
+		{
+			name:     "account and region in resource",
+			value:    "arn:aws:s3:::cdk-hnb659fds-assets-012345678901-ap-northeast-1/asset.zip",
+			expected: "{{ arn `s3` `cdk-hnb659fds-assets-012345678901-ap-northeast-1/asset.zip` }}",
+		},
+		{
+			name:     "around arn",
+			value:    "ap-northeast-1 arn:aws:sns:ap-northeast-1:012345678901:topic-012345678901 012345678901",
+			expected: "{{ aws_region }} {{ arn `sns` `topic-012345678901` }} {{ aws_account_id }}",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, id.Templateize(c.value))
+		})
+	}
+}
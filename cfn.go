@@ -0,0 +1,152 @@
+package stefunny
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+)
+
+// appendCFnTemplateFuncs appends cfn_output and cfn_export for each func_prefix of the cfn section.
+func (l *ConfigLoader) appendCFnTemplateFuncs(cfg *Config) error {
+	prefixes := make(map[string]bool)
+	for i, c := range cfg.CFn {
+		if c.Stack == "" {
+			return fmt.Errorf("cfn[%d].stack is required", i)
+		}
+		if prefixes[c.FuncPrefix] {
+			continue
+		}
+		prefixes[c.FuncPrefix] = true
+		err := l.AppendFuncMap(c.FuncPrefix, template.FuncMap{
+			"cfn_output": l.awsFuncs.cfnOutput,
+			"cfn_export": l.awsFuncs.cfnExport,
+		})
+		if err != nil {
+			return fmt.Errorf("cfn[%d] %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (f *awsTemplateFuncs) cloudFormationClient() (CloudFormationClient, error) {
+	if f.cfn != nil {
+		return f.cfn, nil
+	}
+	awsCfg, err := f.loadAWSConfig()
+	if err != nil {
+		return nil, err
+	}
+	f.cfn = f.cfg.NewCloudFormationClientFromConfig(awsCfg)
+	return f.cfn, nil
+}
+
+func (f *awsTemplateFuncs) cfnOutput(stack string, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	outputs, ok := f.outputs[stack]
+	if !ok {
+		client, err := f.cloudFormationClient()
+		if err != nil {
+			return "", err
+		}
+		list, err := describeStackOutputs(f.ctx, client, stack)
+		if err != nil {
+			return "", err
+		}
+		outputs = make(map[string]string, len(list))
+		for _, o := range list {
+			outputs[aws.ToString(o.OutputKey)] = aws.ToString(o.OutputValue)
+		}
+		log.Printf("[debug] read outputs of stack `%s`", stack)
+		f.outputs[stack] = outputs
+	}
+	value, ok := outputs[key]
+	if !ok {
+		return "", fmt.Errorf("stack `%s` has no output `%s`", stack, key)
+	}
+	return value, nil
+}
+
+func (f *awsTemplateFuncs) cfnExport(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.exports == nil {
+		client, err := f.cloudFormationClient()
+		if err != nil {
+			return "", err
+		}
+		exports := make(map[string]string)
+		p := cloudformation.NewListExportsPaginator(client, &cloudformation.ListExportsInput{})
+		for p.HasMorePages() {
+			page, err := p.NextPage(f.ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to list exports: %w", err)
+			}
+			for _, e := range page.Exports {
+				exports[aws.ToString(e.Name)] = aws.ToString(e.Value)
+			}
+		}
+		log.Printf("[debug] read %d exports", len(exports))
+		f.exports = exports
+	}
+	value, ok := f.exports[name]
+	if !ok {
+		return "", fmt.Errorf("export `%s` is not found", name)
+	}
+	return value, nil
+}
+
+func describeStackOutputs(ctx context.Context, client CloudFormationClient, stack string) ([]cfntypes.Output, error) {
+	out, err := client.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(stack),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe stack `%s`: %w", stack, err)
+	}
+	if len(out.Stacks) == 0 {
+		return nil, fmt.Errorf("stack `%s` is not found", stack)
+	}
+	return out.Stacks[0].Outputs, nil
+}
+
+// ListResourcesFromCFnStack returns the template function calls and the values of the stack outputs.
+// Outputs with the export name are referred by cfn_export, others are by cfn_output.
+func ListResourcesFromCFnStack(ctx context.Context, client CloudFormationClient, stack string) (*OrderdMap[string, string], error) {
+	outputs, err := describeStackOutputs(ctx, client, stack)
+	if err != nil {
+		return nil, err
+	}
+	resources := make(map[string]string) // output value => template function call
+	resourceValues := make([]string, 0)
+	for _, o := range outputs {
+		value := aws.ToString(o.OutputValue)
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		key := fmt.Sprintf("cfn_output `%s` `%s`", stack, aws.ToString(o.OutputKey))
+		if name := aws.ToString(o.ExportName); name != "" {
+			key = fmt.Sprintf("cfn_export `%s`", name)
+		}
+		if duplicated, ok := resources[value]; ok {
+			log.Printf("[warn] `%s` is duplicated (`%s` and `%s`), skip after key", value, duplicated, key)
+			continue
+		}
+		resources[value] = key
+		resourceValues = append(resourceValues, value)
+	}
+	orderd := NewOrderdMap[string, string]()
+	sort.Slice(resourceValues, func(i, j int) bool {
+		return len(resourceValues[i]) < len(resourceValues[j])
+	})
+	for _, v := range resourceValues {
+		orderd.Set(resources[v], v)
+	}
+	return orderd, nil
+}
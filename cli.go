@@ -16,27 +16,36 @@ import (
 const dryRunStr = "DRY RUN"
 
 type CLI struct {
-	LogLevel  string   `name:"log-level" help:"Set log level (debug, info, notice, warn, error)" default:"info" env:"STEFUNNY_LOG_LEVEL" json:"log_level,omitempty"`
-	Config    string   `name:"config" short:"c" help:"Path to config file" default:"stefunny.yaml" env:"STEFUNNY_CONFIG" type:"path" json:"config,omitempty"`
-	TFState   string   `name:"tfstate" help:"URL to terraform.tfstate referenced in config" env:"STEFUNNY_TFSTATE" json:"tfstate,omitempty"`
-	ExtStr    []string `name:"ext-str" help:"external string values for Jsonnet" default:"" json:"ext_str,omitempty"`
-	ExtCode   []string `name:"ext-code" help:"external code values for Jsonnet" default:"" json:"ext_code,omitempty"`
-	AWSRegion string   `name:"region" help:"AWS region" default:"" env:"AWS_REGION" json:"region,omitempty"`
-	AliasName string   `name:"alias" help:"Alias name for state machine" default:"current" env:"STEFUNNY_ALIAS" json:"alias,omitempty"`
+	LogLevel        string   `name:"log-level" help:"Set log level (debug, info, notice, warn, error)" default:"info" env:"STEFUNNY_LOG_LEVEL" json:"log_level,omitempty"`
+	Config          string   `name:"config" short:"c" help:"Path to config file" default:"stefunny.yaml" env:"STEFUNNY_CONFIG" type:"path" json:"config,omitempty"`
+	TFState         string   `name:"tfstate" help:"URL to terraform.tfstate referenced in config" env:"STEFUNNY_TFSTATE" json:"tfstate,omitempty"`
+	ExtStr          []string `name:"ext-str" help:"external string values for Jsonnet" default:"" json:"ext_str,omitempty"`
+	ExtCode         []string `name:"ext-code" help:"external code values for Jsonnet" default:"" json:"ext_code,omitempty"`
+	JPath           []string `name:"jpath" help:"Jsonnet library path, takes precedence over jsonnet.jpath in config" json:"jpath,omitempty"`
+	JsonnetTemplate bool     `name:"jsonnet-template" help:"Render Jsonnet files as templates after evaluation" default:"true" negatable:"" json:"jsonnet_template,omitempty"`
+	AWSRegion       string   `name:"region" help:"AWS region" default:"" env:"AWS_REGION" json:"region,omitempty"`
+	AliasName       string   `name:"alias" help:"Alias name for state machine" default:"current" env:"STEFUNNY_ALIAS" json:"alias,omitempty"`
+	Env             string   `name:"env" aliases:"environment" help:"Environment name to overlay config, e.g. prd merges stefunny.prd.yaml" env:"STEFUNNY_ENV" json:"env,omitempty"`
+	Var             []string `name:"var" help:"Override var value, e.g. name=value" placeholder:"KEY=VALUE" sep:"none" json:"var,omitempty"`
+	VarFile         []string `name:"var-file" help:"Path to file of var values, overrides vars before --var" sep:"none" json:"var_file,omitempty"`
+	Set             []string `name:"set" help:"Override config value after templating, e.g. state_machine.tracing_configuration.enabled=true" placeholder:"KEY=VALUE" sep:"none" json:"set,omitempty"`
 
-	Version  struct{}              `cmd:"" help:"Show version" json:"version,omitempty"`
-	Init     InitOption            `cmd:"" help:"Initialize stefunny configuration" json:"init,omitempty"`
-	Delete   DeleteOption          `cmd:"" help:"Delete state machine and schedule rules" json:"delete,omitempty"`
-	Deploy   DeployCommandOption   `cmd:"" help:"Deploy state machine and schedule rules" json:"deploy,omitempty"`
-	Rollback RollbackOption        `cmd:"" help:"Rollback state machine" json:"rollback,omitempty"`
-	Schedule ScheduleCommandOption `cmd:"" help:"Enable or disable schedule rules (deprecated)" json:"schedule,omitempty"`
-	Render   RenderOption          `cmd:"" help:"Render state machine definition" json:"render,omitempty"`
-	Execute  ExecuteOption         `cmd:"" help:"Execute state machine" json:"execute,omitempty"`
-	Versions VersionsOption        `cmd:"" help:"Manage state machine versions" json:"versions,omitempty"`
-	Diff     DiffOption            `cmd:"" help:"Show diff of state machine definition and trigers" json:"diff,omitempty"`
-	Pull     PullOption            `cmd:"" help:"Pull state machine definition" json:"pull,omitempty"`
-	Studio   StudioOption          `cmd:"" help:"Show Step Functions workflow studio URL" json:"studio,omitempty"`
-	Status   StatusOption          `cmd:"" help:"Show status of state machine" json:"status,omitempty"`
+	Version    struct{}              `cmd:"" help:"Show version" json:"version,omitempty"`
+	Init       InitOption            `cmd:"" help:"Initialize stefunny configuration" json:"init,omitempty"`
+	Delete     DeleteOption          `cmd:"" help:"Delete state machine and schedule rules" json:"delete,omitempty"`
+	Deploy     DeployCommandOption   `cmd:"" help:"Deploy state machine and schedule rules" json:"deploy,omitempty"`
+	Rollback   RollbackOption        `cmd:"" help:"Rollback state machine" json:"rollback,omitempty"`
+	Schedule   ScheduleCommandOption `cmd:"" help:"Enable or disable schedule rules (deprecated)" json:"schedule,omitempty"`
+	Render     RenderOption          `cmd:"" help:"Render state machine definition" json:"render,omitempty"`
+	Execute    ExecuteOption         `cmd:"" help:"Execute state machine" json:"execute,omitempty"`
+	Versions   VersionsOption        `cmd:"" help:"Manage state machine versions" json:"versions,omitempty"`
+	Diff       DiffOption            `cmd:"" help:"Show diff of state machine definition and trigers" json:"diff,omitempty"`
+	Pull       PullOption            `cmd:"" help:"Pull state machine definition" json:"pull,omitempty"`
+	Studio     StudioOption          `cmd:"" help:"Show Step Functions workflow studio URL" json:"studio,omitempty"`
+	Status     StatusOption          `cmd:"" help:"Show status of state machine" json:"status,omitempty"`
+	Executions ExecutionsOption      `cmd:"" help:"Inspect state machine executions" json:"executions,omitempty"`
+	Trigger    TriggerOption         `cmd:"" help:"Inspect and operate triggers" json:"trigger,omitempty"`
+	Schema     struct{}              `cmd:"" help:"Show JSON Schema of config file" json:"schema,omitempty"`
 
 	kctx           *kong.Context
 	exitFunc       func(int)
@@ -152,11 +161,24 @@ func (cli *CLI) Parse(args []string) (string, error) {
 	return cmd, nil
 }
 
+// subCommand returns the sub command name, e.g. `diff` for `executions diff <a> <b>`
+func (cli *CLI) subCommand() string {
+	if cli.kctx == nil {
+		return ""
+	}
+	fields := strings.Fields(cli.kctx.Command())
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
 var defaultConfigNames = []string{
 	"stefunny.yaml",
 	"stefunny.yml",
 	"stefunny.json",
 	"stefunny.jsonnet",
+	"stefunny.toml",
 }
 
 // NewApp creates a new App instance from the CLI configuration
@@ -195,6 +217,25 @@ func (cli *CLI) NewApp(ctx context.Context) (*App, error) {
 		extCode[kv[0]] = kv[1]
 	}
 	configLoader := NewConfigLoader(extStr, extCode)
+	configLoader.SetJsonnetTemplate(cli.JsonnetTemplate)
+	if len(cli.JPath) > 0 {
+		configLoader.AppendJPath(cli.JPath...)
+	}
+	if err := configLoader.SetEnv(cli.Env); err != nil {
+		return nil, err
+	}
+	if err := cli.appendVars(configLoader); err != nil {
+		return nil, err
+	}
+	for _, s := range cli.Set {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid set value: %s", s)
+		}
+		if err := configLoader.AppendOverride(kv[0], kv[1]); err != nil {
+			return nil, fmt.Errorf("invalid set value: %w", err)
+		}
+	}
 	if cli.TFState != "" {
 		log.Println("[warn] tfstate flag is deprecated, use tfstate in config file")
 		err := configLoader.AppendTFState(ctx, "", cli.TFState)
@@ -213,6 +254,25 @@ func (cli *CLI) NewApp(ctx context.Context) (*App, error) {
 	return app, nil
 }
 
+// appendVars appends --var-file and then --var to the loader, --var overrides --var-file.
+func (cli *CLI) appendVars(l *ConfigLoader) error {
+	for _, path := range cli.VarFile {
+		if err := l.AppendVarFile(path); err != nil {
+			return err
+		}
+	}
+	for _, s := range cli.Var {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid var value: %s", s)
+		}
+		if err := l.AppendVar(kv[0], kv[1]); err != nil {
+			return fmt.Errorf("invalid var value: %w", err)
+		}
+	}
+	return nil
+}
+
 // Main runs the command
 func (cli *CLI) Main(ctx context.Context, args []string) error {
 	cmd, err := cli.Parse(args)
@@ -224,6 +284,15 @@ func (cli *CLI) Main(ctx context.Context, args []string) error {
 		log.Println("[debug] run init, use default config")
 		cli.Init.ConfigPath = cli.Config
 		cli.Init.AWSRegion = cli.AWSRegion
+		l := NewConfigLoader(nil, nil)
+		if err := cli.appendVars(l); err != nil {
+			return err
+		}
+		vars, err := l.ResolveVars(nil, true)
+		if err != nil {
+			return err
+		}
+		cli.Init.Vars = vars
 		cfg := NewDefaultConfig()
 		app, err := New(ctx, cfg)
 		if err != nil {
@@ -232,6 +301,9 @@ func (cli *CLI) Main(ctx context.Context, args []string) error {
 		cli.Init.TFState = cli.TFState
 		return app.Init(ctx, cli.Init)
 	}
+	if cmd == "schema" {
+		return WriteConfigJSONSchema(cli.stdout)
+	}
 	log.Println("[debug] create new app")
 	app, err := cli.NewApp(ctx)
 	if err != nil {
@@ -249,6 +321,7 @@ func (cli *CLI) Main(ctx context.Context, args []string) error {
 	case "delete":
 		return app.Delete(ctx, cli.Delete)
 	case "diff":
+		cli.Diff.Writer = cli.stdout
 		return app.Diff(ctx, cli.Diff)
 	case "versions":
 		return app.Versions(ctx, cli.Versions)
@@ -263,6 +336,28 @@ func (cli *CLI) Main(ctx context.Context, args []string) error {
 		return app.Studio(ctx, cli.Studio)
 	case "status":
 		return app.Status(ctx, cli.Status)
+	case "executions":
+		switch sub := cli.subCommand(); sub {
+		case "diff":
+			return app.ExecutionsDiff(ctx, cli.Executions.Diff)
+		default:
+			return fmt.Errorf("unknown sub command: executions %s", sub)
+		}
+	case "trigger":
+		switch sub := cli.subCommand(); sub {
+		case "test-pattern":
+			return app.TriggerTestPattern(ctx, cli.Trigger.TestPattern)
+		case "enable":
+			return app.TriggerSetEnabled(ctx, cli.Trigger.Enable, true)
+		case "disable":
+			return app.TriggerSetEnabled(ctx, cli.Trigger.Disable, false)
+		case "replay":
+			return app.TriggerReplay(ctx, cli.Trigger.Replay)
+		case "send-event":
+			return app.TriggerSendEvent(ctx, cli.Trigger.SendEvent)
+		default:
+			return fmt.Errorf("unknown sub command: trigger %s", sub)
+		}
 	default:
 		return fmt.Errorf("unknown command: %s", cmd)
 	}
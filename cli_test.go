@@ -89,6 +89,26 @@ func TestCLI__Parse(t *testing.T) {
 			args: []string{"deploy", "--region", "ap-northeast-1"},
 			cmd:  "deploy",
 		},
+		{
+			name: "deploy with set",
+			args: []string{"deploy", "--set", "state_machine.tracing_configuration.enabled=true", "--set", "trigger.schedule[0].description=hourly, in JST"},
+			cmd:  "deploy",
+		},
+		{
+			name: "deploy with env",
+			args: []string{"deploy", "--env", "prd"},
+			cmd:  "deploy",
+		},
+		{
+			name: "deploy with environment",
+			args: []string{"deploy", "--environment", "prd"},
+			cmd:  "deploy",
+		},
+		{
+			name: "deploy with var",
+			args: []string{"deploy", "--var-file", "vars.yaml", "--var", "name=Hello", "--var", "tags=[a, b]"},
+			cmd:  "deploy",
+		},
 		{
 			name: "deploy help",
 			args: []string{"deploy", "--help"},
@@ -174,6 +194,76 @@ func TestCLI__Parse(t *testing.T) {
 			args: []string{"execute", "--input", "-"},
 			cmd:  "execute",
 		},
+		{
+			name: "execute with query",
+			args: []string{"execute", "--query", ".result", "--format", "json", "--output-file", "output.json"},
+			cmd:  "execute",
+		},
+		{
+			name: "execute at",
+			args: []string{"execute", "--at", "2026-10-17T03:00", "--timezone", "Asia/Tokyo"},
+			cmd:  "execute",
+		},
+		{
+			name: "execute cancel scheduled",
+			args: []string{"execute", "--cancel-scheduled", "Hello-at-20261016T180000Z"},
+			cmd:  "execute",
+		},
+		{
+			name: "execute with invalid format",
+			args: []string{"execute", "--format", "xml"},
+			code: 1,
+		},
+		{
+			name: "executions diff",
+			args: []string{"executions", "diff", "first", "second"},
+			cmd:  "executions",
+		},
+		{
+			name: "executions diff help",
+			args: []string{"executions", "diff", "--help"},
+			code: 0,
+		},
+		{
+			name: "executions diff missing args",
+			args: []string{"executions", "diff", "first"},
+			code: 1,
+		},
+		{
+			name: "trigger test-pattern",
+			args: []string{"trigger", "test-pattern", "--rule", "Scheduled-hourly", "--event", "testdata/event_sample.json"},
+			cmd:  "trigger",
+		},
+		{
+			name: "trigger test-pattern help",
+			args: []string{"trigger", "test-pattern", "--help"},
+			code: 0,
+		},
+		{
+			name: "trigger disable",
+			args: []string{"trigger", "disable", "Scheduled-hourly", "--dry-run"},
+			cmd:  "trigger",
+		},
+		{
+			name: "trigger enable all",
+			args: []string{"trigger", "enable", "--all"},
+			cmd:  "trigger",
+		},
+		{
+			name: "trigger replay",
+			args: []string{"trigger", "replay", "--rule", "hello-rule", "--from", "2026-10-16T00:00", "--to", "2026-10-16T06:00:00+09:00"},
+			cmd:  "trigger",
+		},
+		{
+			name: "trigger send-event",
+			args: []string{"trigger", "send-event", "--rule", "hello-rule", "--event", "testdata/event_sample.json"},
+			cmd:  "trigger",
+		},
+		{
+			name: "schema",
+			args: []string{"schema"},
+			cmd:  "schema",
+		},
 	}
 	g := goldie.New(
 		t,
@@ -18,12 +18,17 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/pipes"
+	pipestypes "github.com/aws/aws-sdk-go-v2/service/pipes/types"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/sfn"
 	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/fujiwara/tfstate-lookup/tfstate"
 	"github.com/goccy/go-yaml"
@@ -36,6 +41,7 @@ const (
 	jsonExt    = ".json"
 	ymlExt     = ".yml"
 	yamlExt    = ".yaml"
+	tomlExt    = ".toml"
 )
 
 //go:generate go tool mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
@@ -43,6 +49,23 @@ type CloudWatchLogsClient interface {
 	cloudwatchlogs.DescribeLogGroupsAPIClient
 }
 
+type SSMClient interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+type SecretsManagerClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+type STSClient interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+type CloudFormationClient interface {
+	cloudformation.DescribeStacksAPIClient
+	cloudformation.ListExportsAPIClient
+}
+
 type ConfigLoader struct {
 	funcMap           template.FuncMap
 	nestedRednerFiles []string
@@ -52,6 +75,22 @@ type ConfigLoader struct {
 	templateFiles     *OrderdMap[string, string]
 	vm                *jsonnet.VM
 	cwLogsClient      CloudWatchLogsClient
+	ssmClient         SSMClient
+	smClient          SecretsManagerClient
+	cfnClient         CloudFormationClient
+	stsClient         STSClient
+	awsFuncs          *awsTemplateFuncs
+	env               string
+	overrides         []configOverride
+	vars              map[string]any
+	varOverrides      []varOverride
+
+	disableJsonnetTemplate bool
+	nativeDir              string // the directory of the Jsonnet file under evaluation
+	nativeResolve          bool
+	extStr                 map[string]string
+	extCode                map[string]string
+	jpaths                 []string
 }
 
 func NewConfigLoader(extStr, extCode map[string]string) *ConfigLoader {
@@ -62,16 +101,41 @@ func NewConfigLoader(extStr, extCode map[string]string) *ConfigLoader {
 	for k, v := range extCode {
 		vm.ExtCode(k, v)
 	}
-	return &ConfigLoader{
-		funcMap: make(template.FuncMap),
-		vm:      vm,
-	}
+	l := &ConfigLoader{
+		funcMap:       make(template.FuncMap),
+		vm:            vm,
+		envs:          NewOrderdMap[string, string](),
+		mustEnvs:      NewOrderdMap[string, string](),
+		files:         NewOrderdMap[string, string](),
+		templateFiles: NewOrderdMap[string, string](),
+		extStr:        extStr,
+		extCode:       extCode,
+	}
+	vm.Importer(newJsonnetImporter(nil))
+	l.registerNativeFunctions()
+	return l
 }
 
 func (l *ConfigLoader) SetCloudWatchLogsClient(client CloudWatchLogsClient) {
 	l.cwLogsClient = client
 }
 
+func (l *ConfigLoader) SetSSMClient(client SSMClient) {
+	l.ssmClient = client
+}
+
+func (l *ConfigLoader) SetSecretsManagerClient(client SecretsManagerClient) {
+	l.smClient = client
+}
+
+func (l *ConfigLoader) SetCloudFormationClient(client CloudFormationClient) {
+	l.cfnClient = client
+}
+
+func (l *ConfigLoader) SetSTSClient(client STSClient) {
+	l.stsClient = client
+}
+
 func (l *ConfigLoader) AppendTFState(ctx context.Context, prefix string, tfState string) error {
 	funcs, err := tfstate.FuncMap(ctx, tfState)
 	if err != nil {
@@ -92,19 +156,49 @@ func (l *ConfigLoader) AppendFuncMap(prefix string, funcMap template.FuncMap) er
 }
 
 func (l *ConfigLoader) load(path string, strict bool, withEnv bool, v any) error {
-	ext := filepath.Ext(path)
-	switch ext {
-	case yamlExt, ymlExt:
-		b, err := os.ReadFile(path)
+	src, err := l.readFile(path, withEnv)
+	if err != nil {
+		return err
+	}
+	if err := decodeConfig(filepath.Ext(path), src.rendered, strict, v); err != nil {
+		return src.wrapDecodeError(err, nil)
+	}
+	return nil
+}
+
+// readFile reads the file as YAML, TOML or JSON bytes, Jsonnet is evaluated to JSON.
+func (l *ConfigLoader) readFile(path string, withEnv bool) (*configSource, error) {
+	src := &configSource{path: path, ext: filepath.Ext(path)}
+	switch src.ext {
+	case yamlExt, ymlExt, tomlExt:
+		bs, err := os.ReadFile(path)
 		if err != nil {
-			return fmt.Errorf("failed to read file: %w", err)
+			return nil, fmt.Errorf("failed to read file: %w", err)
 		}
-		if withEnv {
-			b, err = l.renderTemplate(b, filepath.Dir(path))
-			if err != nil {
-				return fmt.Errorf("failed to render template: %w", err)
-			}
+		src.source = bs
+	case jsonExt, jsonnetExt:
+		jsonStr, err := l.evaluateJsonnet(path, withEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate jsonnet file: %w", err)
 		}
+		src.source = []byte(jsonStr)
+		src.evaluated = true
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %s", src.ext)
+	}
+	if !withEnv || (src.ext == jsonnetExt && l.disableJsonnetTemplate) {
+		src.rendered = src.source
+		return src, nil
+	}
+	if err := l.renderSource(src, filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return src, nil
+}
+
+func decodeConfig(ext string, b []byte, strict bool, v any) error {
+	switch ext {
+	case yamlExt, ymlExt:
 		decoderOpts := []yaml.DecodeOption{
 			yaml.UseJSONUnmarshaler(),
 		}
@@ -116,18 +210,13 @@ func (l *ConfigLoader) load(path string, strict bool, withEnv bool, v any) error
 			return err
 		}
 		return nil
-	case jsonExt, jsonnetExt:
-		jsonStr, err := l.vm.EvaluateFile(path)
+	case tomlExt:
+		bs, err := TOML2JSON(b)
 		if err != nil {
-			return fmt.Errorf("failed to evaluate jsonnet file: %w", err)
-		}
-		b := []byte(jsonStr)
-		if withEnv {
-			b, err = l.renderTemplate(b, filepath.Dir(path))
-			if err != nil {
-				return fmt.Errorf("failed to render template: %w", err)
-			}
+			return err
 		}
+		return decodeConfig(jsonExt, bs, strict, v)
+	case jsonExt, jsonnetExt:
 		dec := json.NewDecoder(bytes.NewReader(b))
 		if strict {
 			dec.DisallowUnknownFields()
@@ -141,6 +230,98 @@ func (l *ConfigLoader) load(path string, strict bool, withEnv bool, v any) error
 	}
 }
 
+// SetEnv sets the env of the overlay config, such as stefunny.prd.yaml for stefunny.yaml.
+func (l *ConfigLoader) SetEnv(env string) error {
+	if env != "" && !overlayEnvPattern.MatchString(env) {
+		return fmt.Errorf("invalid env `%s`: only alphanumeric, `-` and `_` are allowed", env)
+	}
+	l.env = env
+	return nil
+}
+
+// loadConfig loads the config file, with the overlay config of the env deep-merged and the overrides applied after templating.
+// The returned source is nil with the overlay, positions of the merged config are not of the files.
+func (l *ConfigLoader) loadConfig(path string, strict bool, withEnv bool, v any) (*configSource, error) {
+	ext := filepath.Ext(path)
+	schema := ConfigJSONSchema()
+	src, err := l.readFile(path, withEnv)
+	if err != nil {
+		return nil, err
+	}
+	var overrides []configOverride
+	if withEnv {
+		overrides = l.configOverrides()
+	}
+	if l.env == "" {
+		if err := decodeConfig(ext, src.rendered, strict, v); err != nil {
+			return nil, src.wrapDecodeError(err, schema)
+		}
+		if len(overrides) == 0 {
+			return src, nil
+		}
+	}
+	var merged any
+	if err := decodeConfig(ext, src.rendered, false, &merged); err != nil {
+		return nil, src.wrapDecodeError(err, schema)
+	}
+	var mergedFrom []string
+	if l.env != "" {
+		overlayPath := overlayConfigPath(path, l.env)
+		var overlay any
+		overlaySrc, err := l.readFile(overlayPath, withEnv)
+		if err != nil {
+			return nil, fmt.Errorf("overlay `%s`: %w", overlayPath, err)
+		}
+		if err := decodeConfig(ext, overlaySrc.rendered, false, &overlay); err != nil {
+			return nil, fmt.Errorf("overlay: %w", overlaySrc.wrapDecodeError(err, schema))
+		}
+		log.Printf("[debug] merge overlay config `%s`", overlayPath)
+		merged = mergeOverlay(merged, overlay)
+		mergedFrom = append(mergedFrom, overlayPath)
+	}
+	var applied []configOverride
+	if len(overrides) > 0 {
+		merged, applied, err = applyConfigOverrides(merged, overrides, schema)
+		if err != nil {
+			return nil, fmt.Errorf("override config: %w", err)
+		}
+		for _, o := range applied {
+			mergedFrom = append(mergedFrom, o.from)
+		}
+	}
+	if len(mergedFrom) == 0 {
+		// the overrides change nothing.
+		return src, nil
+	}
+	var b []byte
+	mergedExt := ext
+	switch ext {
+	case yamlExt, ymlExt:
+		b, err = yaml.Marshal(merged)
+	default:
+		b, err = json.Marshal(merged)
+		mergedExt = jsonExt
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged config: %w", err)
+	}
+	// without the overlay, the config is decoded over the one of the file, so positions of the file are still of the config except for the overridden keys.
+	if err := decodeConfig(mergedExt, b, strict, v); err != nil {
+		mergedSrc := &configSource{
+			path:      fmt.Sprintf("%s with %s", path, strings.Join(mergedFrom, ", ")),
+			ext:       mergedExt,
+			source:    b,
+			evaluated: true,
+			rendered:  b,
+		}
+		return nil, mergedSrc.wrapDecodeError(err, schema)
+	}
+	if l.env != "" {
+		return nil, nil
+	}
+	return src, nil
+}
+
 func newTemplateFuncEnv(envs *OrderdMap[string, string]) func(string, ...string) string {
 	return func(key string, args ...string) string {
 		keys := make([]string, 1, len(args)+1)
@@ -163,30 +344,30 @@ func newTemplateFuncEnv(envs *OrderdMap[string, string]) func(string, ...string)
 	}
 }
 
-func newTemplatefuncMustEnv(mustEnvs *OrderdMap[string, string], missingEnvs map[string]struct{}) func(string) string {
+func newTemplatefuncMustEnv(mustEnvs *OrderdMap[string, string], missingEnvs map[string]string) func(string) string {
 	if mustEnvs == nil {
 		mustEnvs = NewOrderdMap[string, string]()
 	}
 	if missingEnvs == nil {
-		missingEnvs = make(map[string]struct{})
+		missingEnvs = make(map[string]string)
 	}
 	return func(key string) string {
 		if v, ok := os.LookupEnv(key); ok {
 			mustEnvs.Set(key, v)
 			return v
 		}
-		missingEnvs[key] = struct{}{}
+		missingEnvs[key] = key
 		return ""
 	}
 }
 
-func (l *ConfigLoader) newTemplateFuncFile(base string, files *OrderdMap[string, string], missingFiles map[string]struct{}) func(string) (string, error) {
+func (l *ConfigLoader) newTemplateFuncFile(base string, files *OrderdMap[string, string], missingFiles map[string]string) func(string) (string, error) {
 	return func(path string) (string, error) {
 		target := resolvePath(base, path)
 		bs, err := os.ReadFile(target)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
-				missingFiles[target] = struct{}{}
+				missingFiles[target] = path
 				return "", nil
 			}
 			return "", err
@@ -196,7 +377,7 @@ func (l *ConfigLoader) newTemplateFuncFile(base string, files *OrderdMap[string,
 	}
 }
 
-func (l *ConfigLoader) newTemplateFuncTemplateFile(base string, files *OrderdMap[string, string], missingFiles map[string]struct{}) func(string) (string, error) {
+func (l *ConfigLoader) newTemplateFuncTemplateFile(base string, files *OrderdMap[string, string], missingFiles map[string]string) func(string) (string, error) {
 	f := l.newTemplateFuncFile(base, files, missingFiles)
 	return func(path string) (string, error) {
 		str, err := f(path)
@@ -223,6 +404,15 @@ func (l *ConfigLoader) newTemplateFuncTemplateFile(base string, files *OrderdMap
 }
 
 func (l *ConfigLoader) renderTemplate(bs []byte, loadingDir string) ([]byte, error) {
+	src := &configSource{source: bs, evaluated: true}
+	if err := l.renderSource(src, loadingDir); err != nil {
+		return nil, err
+	}
+	return src.rendered, nil
+}
+
+// renderSource renders the source as a template, errors point the position in the source if it has the path.
+func (l *ConfigLoader) renderSource(src *configSource, loadingDir string) error {
 	funcMap := make(template.FuncMap, len(l.funcMap))
 	for k, v := range l.funcMap {
 		funcMap[k] = v
@@ -239,14 +429,17 @@ func (l *ConfigLoader) renderTemplate(bs []byte, loadingDir string) ([]byte, err
 	if l.templateFiles == nil {
 		l.templateFiles = NewOrderdMap[string, string]()
 	}
-	missingFiles := make(map[string]struct{})
-	missingEnvs := make(map[string]struct{}, 0)
+	missingFiles := make(map[string]string)
+	missingEnvs := make(map[string]string)
 	if _, ok := funcMap["env"]; !ok {
 		funcMap["env"] = newTemplateFuncEnv(l.envs)
 	}
 	if _, ok := funcMap["must_env"]; !ok {
 		funcMap["must_env"] = newTemplatefuncMustEnv(l.mustEnvs, missingEnvs)
 	}
+	if _, ok := funcMap["var"]; !ok {
+		funcMap["var"] = newTemplateFuncVar(l.vars)
+	}
 	if _, ok := funcMap["json_escape"]; !ok {
 		funcMap["json_escape"] = jsonEscape
 	}
@@ -270,27 +463,28 @@ func (l *ConfigLoader) renderTemplate(bs []byte, loadingDir string) ([]byte, err
 	if _, ok := funcMap["template_file"]; !ok {
 		funcMap["template_file"] = l.newTemplateFuncTemplateFile(loadingDir, l.templateFiles, missingFiles)
 	}
-	tmpl, err := template.New("config").Funcs(funcMap).Parse(string(bs))
+	name := src.path
+	if name == "" {
+		name = "config"
+	}
+	tmpl, err := template.New(name).Funcs(funcMap).Parse(string(src.source))
 	if err != nil {
-		return nil, fmt.Errorf("template parse error: %w", err)
+		return src.templateError(fmt.Errorf("template parse error: %w", err))
 	}
+	root := tmpl.Tree.Root
+	segments := instrumentTemplate(src.source, root)
 	buf := new(bytes.Buffer)
 	if err := tmpl.Execute(buf, nil); err != nil {
-		return nil, fmt.Errorf("template execute error: %w", err)
+		return src.templateError(fmt.Errorf("template execute error: %w", err))
 	}
+	src.rendered, src.srcMap = stripTemplateMarkers(buf.Bytes(), segments)
 	if len(missingEnvs) > 0 {
-		for k := range missingEnvs {
-			log.Printf("[warn] environment variable `%s` is not defined", k)
-		}
-		return nil, fmt.Errorf("missing %d environment variables", len(missingEnvs))
+		return src.templateCallError(root, []string{"must_env"}, missingEnvs, "environment variable `%s` is not defined", "missing %d environment variables")
 	}
 	if len(missingFiles) > 0 {
-		for k := range missingFiles {
-			log.Printf("[warn] file `%s` is not found", k)
-		}
-		return nil, fmt.Errorf("missing %d files", len(missingFiles))
+		return src.templateCallError(root, []string{"file", "template_file"}, missingFiles, "file `%s` is not found", "missing %d files")
 	}
-	return buf.Bytes(), nil
+	return nil
 }
 
 func (l *ConfigLoader) Load(ctx context.Context, path string) (*Config, error) {
@@ -305,15 +499,28 @@ func (l *ConfigLoader) Load(ctx context.Context, path string) (*Config, error) {
 		cfg.StateMachine = &StateMachineConfig{}
 	}
 	cfg.StateMachine.Strict = true
-	if err := l.load(path, true, true, cfg); err != nil {
+	src, err := l.loadConfig(path, true, true, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 	if err := l.migrationForDeprecatedFields(ctx, cfg); err != nil {
 		return nil, fmt.Errorf("migration for deprecated fields: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		if src != nil {
+			err = src.wrapKeyPathErrors(err)
+		}
+		return nil, fmt.Errorf("config validate: %w", err)
+	}
 	if err := cfg.Restrict(); err != nil {
+		if src != nil {
+			err = src.wrapKeyPathError(err)
+		}
 		return nil, fmt.Errorf("config restrict:%w", err)
 	}
+	if src != nil {
+		src.warnEnumViolations(ConfigJSONSchema())
+	}
 	if err := cfg.ValidateVersion(Version); err != nil {
 		return nil, fmt.Errorf("config validate version:%w", err)
 	}
@@ -321,7 +528,11 @@ func (l *ConfigLoader) Load(ctx context.Context, path string) (*Config, error) {
 		return cfg, nil
 	}
 	if cfg.StateMachine.DefinitionPath == "" {
-		return nil, errors.New("state_machine.definition is required")
+		err := errors.New("state_machine.definition is required")
+		if src != nil {
+			err = src.wrapKeyPathError(err)
+		}
+		return nil, err
 	}
 	// cfg.StateMachine.Definition written definition file path
 	var definition json.RawMessage
@@ -373,19 +584,29 @@ func (l *ConfigLoader) setConfigPath(cfg *Config, path string) error {
 
 // pre load for tfstate path read
 func (l *ConfigLoader) preLoadForTemplateFuncs(ctx context.Context, cfg *Config, path string) error {
-	if err := l.load(path, false, false, cfg); err != nil {
+	if _, err := l.loadConfig(path, false, false, cfg); err != nil {
 		return err
 	}
-	bs, err := json.Marshal(cfg.TFState)
-	if err != nil {
-		return fmt.Errorf("tfstate marshal:%w", err)
+	if err := l.setVars(cfg); err != nil {
+		return fmt.Errorf("vars: %w", err)
 	}
-	bs, err = l.renderTemplate(bs, filepath.Dir(path))
-	if err != nil {
-		return fmt.Errorf("render template:%w", err)
+	if err := l.appendAWSTemplateFuncs(ctx, cfg); err != nil {
+		return fmt.Errorf("aws template funcs: %w", err)
+	}
+	if err := l.renderSection("jsonnet", &cfg.Jsonnet, filepath.Dir(path)); err != nil {
+		return err
 	}
-	if err := json.Unmarshal(bs, &cfg.TFState); err != nil {
-		return fmt.Errorf("tfstate unmarshal:%w", err)
+	if err := l.setJsonnetConfig(cfg.Jsonnet, filepath.Dir(path)); err != nil {
+		return fmt.Errorf("jsonnet: %w", err)
+	}
+	if err := l.renderSection("tfstate", &cfg.TFState, filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := l.renderSection("cfn", &cfg.CFn, filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := l.appendCFnTemplateFuncs(cfg); err != nil {
+		return err
 	}
 	for i, tfstate := range cfg.TFState {
 		if tfstate.Location == "" {
@@ -403,6 +624,22 @@ func (l *ConfigLoader) preLoadForTemplateFuncs(ctx context.Context, cfg *Config,
 	return nil
 }
 
+// renderSection renders the section of the config read without templates, such as tfstate, before the template functions of the section are appended.
+func (l *ConfigLoader) renderSection(name string, v any, dir string) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%s marshal:%w", name, err)
+	}
+	bs, err = l.renderTemplate(bs, dir)
+	if err != nil {
+		return fmt.Errorf("render template:%w", err)
+	}
+	if err := json.Unmarshal(bs, v); err != nil {
+		return fmt.Errorf("%s unmarshal:%w", name, err)
+	}
+	return nil
+}
+
 func (l *ConfigLoader) migrationForDeprecatedFields(ctx context.Context, cfg *Config) error {
 	// migration from old version: TODO delete v0.7.0
 	if cfg.StateMachine != nil && cfg.StateMachine.Logging != nil {
@@ -495,6 +732,11 @@ type Config struct {
 	Endpoints *EndpointsConfig `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
 
 	TFState []*TFStateConfig `yaml:"tfstate,omitempty" json:"tfstate,omitempty"`
+	CFn     []*CFnConfig     `yaml:"cfn,omitempty" json:"cfn,omitempty"`
+
+	Vars map[string]any `yaml:"vars,omitempty" json:"vars,omitempty"`
+
+	Jsonnet *JsonnetConfig `yaml:"jsonnet,omitempty" json:"jsonnet,omitempty"`
 
 	ConfigDir      string                     `yaml:"-" json:"-"`
 	ConfigFileName string                     `yaml:"-" json:"-"`
@@ -502,6 +744,9 @@ type Config struct {
 	MustEnvs       *OrderdMap[string, string] `yaml:"-" json:"-"`
 	Files          *OrderdMap[string, string] `yaml:"-" json:"-"`
 	TemplateFiles  *OrderdMap[string, string] `yaml:"-" json:"-"`
+	VarValues      *OrderdMap[string, string] `yaml:"-" json:"-"`
+	SecretValues   *OrderdMap[string, string] `yaml:"-" json:"-"`
+	AWSIdentity    *AWSIdentity               `yaml:"-" json:"-"`
 	//private field
 	mu                 sync.Mutex
 	versionConstraints gv.Constraints `yaml:"-,omitempty"`
@@ -513,12 +758,23 @@ type TFStateConfig struct {
 	Location   string `yaml:"location,omitempty" json:"location,omitempty"`
 }
 
+type JsonnetConfig struct {
+	JPath   []string          `yaml:"jpath,omitempty" json:"jpath,omitempty"`
+	ExtStr  map[string]string `yaml:"ext_str,omitempty" json:"ext_str,omitempty"`
+	ExtCode map[string]string `yaml:"ext_code,omitempty" json:"ext_code,omitempty"`
+}
+
+type CFnConfig struct {
+	FuncPrefix string `yaml:"func_prefix,omitempty" json:"func_prefix,omitempty"`
+	Stack      string `yaml:"stack,omitempty" json:"stack,omitempty"`
+}
+
 type StateMachineConfig struct {
 	KeysToSnakeCase[sfn.CreateStateMachineInput] `yaml:",inline" json:",inline"`
-	DefinitionPath                               string `yaml:"definition_path,omitempty" json:"definition_path,omitempty"`
+	DefinitionPath                               string `yaml:"definition_path,omitempty" json:"definition_path,omitempty" schema:"-"`
 
-	Logging *StateMachineLogging           `yaml:"-,omitempty"`
-	Tracing *sfntypes.TracingConfiguration `yaml:"-,omitempty"`
+	Logging *StateMachineLogging           `yaml:"-,omitempty" schema:"logging"`
+	Tracing *sfntypes.TracingConfiguration `yaml:"-,omitempty" schema:"tracing"`
 }
 
 type StateMachineLogging struct {
@@ -571,6 +827,10 @@ type EndpointsConfig struct {
 	STS            string `yaml:"sts,omitempty" json:"sts,omitempty"`
 	EventBridge    string `yaml:"eventbridge,omitempty" json:"event_bridge,omitempty"`
 	Scheduler      string `yaml:"scheduler,omitempty" json:"scheduler,omitempty"`
+	Pipes          string `yaml:"pipes,omitempty" json:"pipes,omitempty"`
+	SSM            string `yaml:"ssm,omitempty" json:"ssm,omitempty"`
+	SecretsManager string `yaml:"secretsmanager,omitempty" json:"secrets_manager,omitempty"`
+	CloudFormation string `yaml:"cloudformation,omitempty" json:"cloud_formation,omitempty"`
 }
 
 type ScheduleConfig struct {
@@ -584,10 +844,24 @@ type ScheduleConfig struct {
 type TriggerConfig struct {
 	Schedule []TriggerScheduleConfig `yaml:"schedule,omitempty" json:"schedule,omitempty"`
 	Event    []TriggerEventConfig    `yaml:"event,omitempty" json:"event,omitempty"`
+	Pipe     []TriggerPipeConfig     `yaml:"pipe,omitempty" json:"pipe,omitempty"`
+
+	ScheduleGroup []TriggerScheduleGroupConfig `yaml:"schedule_group,omitempty" json:"schedule_group,omitempty"`
+}
+
+// TriggerScheduleGroupConfig is the EventBridge Scheduler schedule group that stefunny creates and deletes.
+type TriggerScheduleGroupConfig struct {
+	Name string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Tags map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
 type TriggerScheduleConfig struct {
-	KeysToSnakeCase[scheduler.CreateScheduleInput] `yaml:",inline" json:",inline"`
+	KeysToSnakeCase[TriggerScheduleConfigInner] `yaml:",inline" json:",inline"`
+}
+
+type TriggerScheduleConfigInner struct {
+	scheduler.CreateScheduleInput `yaml:",inline"`
+	Qualifier                     *string `yaml:"Qualifier,omitempty" json:"Qualifier,omitempty"`
 }
 
 type TriggerEventConfig struct {
@@ -596,7 +870,14 @@ type TriggerEventConfig struct {
 
 type TriggerEventConfigInner struct {
 	eventbridge.PutRuleInput `yaml:",inline"`
-	Target                   eventbridgetypes.Target `yaml:"Target,omitempty" json:"Target,omitempty"`
+	Target                   eventbridgetypes.Target         `yaml:"Target,omitempty" json:"Target,omitempty"`
+	Targets                  []eventbridgetypes.Target       `yaml:"Targets,omitempty" json:"Targets,omitempty"`
+	Qualifier                *string                         `yaml:"Qualifier,omitempty" json:"Qualifier,omitempty"`
+	Archive                  *eventbridge.CreateArchiveInput `yaml:"Archive,omitempty" json:"Archive,omitempty"`
+}
+
+type TriggerPipeConfig struct {
+	KeysToSnakeCase[pipes.CreatePipeInput] `yaml:",inline" json:",inline"`
 }
 
 // Restrict restricts a configuration.
@@ -636,6 +917,31 @@ func (cfg *TriggerConfig) Restrict(stateMachineName string) error {
 			return fmt.Errorf("event[%d].%w", i, err)
 		}
 	}
+	for i, p := range cfg.Pipe {
+		if err := p.Restrict(i, stateMachineName); err != nil {
+			return fmt.Errorf("pipe[%d].%w", i, err)
+		}
+	}
+	groupNames := make(map[string]struct{}, len(cfg.ScheduleGroup))
+	for i, g := range cfg.ScheduleGroup {
+		if err := g.Restrict(); err != nil {
+			return fmt.Errorf("schedule_group[%d].%w", i, err)
+		}
+		if _, ok := groupNames[g.Name]; ok {
+			return fmt.Errorf("schedule_group[%d].name `%s` is duplicated", i, g.Name)
+		}
+		groupNames[g.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (cfg *TriggerScheduleGroupConfig) Restrict() error {
+	if cfg.Name == "" {
+		return errors.New("name is required")
+	}
+	if cfg.Name == defaultScheduleGroupName {
+		return fmt.Errorf("name `%s` is reserved by EventBridge Scheduler", defaultScheduleGroupName)
+	}
 	return nil
 }
 
@@ -655,9 +961,42 @@ func (cfg *TriggerEventConfig) Restrict(i int, stateMachineName string) error {
 	if cfg.Value.Target.Arn != nil {
 		return errors.New("target.arn is not allowed")
 	}
+	ids := map[string]struct{}{
+		coalesce(cfg.Value.Target.Id, aws.String(defaultEventBridgeTargetID)): {},
+	}
+	for j, target := range cfg.Value.Targets {
+		id := coalesce(target.Id)
+		if id == "" {
+			return fmt.Errorf("targets[%d].id is required", j)
+		}
+		if _, ok := ids[id]; ok {
+			return fmt.Errorf("targets[%d].id `%s` is duplicated", j, id)
+		}
+		ids[id] = struct{}{}
+		if coalesce(target.Arn) == "" {
+			return fmt.Errorf("targets[%d].arn is required", j)
+		}
+	}
 	if cfg.Value.State == "" {
 		cfg.Value.State = eventbridgetypes.RuleStateEnabled
 	}
+	if archive := cfg.Value.Archive; archive != nil {
+		if archive.EventSourceArn != nil {
+			return errors.New("archive.event_source_arn is not allowed")
+		}
+		if coalesce(archive.EventPattern) == "" {
+			archive.EventPattern = cfg.Value.EventPattern
+		}
+		if coalesce(archive.EventPattern) == "" {
+			return errors.New("archive requires event_pattern")
+		}
+		if coalesce(archive.ArchiveName) == "" {
+			archive.ArchiveName = cfg.Value.Name
+		}
+		if err := validateArchiveDescription(coalesce(cfg.Value.Name), coalesce(archive.Description)); err != nil {
+			return fmt.Errorf("archive.%w", err)
+		}
+	}
 	return nil
 }
 
@@ -677,6 +1016,9 @@ func (cfg *TriggerScheduleConfig) Restrict(i int, stateMachineName string) error
 	if coalesce(cfg.Value.ScheduleExpression) == "" {
 		return errors.New("schedule_expression is required")
 	}
+	if err := validateScheduleDescription(coalesce(cfg.Value.Description)); err != nil {
+		return err
+	}
 	if cfg.Value.Target == nil {
 		return nil
 	}
@@ -694,6 +1036,80 @@ func (cfg *TriggerScheduleConfig) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (cfg *TriggerPipeConfig) Restrict(i int, stateMachineName string) error {
+	if coalesce(cfg.Value.Name) == "" {
+		log.Printf("[warn] trigger.pipe[%d].name is empty. Use state_machine.name as name.", i)
+		cfg.Value.Name = aws.String(stateMachineName)
+	}
+	if coalesce(cfg.Value.Source) == "" {
+		return errors.New("source is required")
+	}
+	if coalesce(cfg.Value.RoleArn) == "" {
+		return errors.New("role_arn is required")
+	}
+	if coalesce(cfg.Value.Target) != "" {
+		return errors.New("target is not allowed")
+	}
+	if cfg.Value.DesiredState == "" {
+		cfg.Value.DesiredState = pipestypes.RequestedPipeStateRunning
+	}
+	return nil
+}
+
+// UnmarshalJSON keeps tag keys as is, KeysToSnakeCase would convert them to CamelCase.
+func (cfg *TriggerPipeConfig) UnmarshalJSON(b []byte) error {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	var tags map[string]string
+	if raw, ok := data["tags"]; ok {
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			return fmt.Errorf("tags unmarshal failed:%w", err)
+		}
+		delete(data, "tags")
+	}
+	replaced, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("replaced unmarshal failed:%w", err)
+	}
+	cfg.Strict = true
+	if err := json.Unmarshal(replaced, &cfg.KeysToSnakeCase); err != nil {
+		return err
+	}
+	cfg.Value.Tags = tags
+	return nil
+}
+
+func (cfg TriggerPipeConfig) MarshalJSON() ([]byte, error) {
+	data, err := cfg.marshalMap()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+func (cfg TriggerPipeConfig) MarshalYAML() (interface{}, error) {
+	return cfg.marshalMap()
+}
+
+func (cfg TriggerPipeConfig) marshalMap() (map[string]any, error) {
+	value := cfg.Value
+	value.Tags = nil
+	bs, err := NewKeysToSnakeCase(value).MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(bs, &data); err != nil {
+		return nil, err
+	}
+	if len(cfg.Value.Tags) > 0 {
+		data["tags"] = cfg.Value.Tags
+	}
+	return data, nil
+}
+
 func (cfg *Config) LoadAWSConfig(ctx context.Context) (aws.Config, error) {
 	cfg.mu.Lock()
 	defer cfg.mu.Unlock()
@@ -714,6 +1130,11 @@ func (cfg *Config) LoadAWSConfig(ctx context.Context) (aws.Config, error) {
 	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err == nil {
 		log.Printf("[debug] caller identity: %s", *identity.Arn)
+		if id, err := newAWSIdentity(identity, awsCfg.Region); err == nil {
+			cfg.AWSIdentity = id
+		} else {
+			log.Printf("[debug] %s", err)
+		}
 	}
 	cfg.awsCfg = &awsCfg
 	return awsCfg, nil
@@ -756,10 +1177,16 @@ func (cfg *Config) NewEventBridgeRules() EventBridgeRules {
 	rules := make(EventBridgeRules, 0, len(cfg.Trigger.Event))
 	for i, e := range cfg.Trigger.Event {
 		rule := &EventBridgeRule{
-			PutRuleInput:    e.Value.PutRuleInput,
-			Target:          e.Value.Target,
-			ConfigFilePath:  aws.String(filepath.Join(cfg.ConfigDir, cfg.ConfigFileName)),
-			ConfigFileIndex: i,
+			PutRuleInput:      e.Value.PutRuleInput,
+			Target:            e.Value.Target,
+			AdditionalTargets: e.Value.Targets,
+			Qualifier:         e.Value.Qualifier,
+			ConfigFilePath:    aws.String(filepath.Join(cfg.ConfigDir, cfg.ConfigFileName)),
+			ConfigFileIndex:   i,
+		}
+		if e.Value.Archive != nil {
+			archive := *e.Value.Archive
+			rule.Archive = &archive
 		}
 		if rule.Target.RoleArn == nil && e.Value.RoleArn != nil {
 			rule.Target.RoleArn = e.Value.RoleArn
@@ -781,10 +1208,12 @@ func (cfg *Config) NewSchedules() Schedules {
 	schedules := make(Schedules, 0, len(cfg.Trigger.Schedule))
 	for i, s := range cfg.Trigger.Schedule {
 		schedule := &Schedule{
-			CreateScheduleInput: s.Value,
+			CreateScheduleInput: s.Value.CreateScheduleInput,
+			Qualifier:           s.Value.Qualifier,
 			ConfigFilePath:      aws.String(filepath.Join(cfg.ConfigDir, cfg.ConfigFileName)),
 			ConfigFileIndex:     i,
 		}
+		schedule.AppendManagedByMarker()
 		if schedule.HasItPassed() {
 			log.Printf("[warn] schedule %s has passed, ignore this schedule", coalesce(schedule.Name))
 			continue
@@ -795,6 +1224,60 @@ func (cfg *Config) NewSchedules() Schedules {
 	return schedules
 }
 
+func (cfg *Config) NewScheduleGroups() ScheduleGroups {
+	if cfg.Trigger == nil {
+		return ScheduleGroups{}
+	}
+	groups := make(ScheduleGroups, 0, len(cfg.Trigger.ScheduleGroup))
+	for _, g := range cfg.Trigger.ScheduleGroup {
+		group := &ScheduleGroup{
+			Name: g.Name,
+			Tags: make(map[string]string, len(cfg.Tags)+len(g.Tags)+2),
+		}
+		for k, v := range cfg.Tags {
+			group.Tags[k] = v
+		}
+		for k, v := range g.Tags {
+			group.Tags[k] = v
+		}
+		group.Tags[tagManagedBy] = appName
+		group.Tags[tagManagedStateMachine] = cfg.StateMachineName()
+		groups = append(groups, group)
+	}
+	sort.Sort(groups)
+	return groups
+}
+
+func (cfg *Config) NewPipes() Pipes {
+	if cfg.Trigger == nil {
+		return Pipes{}
+	}
+	tags := make(map[string]string)
+	for _, tag := range cfg.StateMachine.Value.Tags {
+		tags[coalesce(tag.Key)] = coalesce(tag.Value)
+	}
+	for k, v := range cfg.Tags {
+		tags[k] = v
+	}
+	tags[tagManagedBy] = appName
+	result := make(Pipes, 0, len(cfg.Trigger.Pipe))
+	for i, p := range cfg.Trigger.Pipe {
+		pipe := &Pipe{
+			CreatePipeInput: p.Value,
+			ConfigFilePath:  aws.String(filepath.Join(cfg.ConfigDir, cfg.ConfigFileName)),
+			ConfigFileIndex: i,
+		}
+		pipe.Tags = make(map[string]string, len(p.Value.Tags)+len(tags))
+		for k, v := range p.Value.Tags {
+			pipe.Tags[k] = v
+		}
+		pipe.AppendTags(tags)
+		result = append(result, pipe)
+	}
+	sort.Sort(result)
+	return result
+}
+
 func (cfg *StateMachineConfig) SetDetinitionPath(path string) {
 	cfg.DefinitionPath = path
 }
@@ -940,6 +1423,8 @@ func NewDefaultConfig() *Config {
 		MustEnvs:      NewOrderdMap[string, string](),
 		Files:         NewOrderdMap[string, string](),
 		TemplateFiles: NewOrderdMap[string, string](),
+		VarValues:     NewOrderdMap[string, string](),
+		SecretValues:  NewOrderdMap[string, string](),
 	}
 }
 
@@ -983,6 +1468,16 @@ func (cfg *Config) NewStepFunctionsClientFromConfig(awsCfg aws.Config) *sfn.Clie
 	return sfn.NewFromConfig(awsCfg, opts...)
 }
 
+func (cfg *Config) NewPipesClientFromConfig(awsCfg aws.Config) *pipes.Client {
+	var opts []func(*pipes.Options)
+	if cfg.Endpoints != nil && cfg.Endpoints.Pipes != "" {
+		opts = append(opts, func(o *pipes.Options) {
+			o.BaseEndpoint = aws.String(cfg.Endpoints.Pipes)
+		})
+	}
+	return pipes.NewFromConfig(awsCfg, opts...)
+}
+
 func (cfg *Config) NewSchedulerClientFromConfig(awsCfg aws.Config) *scheduler.Client {
 	var opts []func(*scheduler.Options)
 	if cfg.Endpoints != nil && cfg.Endpoints.Scheduler != "" {
@@ -992,3 +1487,33 @@ func (cfg *Config) NewSchedulerClientFromConfig(awsCfg aws.Config) *scheduler.Cl
 	}
 	return scheduler.NewFromConfig(awsCfg, opts...)
 }
+
+func (cfg *Config) NewSSMClientFromConfig(awsCfg aws.Config) *ssm.Client {
+	var opts []func(*ssm.Options)
+	if cfg.Endpoints != nil && cfg.Endpoints.SSM != "" {
+		opts = append(opts, func(o *ssm.Options) {
+			o.BaseEndpoint = aws.String(cfg.Endpoints.SSM)
+		})
+	}
+	return ssm.NewFromConfig(awsCfg, opts...)
+}
+
+func (cfg *Config) NewSecretsManagerClientFromConfig(awsCfg aws.Config) *secretsmanager.Client {
+	var opts []func(*secretsmanager.Options)
+	if cfg.Endpoints != nil && cfg.Endpoints.SecretsManager != "" {
+		opts = append(opts, func(o *secretsmanager.Options) {
+			o.BaseEndpoint = aws.String(cfg.Endpoints.SecretsManager)
+		})
+	}
+	return secretsmanager.NewFromConfig(awsCfg, opts...)
+}
+
+func (cfg *Config) NewCloudFormationClientFromConfig(awsCfg aws.Config) *cloudformation.Client {
+	var opts []func(*cloudformation.Options)
+	if cfg.Endpoints != nil && cfg.Endpoints.CloudFormation != "" {
+		opts = append(opts, func(o *cloudformation.Options) {
+			o.BaseEndpoint = aws.String(cfg.Endpoints.CloudFormation)
+		})
+	}
+	return cloudformation.NewFromConfig(awsCfg, opts...)
+}
@@ -0,0 +1,643 @@
+package stefunny
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+// ConfigError is an error of the config or definition file, with the position in the file.
+type ConfigError struct {
+	Path    string
+	Line    int // 1-based, 0 if the position is unknown
+	Column  int
+	KeyPath string // such as state_machine.type, empty if unknown
+	Excerpt string
+	Err     error
+}
+
+func (e *ConfigError) Error() string {
+	loc := e.Path
+	if e.Line > 0 {
+		loc = fmt.Sprintf("%s:%d:%d", e.Path, e.Line, e.Column)
+	}
+	msg := e.Err.Error()
+	if e.KeyPath != "" && !strings.HasPrefix(msg, e.KeyPath) {
+		msg = e.KeyPath + ": " + msg
+	}
+	if e.Excerpt == "" {
+		return loc + ": " + msg
+	}
+	return loc + ": " + msg + "\n" + e.Excerpt
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configSource is a config or definition file being loaded, keeps what is needed to point the position of errors.
+type configSource struct {
+	path      string // empty if not a file, such as a part of the config
+	ext       string
+	source    []byte
+	evaluated bool // the source is evaluated from Jsonnet, its positions are not of the file
+	rendered  []byte
+	srcMap    *templateSourceMap // nil if not rendered
+}
+
+// errorAt returns the error at the line and column of the rendered.
+func (s *configSource) errorAt(line, column int, keyPath string, err error) error {
+	if s.srcMap != nil && line > 0 {
+		offset := s.srcMap.sourceOffset(lineColumnToOffset(s.rendered, line, column))
+		line, column = offsetToLineColumn(s.source, offset)
+	}
+	return s.errorAtSource(line, column, keyPath, err)
+}
+
+// errorAtSource returns the error at the line and column of the source.
+func (s *configSource) errorAtSource(line, column int, keyPath string, err error) error {
+	if s.path == "" {
+		return err
+	}
+	cerr := &ConfigError{
+		Path:    s.path,
+		KeyPath: keyPath,
+		Err:     err,
+	}
+	if s.evaluated || line <= 0 {
+		return cerr
+	}
+	cerr.Line, cerr.Column = line, column
+	cerr.Excerpt = sourceExcerpt(s.source, line, column)
+	return cerr
+}
+
+// templateError points the position of the error of text/template.
+func (s *configSource) templateError(err error) error {
+	line, column, ok := templateErrorLineColumn(err)
+	if !ok {
+		return s.errorAtSource(0, 0, "", err)
+	}
+	msg := templateErrorPosition.ReplaceAllString(err.Error(), "")
+	return s.errorAtSource(line, column, "", errors.New(msg))
+}
+
+// templateCallError points the first call of the template functions with the missing arguments.
+// missing is keyed by what is reported, and the value is the argument of the call.
+func (s *configSource) templateCallError(root *parse.ListNode, funcNames []string, missing map[string]string, warnFormat string, errFormat string) error {
+	keys := make([]string, 0, len(missing))
+	for k := range missing {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	first := -1
+	for _, k := range keys {
+		offset := -1
+		for _, name := range funcNames {
+			if o, ok := findTemplateCall(root, name, missing[k]); ok && (offset < 0 || o < offset) {
+				offset = o
+			}
+		}
+		if offset < 0 || s.path == "" || s.evaluated {
+			log.Printf("[warn] "+warnFormat, k)
+			continue
+		}
+		line, column := offsetToLineColumn(s.source, offset)
+		log.Printf("[warn] %s:%d:%d: "+warnFormat, s.path, line, column, k)
+		if first < 0 || offset < first {
+			first = offset
+		}
+	}
+	err := fmt.Errorf(errFormat, len(missing))
+	if first < 0 {
+		return s.errorAtSource(0, 0, "", err)
+	}
+	line, column := offsetToLineColumn(s.source, first)
+	return s.errorAtSource(line, column, "", err)
+}
+
+// document returns the YAML AST of the rendered to find keys, and the source that has the positions of the AST.
+// TOML is converted to JSON, so only keys are found without positions.
+func (s *configSource) document() (ast.Node, *configSource) {
+	view := s
+	if s.ext == tomlExt {
+		bs, err := TOML2JSON(s.rendered)
+		if err != nil {
+			return nil, s
+		}
+		view = &configSource{path: s.path, ext: jsonExt, source: bs, evaluated: true, rendered: bs}
+	}
+	file, err := parser.ParseBytes(view.rendered, 0)
+	if err != nil || len(file.Docs) == 0 {
+		return nil, s
+	}
+	return file.Docs[0].Body, view
+}
+
+var goccyErrorPositionPrefix = regexp.MustCompile(`^\[\d+:\d+\] `)
+
+// wrapDecodeError points the position of the decode error, schema is used to find the key that the error is raised from UnmarshalJSON.
+func (s *configSource) wrapDecodeError(err error, schema *JSONSchema) error {
+	var tomlErr toml.ParseError
+	if errors.As(err, &tomlErr) {
+		return s.errorAt(tomlErr.Position.Line, tomlErr.Position.Col, "", errors.New(tomlErr.Message))
+	}
+	if tk := goccyErrorToken(err); tk != nil && tk.Position != nil {
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		msg = goccyErrorPositionPrefix.ReplaceAllString(msg, "")
+		return s.errorAt(tk.Position.Line, tk.Position.Column, "", errors.New(msg))
+	}
+	if schema != nil {
+		if doc, view := s.document(); doc != nil {
+			if v := matchSchemaViolation(findSchemaViolations(schema, doc, ""), err); v != nil {
+				log.Printf("[debug] %s", err)
+				return view.errorAt(v.token.Position.Line, v.token.Position.Column, v.keyPath, errors.New(v.message))
+			}
+		}
+	}
+	return s.errorAtSource(0, 0, "", err)
+}
+
+// warnEnumViolations warns values not in the enum of the schema, they are not rejected because AWS may accept them.
+func (s *configSource) warnEnumViolations(schema *JSONSchema) {
+	doc, view := s.document()
+	if doc == nil {
+		return
+	}
+	for _, v := range findSchemaViolations(schema, doc, "") {
+		if !v.enum {
+			continue
+		}
+		err := view.errorAt(v.token.Position.Line, v.token.Position.Column, v.keyPath, errors.New(v.message))
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		log.Printf("[warn] %s", msg)
+	}
+}
+
+var (
+	errorKeyPath        = regexp.MustCompile(`^[a-z0-9_]+(\[\d+\])?(\.[a-z0-9_]+(\[\d+\])?)*`)
+	errorKeyPathSegment = regexp.MustCompile(`[a-z0-9_]+|\[\d+\]`)
+)
+
+// wrapKeyPathError points the position of the error that starts with the key path, such as errors of Config.Restrict.
+// The parent key is pointed if the key is not found, e.g. for `state_machine.name is required`.
+func (s *configSource) wrapKeyPathError(err error) error {
+	keyPath := errorKeyPath.FindString(err.Error())
+	if keyPath == "" {
+		return s.errorAtSource(0, 0, "", err)
+	}
+	doc, view := s.document()
+	if doc == nil {
+		return s.errorAtSource(0, 0, "", err)
+	}
+	segments := errorKeyPathSegment.FindAllString(keyPath, -1)
+	for n := len(segments); n > 0; n-- {
+		key, value, ok := lookupKeyPath(doc, segments[:n])
+		if !ok {
+			continue
+		}
+		tk := key
+		if _, scalar := value.(ast.ScalarNode); scalar && n == len(segments) {
+			// the value is pointed for errors such as invalid enum values.
+			tk = value.GetToken()
+		}
+		if tk == nil || tk.Position == nil {
+			break
+		}
+		return view.errorAt(tk.Position.Line, tk.Position.Column, "", err)
+	}
+	return s.errorAtSource(0, 0, "", err)
+}
+
+// wrapKeyPathErrors points the position of each error joined by errors.Join.
+func (s *configSource) wrapKeyPathErrors(err error) error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return s.wrapKeyPathError(err)
+	}
+	errs := joined.Unwrap()
+	wrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		wrapped = append(wrapped, s.wrapKeyPathError(err))
+	}
+	return errors.Join(wrapped...)
+}
+
+// lookupKeyPath returns the token of the key and the value node of the key path segments, such as [trigger schedule [0] name].
+func lookupKeyPath(node ast.Node, segments []string) (*token.Token, ast.Node, bool) {
+	var key *token.Token
+	for _, seg := range segments {
+		node = unwrapYAMLNode(node)
+		if strings.HasPrefix(seg, "[") {
+			i, err := strconv.Atoi(strings.Trim(seg, "[]"))
+			seq, ok := node.(*ast.SequenceNode)
+			if err != nil || !ok || i >= len(seq.Values) {
+				return nil, nil, false
+			}
+			node = seq.Values[i]
+			key = node.GetToken()
+			continue
+		}
+		found := false
+		for _, mv := range yamlMappingValues(node) {
+			if mv.Key.GetToken().Value == seg {
+				key, node, found = mv.Key.GetToken(), mv.Value, true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, false
+		}
+	}
+	return key, unwrapYAMLNode(node), key != nil
+}
+
+func unwrapYAMLNode(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+		case *ast.TagNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
+
+func yamlMappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	}
+	return nil
+}
+
+func goccyErrorToken(err error) *token.Token {
+	var (
+		syntaxErr     *yaml.SyntaxError
+		typeErr       *yaml.TypeError
+		overflowErr   *yaml.OverflowError
+		duplicateErr  *yaml.DuplicateKeyError
+		unknownErr    *yaml.UnknownFieldError
+		unexpectedErr *yaml.UnexpectedNodeTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		return syntaxErr.Token
+	case errors.As(err, &typeErr):
+		return typeErr.Token
+	case errors.As(err, &overflowErr):
+		return overflowErr.Token
+	case errors.As(err, &duplicateErr):
+		return duplicateErr.Token
+	case errors.As(err, &unknownErr):
+		return unknownErr.Token
+	case errors.As(err, &unexpectedErr):
+		return unexpectedErr.Token
+	}
+	return nil
+}
+
+type schemaViolation struct {
+	keyPath string
+	message string
+	token   *token.Token
+	unknown string // the unknown key
+	enum    bool   // the value is not one of the enum, AWS may accept values that the SDK does not know yet
+}
+
+var jsonUnknownFieldError = regexp.MustCompile(`unknown field "([^"]+)"`)
+
+// matchSchemaViolation returns the violation that causes the decode error, or the first violation if unsure.
+func matchSchemaViolation(violations []*schemaViolation, err error) *schemaViolation {
+	if len(violations) == 0 {
+		return nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	unknownField := jsonUnknownFieldError.FindStringSubmatch(err.Error())
+	violations = slices.DeleteFunc(slices.Clone(violations), func(v *schemaViolation) bool {
+		return v.enum
+	})
+	if len(violations) == 0 {
+		return nil
+	}
+	for _, v := range violations {
+		switch {
+		case errors.As(err, &typeErr):
+			if v.unknown == "" && !v.enum {
+				return v
+			}
+		case unknownField != nil:
+			if v.unknown == unknownField[1] || strings.EqualFold(SnakeToCamel(v.unknown), unknownField[1]) {
+				return v
+			}
+		}
+	}
+	return violations[0]
+}
+
+// findSchemaViolations returns unknown keys, type mismatches and values not in the enum of the node.
+func findSchemaViolations(s *JSONSchema, node ast.Node, keyPath string) []*schemaViolation {
+	node = unwrapYAMLNode(node)
+	if s == nil || node == nil {
+		return nil
+	}
+	switch node.(type) {
+	case *ast.AliasNode, *ast.NullNode:
+		return nil
+	}
+	mismatch := func(expected string) []*schemaViolation {
+		return []*schemaViolation{{
+			keyPath: keyPath,
+			message: fmt.Sprintf("expected %s, but got %s", expected, strings.ToLower(node.Type().String())),
+			token:   node.GetToken(),
+		}}
+	}
+	var violations []*schemaViolation
+	switch s.Type {
+	case "object":
+		values := yamlMappingValues(node)
+		if values == nil {
+			return mismatch("mapping")
+		}
+		for _, mv := range values {
+			key := mv.Key.GetToken().Value
+			childPath := key
+			if keyPath != "" {
+				childPath = keyPath + "." + key
+			}
+			child, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties == false {
+					violations = append(violations, &schemaViolation{
+						keyPath: childPath,
+						message: fmt.Sprintf("unknown key `%s`", key),
+						token:   mv.Key.GetToken(),
+						unknown: key,
+					})
+					continue
+				}
+				child, _ = s.AdditionalProperties.(*JSONSchema)
+			}
+			violations = append(violations, findSchemaViolations(child, mv.Value, childPath)...)
+		}
+	case "array":
+		seq, ok := node.(*ast.SequenceNode)
+		if !ok {
+			return mismatch("sequence")
+		}
+		for i, item := range seq.Values {
+			violations = append(violations, findSchemaViolations(s.Items, item, fmt.Sprintf("%s[%d]", keyPath, i))...)
+		}
+	case "string":
+		switch node.Type() {
+		case ast.StringType, ast.LiteralType:
+		default:
+			return mismatch("string")
+		}
+		if value := node.GetToken().Value; len(s.Enum) > 0 && !slices.Contains(s.Enum, value) {
+			return []*schemaViolation{{
+				keyPath: keyPath,
+				message: fmt.Sprintf("`%s` is not one of %s", value, strings.Join(s.Enum, ", ")),
+				token:   node.GetToken(),
+				enum:    true,
+			}}
+		}
+	case "integer":
+		if node.Type() != ast.IntegerType {
+			return mismatch("integer")
+		}
+	case "number":
+		switch node.Type() {
+		case ast.IntegerType, ast.FloatType:
+		default:
+			return mismatch("number")
+		}
+	case "boolean":
+		if node.Type() != ast.BoolType {
+			return mismatch("boolean")
+		}
+	}
+	return violations
+}
+
+// templateSourceMap maps offsets of the rendered template to the source.
+type templateSourceMap struct {
+	segments []templateSegment
+}
+
+type templateSegment struct {
+	out  int  // offset of the rendered
+	src  int  // offset of the source
+	text bool // text is rendered as is, other nodes are mapped to the start of the action
+}
+
+func (m *templateSourceMap) sourceOffset(out int) int {
+	i := sort.Search(len(m.segments), func(i int) bool {
+		return m.segments[i].out > out
+	}) - 1
+	if i < 0 {
+		return out
+	}
+	seg := m.segments[i]
+	if !seg.text {
+		return seg.src
+	}
+	return seg.src + out - seg.out
+}
+
+const templateMarker = '\x00'
+
+// instrumentTemplate inserts markers before the nodes, to know which node renders each part of the output.
+func instrumentTemplate(src []byte, list *parse.ListNode) []templateSegment {
+	var segments []templateSegment
+	var walk func(list *parse.ListNode)
+	walk = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+		nodes := make([]parse.Node, 0, len(list.Nodes)*2)
+		for _, node := range list.Nodes {
+			seg := templateSegment{src: int(node.Position())}
+			if text, ok := node.(*parse.TextNode); ok {
+				seg.text = true
+				// the position is before the spaces trimmed by `-}}`
+				if i := bytes.Index(src[min(seg.src, len(src)):], text.Text); i > 0 {
+					seg.src += i
+				}
+			}
+			marker := fmt.Sprintf("%c%d%c", templateMarker, len(segments), templateMarker)
+			segments = append(segments, seg)
+			nodes = append(nodes, &parse.TextNode{NodeType: parse.NodeText, Pos: node.Position(), Text: []byte(marker)}, node)
+			switch n := node.(type) {
+			case *parse.IfNode:
+				walk(n.List)
+				walk(n.ElseList)
+			case *parse.RangeNode:
+				walk(n.List)
+				walk(n.ElseList)
+			case *parse.WithNode:
+				walk(n.List)
+				walk(n.ElseList)
+			}
+		}
+		list.Nodes = nodes
+	}
+	walk(list)
+	return segments
+}
+
+// stripTemplateMarkers removes the markers from the output, and returns the source map.
+func stripTemplateMarkers(out []byte, segments []templateSegment) ([]byte, *templateSourceMap) {
+	m := &templateSourceMap{}
+	buf := make([]byte, 0, len(out))
+	for {
+		i := bytes.IndexByte(out, templateMarker)
+		if i < 0 {
+			buf = append(buf, out...)
+			break
+		}
+		buf = append(buf, out[:i]...)
+		out = out[i+1:]
+		j := bytes.IndexByte(out, templateMarker)
+		if j < 0 {
+			buf = append(buf, out...)
+			break
+		}
+		if id, err := strconv.Atoi(string(out[:j])); err == nil && id < len(segments) {
+			seg := segments[id]
+			seg.out = len(buf)
+			m.segments = append(m.segments, seg)
+		}
+		out = out[j+1:]
+	}
+	return buf, m
+}
+
+// findTemplateCall returns the source offset of the first call of the template function with the argument.
+func findTemplateCall(list *parse.ListNode, funcName string, arg string) (int, bool) {
+	var found parse.Pos = -1
+	var walkPipe func(pipe *parse.PipeNode)
+	var walk func(list *parse.ListNode)
+	walkPipe = func(pipe *parse.PipeNode) {
+		if pipe == nil {
+			return
+		}
+		for _, cmd := range pipe.Cmds {
+			for i, a := range cmd.Args {
+				switch a := a.(type) {
+				case *parse.IdentifierNode:
+					if a.Ident != funcName || i+1 >= len(cmd.Args) {
+						continue
+					}
+					if s, ok := cmd.Args[i+1].(*parse.StringNode); ok && s.Text == arg && (found < 0 || a.Pos < found) {
+						found = a.Pos
+					}
+				case *parse.PipeNode:
+					walkPipe(a)
+				}
+			}
+		}
+	}
+	walk = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+		for _, node := range list.Nodes {
+			switch n := node.(type) {
+			case *parse.ActionNode:
+				walkPipe(n.Pipe)
+			case *parse.IfNode:
+				walkPipe(n.Pipe)
+				walk(n.List)
+				walk(n.ElseList)
+			case *parse.RangeNode:
+				walkPipe(n.Pipe)
+				walk(n.List)
+				walk(n.ElseList)
+			case *parse.WithNode:
+				walkPipe(n.Pipe)
+				walk(n.List)
+				walk(n.ElseList)
+			case *parse.TemplateNode:
+				walkPipe(n.Pipe)
+			}
+		}
+	}
+	walk(list)
+	return int(found), found >= 0
+}
+
+var templateErrorPosition = regexp.MustCompile(`template: [^\n]*?:(\d+)(?::(\d+))?: `)
+
+// templateErrorLineColumn returns the position in the error of text/template, such as `template: stefunny.yaml:3:12: executing ...`.
+func templateErrorLineColumn(err error) (int, int, bool) {
+	m := templateErrorPosition.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, 0, false
+	}
+	line, _ := strconv.Atoi(m[1])
+	column := 1
+	if m[2] != "" {
+		column, _ = strconv.Atoi(m[2])
+	}
+	return line, column, true
+}
+
+func offsetToLineColumn(b []byte, offset int) (int, int) {
+	offset = max(0, min(offset, len(b)))
+	line := bytes.Count(b[:offset], []byte("\n")) + 1
+	column := offset - (bytes.LastIndexByte(b[:offset], '\n') + 1) + 1
+	return line, column
+}
+
+func lineColumnToOffset(b []byte, line, column int) int {
+	offset := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(b[offset:], '\n')
+		if i < 0 {
+			return len(b)
+		}
+		offset += i + 1
+	}
+	return min(offset+column-1, len(b))
+}
+
+const sourceExcerptContextLines = 2
+
+// sourceExcerpt returns the lines around the position, with a caret at the column.
+func sourceExcerpt(b []byte, line, column int) string {
+	lines := strings.Split(string(b), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	from := max(1, line-sourceExcerptContextLines)
+	width := len(strconv.Itoa(line))
+	var sb strings.Builder
+	for l := from; l <= line; l++ {
+		marker := "  "
+		if l == line {
+			marker = "> "
+		}
+		fmt.Fprintf(&sb, "%s%*d | %s\n", marker, width, l, lines[l-1])
+	}
+	fmt.Fprintf(&sb, "  %s | %s^", strings.Repeat(" ", width), strings.Repeat(" ", max(0, column-1)))
+	return sb.String()
+}
@@ -0,0 +1,213 @@
+package stefunny
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// stefunnyLibsonnet is the helper library of ASL, importable by `import 'stefunny.libsonnet'` without the file.
+//
+//go:embed stefunny.libsonnet
+var stefunnyLibsonnet string
+
+const stefunnyLibsonnetName = "stefunny.libsonnet"
+
+// the contents must be the same for the same path, Jsonnet caches imports by them.
+var stefunnyLibsonnetContents = jsonnet.MakeContents(stefunnyLibsonnet)
+
+// jsonnetImporter imports files from the library paths, and the embedded stefunny.libsonnet if it is not found in them.
+type jsonnetImporter struct {
+	files *jsonnet.FileImporter
+}
+
+func newJsonnetImporter(jpaths []string) *jsonnetImporter {
+	return &jsonnetImporter{
+		files: &jsonnet.FileImporter{JPaths: jpaths},
+	}
+}
+
+func (i *jsonnetImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	contents, foundAt, err := i.files.Import(importedFrom, importedPath)
+	if err != nil && importedPath == stefunnyLibsonnetName {
+		return stefunnyLibsonnetContents, "<embedded>/" + stefunnyLibsonnetName, nil
+	}
+	return contents, foundAt, err
+}
+
+// SetJsonnetTemplate sets whether Jsonnet files are rendered as templates after the evaluation, it is enabled by default.
+// Disable it to use `{{` in Jsonnet strings, the native functions are available instead.
+func (l *ConfigLoader) SetJsonnetTemplate(enabled bool) {
+	l.disableJsonnetTemplate = !enabled
+}
+
+// AppendJPath appends the library paths of Jsonnet, such as --jpath. They take precedence over jsonnet.jpath of the config.
+func (l *ConfigLoader) AppendJPath(paths ...string) {
+	l.jpaths = append(l.jpaths, paths...)
+	l.vm.Importer(newJsonnetImporter(l.jpaths))
+}
+
+// setJsonnetConfig sets the jsonnet section of the config to the VM, the jpath is relative to the config.
+// ext_str and ext_code given by NewConfigLoader, such as --ext-str, take precedence.
+func (l *ConfigLoader) setJsonnetConfig(cfg *JsonnetConfig, dir string) error {
+	if cfg == nil {
+		return nil
+	}
+	jpaths := make([]string, 0, len(cfg.JPath)+len(l.jpaths))
+	for _, path := range cfg.JPath {
+		if path == "" {
+			return errors.New("jpath is empty")
+		}
+		jpaths = append(jpaths, resolvePath(dir, path))
+	}
+	// the last path has the highest priority.
+	jpaths = append(jpaths, l.jpaths...)
+	l.vm.Importer(newJsonnetImporter(jpaths))
+	for key, value := range cfg.ExtStr {
+		if _, ok := l.extStr[key]; ok {
+			log.Printf("[debug] jsonnet.ext_str `%s` is overridden by --ext-str", key)
+			continue
+		}
+		l.vm.ExtVar(key, value)
+	}
+	for key, value := range cfg.ExtCode {
+		if _, ok := l.extCode[key]; ok {
+			log.Printf("[debug] jsonnet.ext_code `%s` is overridden by --ext-code", key)
+			continue
+		}
+		l.vm.ExtCode(key, value)
+	}
+	return nil
+}
+
+// evaluateJsonnet evaluates the Jsonnet file, the native functions resolve values only if resolve is true as templates are rendered.
+func (l *ConfigLoader) evaluateJsonnet(path string, resolve bool) (string, error) {
+	l.nativeDir = filepath.Dir(path)
+	l.nativeResolve = resolve
+	defer func() {
+		l.nativeDir = ""
+		l.nativeResolve = false
+	}()
+	return l.vm.EvaluateFile(path)
+}
+
+// registerNativeFunctions registers the native functions of Jsonnet, such as std.native('env')('NAME', 'default').
+// They record the values as the template functions do, for templateize of pull.
+func (l *ConfigLoader) registerNativeFunctions() {
+	natives := []*jsonnet.NativeFunction{
+		{
+			Name:   "env",
+			Params: ast.Identifiers{"name", "default"},
+			Func: func(args []any) (any, error) {
+				name, def, err := nativeStringArgs2(args)
+				if err != nil {
+					return nil, err
+				}
+				return newTemplateFuncEnv(l.envs)(name, def), nil
+			},
+		},
+		{
+			Name:   "must_env",
+			Params: ast.Identifiers{"name"},
+			Func: func(args []any) (any, error) {
+				name, err := nativeStringArg(args)
+				if err != nil {
+					return nil, err
+				}
+				v, ok := os.LookupEnv(name)
+				if !ok {
+					if !l.nativeResolve {
+						return "", nil
+					}
+					return nil, fmt.Errorf("environment variable `%s` is not defined", name)
+				}
+				l.mustEnvs.Set(name, v)
+				return v, nil
+			},
+		},
+		{
+			Name:   "file",
+			Params: ast.Identifiers{"path"},
+			Func: func(args []any) (any, error) {
+				path, err := nativeStringArg(args)
+				if err != nil {
+					return nil, err
+				}
+				bs, err := os.ReadFile(resolvePath(l.nativeDir, path))
+				if err != nil {
+					if errors.Is(err, os.ErrNotExist) && !l.nativeResolve {
+						return "", nil
+					}
+					return nil, err
+				}
+				l.files.Set(path, string(bs))
+				return string(bs), nil
+			},
+		},
+		l.nativeTemplateFunc("tfstate", "address"),
+		l.nativeTemplateFunc("ssm", "name"),
+	}
+	for _, f := range natives {
+		l.vm.NativeFunction(f)
+	}
+}
+
+// nativeTemplateFunc is the native function that calls the template function of the name, such as tfstate of the tfstate section.
+// The template function is looked up when called, because it is appended after the config is read.
+func (l *ConfigLoader) nativeTemplateFunc(name string, param ast.Identifier) *jsonnet.NativeFunction {
+	return &jsonnet.NativeFunction{
+		Name:   name,
+		Params: ast.Identifiers{param},
+		Func: func(args []any) (v any, err error) {
+			arg, err := nativeStringArg(args)
+			if err != nil {
+				return nil, err
+			}
+			if !l.nativeResolve {
+				return "", nil
+			}
+			fn, ok := l.funcMap[name]
+			if !ok {
+				return nil, fmt.Errorf("%s is not available, check the config", name)
+			}
+			defer func() {
+				// tfstate panics if the address is not found.
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%v", r)
+				}
+			}()
+			out := reflect.ValueOf(fn).Call([]reflect.Value{reflect.ValueOf(arg)})
+			if len(out) == 2 && !out[1].IsNil() {
+				return nil, out[1].Interface().(error)
+			}
+			return out[0].Interface(), nil
+		},
+	}
+}
+
+func nativeStringArg(args []any) (string, error) {
+	s, ok := args[0].(string)
+	if !ok {
+		return "", fmt.Errorf("argument must be a string, but %T", args[0])
+	}
+	return s, nil
+}
+
+func nativeStringArgs2(args []any) (string, string, error) {
+	first, err := nativeStringArg(args[:1])
+	if err != nil {
+		return "", "", err
+	}
+	second, err := nativeStringArg(args[1:])
+	if err != nil {
+		return "", "", err
+	}
+	return first, second, nil
+}
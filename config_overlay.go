@@ -0,0 +1,147 @@
+package stefunny
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var overlayEnvPattern = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+// overlayConfigPath returns the overlay config path of the env, such as stefunny.prd.yaml for stefunny.yaml.
+func overlayConfigPath(path string, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
+
+// overlayDeleteKey is the key of the list element in overlay to delete the element of base.
+const overlayDeleteKey = "$delete"
+
+// overlayListKeys are the keys to merge list elements, the first key that all elements have is used.
+var overlayListKeys = []string{"name", "id"}
+
+// mergeOverlay deep-merges overlay into base.
+//
+//   - maps are merged by key, and null in overlay deletes the key.
+//   - lists of maps that all have `name` (or `id`) are merged by the key, new elements are appended.
+//     the element with `$delete: true` deletes the element of the same key, and `$delete` is not left in the result.
+//   - other values, including other lists, are replaced by overlay.
+func mergeOverlay(base, overlay any) any {
+	switch o := overlay.(type) {
+	case map[string]any:
+		b, ok := base.(map[string]any)
+		if !ok {
+			return o
+		}
+		merged := make(map[string]any, len(b)+len(o))
+		for k, v := range b {
+			merged[k] = v
+		}
+		for k, v := range o {
+			if v == nil {
+				delete(merged, k)
+				continue
+			}
+			merged[k] = mergeOverlay(b[k], v)
+		}
+		return merged
+	case []any:
+		b, ok := base.([]any)
+		if !ok {
+			return withoutOverlayDelete(o)
+		}
+		key, ok := overlayListKey(b, o)
+		if !ok {
+			return withoutOverlayDelete(o)
+		}
+		merged := make([]any, len(b), len(b)+len(o))
+		copy(merged, b)
+		index := make(map[string]int, len(b))
+		for i, v := range b {
+			index[fmt.Sprint(v.(map[string]any)[key])] = i
+		}
+		for _, v := range o {
+			m := v.(map[string]any)
+			name := fmt.Sprint(m[key])
+			i, ok := index[name]
+			if del, _ := m[overlayDeleteKey].(bool); del {
+				if ok {
+					merged[i] = nil
+				}
+				continue
+			}
+			v = withoutOverlayDeleteKey(m)
+			if ok {
+				merged[i] = mergeOverlay(merged[i], v)
+				continue
+			}
+			index[name] = len(merged)
+			merged = append(merged, v)
+		}
+		result := make([]any, 0, len(merged))
+		for _, v := range merged {
+			if v != nil {
+				result = append(result, v)
+			}
+		}
+		return result
+	default:
+		return overlay
+	}
+}
+
+// withoutOverlayDelete removes the elements with `$delete: true` from the list that is not merged, and `$delete` of the other elements.
+func withoutOverlayDelete(list []any) []any {
+	result := make([]any, 0, len(list))
+	for _, v := range list {
+		m, ok := v.(map[string]any)
+		if !ok {
+			result = append(result, v)
+			continue
+		}
+		if del, _ := m[overlayDeleteKey].(bool); del {
+			continue
+		}
+		result = append(result, withoutOverlayDeleteKey(m))
+	}
+	return result
+}
+
+// withoutOverlayDeleteKey returns a copy of the map without `$delete`, so that `$delete: false` is not left in the config.
+func withoutOverlayDeleteKey(m map[string]any) map[string]any {
+	if _, ok := m[overlayDeleteKey]; !ok {
+		return m
+	}
+	result := make(map[string]any, len(m)-1)
+	for k, v := range m {
+		if k != overlayDeleteKey {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func overlayListKey(lists ...[]any) (string, bool) {
+	for _, key := range overlayListKeys {
+		if allHaveKey(key, lists...) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func allHaveKey(key string, lists ...[]any) bool {
+	for _, list := range lists {
+		for _, v := range list {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return false
+			}
+			if _, ok := m[key]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package stefunny
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// configOverride is a value that patches the config after templating, from the `env` tag or the --set flag.
+type configOverride struct {
+	keyPath []string // keys and [n] indexes, e.g. trigger, schedule, [0], state
+	value   string
+	from    string // where the override is from, for logs and errors
+	// the value of the config file takes precedence over the fallback, such as the env tags
+	fallback bool
+}
+
+// AppendOverride appends the override of the config value, keyPath is dotted such as state_machine.tracing_configuration.enabled.
+func (l *ConfigLoader) AppendOverride(keyPath string, value string) error {
+	segments, err := parseKeyPath(keyPath)
+	if err != nil {
+		return err
+	}
+	l.overrides = append(l.overrides, configOverride{
+		keyPath: segments,
+		value:   value,
+		from:    "--set " + keyPath,
+	})
+	return nil
+}
+
+// configOverrides returns the overrides in the order of applying, env tags first and then --set.
+// env tags are applied only to the keys that are not set in the config file, such as the shell's AWS_REGION.
+func (l *ConfigLoader) configOverrides() []configOverride {
+	var overrides []configOverride
+	for _, f := range configTaggedFields(reflect.TypeFor[Config](), "env") {
+		value, ok := os.LookupEnv(f.tag)
+		if !ok || value == "" {
+			continue
+		}
+		overrides = append(overrides, configOverride{
+			keyPath:  f.keyPath,
+			value:    value,
+			from:     "env " + f.tag,
+			fallback: true,
+		})
+	}
+	return append(overrides, l.overrides...)
+}
+
+// applyConfigOverrides patches the decoded config, and returns the overrides that changed the value.
+// The value is a string if the schema says so, otherwise it is parsed as YAML, e.g. true or [1, 2].
+func applyConfigOverrides(config any, overrides []configOverride, schema *JSONSchema) (any, []configOverride, error) {
+	root, ok := config.(map[string]any)
+	if !ok {
+		if config != nil {
+			return nil, nil, fmt.Errorf("config is not a map, but %T", config)
+		}
+		root = map[string]any{}
+	}
+	var applied []configOverride
+	for _, o := range overrides {
+		if o.fallback && isKeyPathSet(root, o.keyPath) {
+			log.Printf("[debug] %s is set in the config, %s is ignored", joinKeyPath(o.keyPath), o.from)
+			continue
+		}
+		value, err := overrideValue(o, schema)
+		if err != nil {
+			return nil, nil, err
+		}
+		changed, err := setKeyPath(root, o.keyPath, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", o.from, err)
+		}
+		if changed {
+			log.Printf("[info] %s is overridden by %s", joinKeyPath(o.keyPath), o.from)
+			applied = append(applied, o)
+		}
+	}
+	return root, applied, nil
+}
+
+func overrideValue(o configOverride, schema *JSONSchema) (any, error) {
+	if s := lookupSchema(schema, o.keyPath); s != nil && s.Type == "string" {
+		return o.value, nil
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(o.value), &value); err != nil {
+		return nil, fmt.Errorf("%s: invalid value `%s`: %w", o.from, o.value, err)
+	}
+	return value, nil
+}
+
+// setKeyPath sets the value at the key path, missing maps are created. It returns false if the value is not changed.
+func setKeyPath(node map[string]any, keyPath []string, value any) (bool, error) {
+	var current any = node
+	for i, seg := range keyPath {
+		last := i == len(keyPath)-1
+		if index, ok := keyPathIndex(seg); ok {
+			list, ok := current.([]any)
+			if !ok {
+				return false, fmt.Errorf("%s is not a list", joinKeyPath(keyPath[:i]))
+			}
+			if index >= len(list) {
+				return false, fmt.Errorf("%s is out of range, the length is %d", joinKeyPath(keyPath[:i+1]), len(list))
+			}
+			if last {
+				if reflect.DeepEqual(list[index], value) {
+					return false, nil
+				}
+				list[index] = value
+				return true, nil
+			}
+			current = list[index]
+			continue
+		}
+		m, ok := current.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("%s is not a map", joinKeyPath(keyPath[:i]))
+		}
+		if last {
+			if old, ok := m[seg]; ok && reflect.DeepEqual(old, value) {
+				return false, nil
+			}
+			m[seg] = value
+			return true, nil
+		}
+		if m[seg] == nil {
+			if _, ok := keyPathIndex(keyPath[i+1]); ok {
+				return false, fmt.Errorf("%s is not a list", joinKeyPath(keyPath[:i+1]))
+			}
+			m[seg] = map[string]any{}
+		}
+		current = m[seg]
+	}
+	return false, nil
+}
+
+// isKeyPathSet returns true if the key path has a value other than null or the empty string.
+func isKeyPathSet(node map[string]any, keyPath []string) bool {
+	var current any = node
+	for _, seg := range keyPath {
+		if index, ok := keyPathIndex(seg); ok {
+			list, ok := current.([]any)
+			if !ok || index >= len(list) {
+				return false
+			}
+			current = list[index]
+			continue
+		}
+		m, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+		current = m[seg]
+	}
+	return current != nil && current != ""
+}
+
+// lookupSchema returns the schema of the key path, or nil if the schema does not know it.
+func lookupSchema(s *JSONSchema, keyPath []string) *JSONSchema {
+	for _, seg := range keyPath {
+		if s == nil {
+			return nil
+		}
+		if _, ok := keyPathIndex(seg); ok {
+			s = s.Items
+			continue
+		}
+		if child, ok := s.Properties[seg]; ok {
+			s = child
+			continue
+		}
+		s, _ = s.AdditionalProperties.(*JSONSchema)
+	}
+	return s
+}
+
+// parseKeyPath parses the dotted key path, such as trigger.schedule[0].state.
+func parseKeyPath(keyPath string) ([]string, error) {
+	if keyPath == "" {
+		return nil, fmt.Errorf("key path is empty")
+	}
+	var segments []string
+	for _, key := range strings.Split(keyPath, ".") {
+		name, rest, _ := strings.Cut(key, "[")
+		if name == "" {
+			return nil, fmt.Errorf("invalid key path `%s`: empty key", keyPath)
+		}
+		segments = append(segments, name)
+		for rest != "" {
+			index, after, ok := strings.Cut(rest, "]")
+			if _, err := strconv.Atoi(index); !ok || err != nil {
+				return nil, fmt.Errorf("invalid key path `%s`: invalid index `[%s`", keyPath, rest)
+			}
+			segments = append(segments, "["+index+"]")
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("invalid key path `%s`: unexpected `%s`", keyPath, after)
+			}
+			rest = after[1:]
+		}
+	}
+	return segments, nil
+}
+
+func keyPathIndex(seg string) (int, bool) {
+	if !strings.HasPrefix(seg, "[") {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.Trim(seg, "[]"))
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
+func joinKeyPath(keyPath []string) string {
+	var b strings.Builder
+	for _, seg := range keyPath {
+		if b.Len() > 0 && !strings.HasPrefix(seg, "[") {
+			b.WriteString(".")
+		}
+		b.WriteString(seg)
+	}
+	return b.String()
+}
+
+// configTaggedField is a field of the config that has the tag.
+type configTaggedField struct {
+	keyPath []string
+	index   []int
+	tag     string
+}
+
+// configTaggedFields returns the fields that have the tag, the key path is of the config file.
+// Lists, maps and AWS SDK types under KeysToSnakeCase are not walked.
+func configTaggedFields(t reflect.Type, tag string) []configTaggedField {
+	var fields []configTaggedField
+	var walk func(t reflect.Type, keyPath []string, index []int)
+	walk = func(t reflect.Type, keyPath []string, index []int) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, inline := schemaFieldName(f, false)
+			if name == "-" {
+				continue
+			}
+			fieldIndex := append(append([]int{}, index...), i)
+			if inline {
+				if !f.Type.Implements(keysToSnakeCaseIfaceTy) {
+					walk(f.Type, keyPath, fieldIndex)
+				}
+				continue
+			}
+			fieldKeyPath := append(append([]string{}, keyPath...), name)
+			if value, ok := f.Tag.Lookup(tag); ok {
+				fields = append(fields, configTaggedField{keyPath: fieldKeyPath, index: fieldIndex, tag: value})
+			}
+			walk(f.Type, fieldKeyPath, fieldIndex)
+		}
+	}
+	walk(t, nil, nil)
+	return fields
+}
@@ -1,6 +1,7 @@
 package stefunny_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -10,6 +11,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/mashiike/stefunny"
 	"github.com/mashiike/stefunny/mock"
 	"github.com/motemen/go-testutil/dataloc"
@@ -79,6 +84,58 @@ func TestConfigLoadValid(t *testing.T) {
 			path:        "testdata/stefunny.jsonnet",
 			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
 		},
+		{
+			casename:    "jsonnet_native_funcs",
+			path:        "testdata/native_funcs.jsonnet",
+			expectedDef: `{"Comment":"A Hello World example of the Amazon States Language using Pass states","StartAt":"Hello","States":{"Hello":{"Type":"Pass","Next":"World"},"World":{"Type":"Pass","Result":"{{ not a template }}","End":true}}}`,
+			envs: map[string]string{
+				"START_AT": "Hello",
+			},
+			setupLoader: func(t *testing.T, l *stefunny.ConfigLoader, _ *gomock.Controller) {
+				l.SetJsonnetTemplate(false)
+			},
+		},
+		{
+			casename:    "jsonnet_jpath",
+			path:        "testdata/jsonnet_jpath.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
+		{
+			casename:    "jsonnet_jpath_override",
+			path:        "testdata/jsonnet_jpath.yaml",
+			expectedDef: `{"Comment":"great!!!","StartAt":"Hello","States":{"Hello":{"Type":"Pass","Comment":"override","Next":"World"},"World":{"Type":"Pass","Comment":"override","Result":"World","End":true}}}`,
+			extStr: map[string]string{
+				"Comment": "great!!!",
+			},
+			setupLoader: func(t *testing.T, l *stefunny.ConfigLoader, _ *gomock.Controller) {
+				l.AppendJPath("testdata/jpath_override")
+			},
+		},
+		{
+			casename:    "stefunny_libsonnet",
+			path:        "testdata/stefunny_lib.yaml",
+			expectedDef: LoadString(t, "testdata/stefunny_lib.asl.json"),
+		},
+		{
+			casename:    "vars",
+			path:        "testdata/vars.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
+		{
+			casename:    "vars_override",
+			path:        "testdata/vars.yaml",
+			expectedDef: `{"Comment":"A Hello-prd World example of the Amazon States Language using Pass states","StartAt":"Hello","States":{"Hello":{"Type":"Pass","Next":"World"},"World":{"Type":"Pass","Result":"World","End":false}}}`,
+			setupLoader: func(t *testing.T, l *stefunny.ConfigLoader, _ *gomock.Controller) {
+				require.NoError(t, l.AppendVarFile("testdata/vars_override.yaml"))
+				require.NoError(t, l.AppendVar("log_level", "FATAL"))
+				require.NoError(t, l.AppendVar("tracing", "true"))
+			},
+		},
+		{
+			casename:    "toml",
+			path:        "testdata/toml_def.toml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
 		{
 			casename:    "event",
 			path:        "testdata/event.yaml",
@@ -89,6 +146,47 @@ func TestConfigLoadValid(t *testing.T) {
 			path:        "testdata/schedule.yaml",
 			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
 		},
+		{
+			casename:    "event_targets",
+			path:        "testdata/event_targets.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
+		{
+			casename:    "qualifier",
+			path:        "testdata/qualifier.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
+		{
+			casename:    "overlay",
+			path:        "testdata/overlay/stefunny.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+			setupLoader: func(t *testing.T, l *stefunny.ConfigLoader, _ *gomock.Controller) {
+				require.NoError(t, l.SetEnv("prd"))
+			},
+		},
+		{
+			casename:    "overlay_jsonnet",
+			path:        "testdata/overlay/stefunny.jsonnet",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+			setupLoader: func(t *testing.T, l *stefunny.ConfigLoader, _ *gomock.Controller) {
+				require.NoError(t, l.SetEnv("prd"))
+			},
+		},
+		{
+			casename:    "event_archive",
+			path:        "testdata/event_archive.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
+		{
+			casename:    "schedule_group",
+			path:        "testdata/schedule_group.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
+		{
+			casename:    "pipe",
+			path:        "testdata/pipe.yaml",
+			expectedDef: LoadString(t, "testdata/hello_world.asl.json"),
+		},
 		{
 			casename:    "old_type_config_v0.5.0",
 			path:        "testdata/old_config.yaml",
@@ -178,18 +276,81 @@ func TestConfigLoadInValid(t *testing.T) {
 		{
 			casename: "level_invalid",
 			path:     "testdata/hoge_level.yaml",
-			expected: "state_machine.logging_configuration.level is invalid level: please ALL, ERROR, FATAL, or OFF",
+			expected: "testdata/hoge_level.yaml:8:12: state_machine.logging_configuration.level is invalid level: please ALL, ERROR, FATAL, or OFF",
 		},
 		{
 			casename: "type_invalid",
 			path:     "testdata/hoge_type.yaml",
-			expected: "state_machine.type is invalid type: please STANDARD, EXPRESS",
+			expected: "testdata/hoge_type.yaml:5:9: state_machine.type is invalid type: please STANDARD, EXPRESS",
 		},
 		{
 			casename: "cycle_template_func",
 			path:     "testdata/cycle_template_func.yaml",
 			expected: "cycle template_file detected",
 		},
+		{
+			casename: "event_targets_duplicated",
+			path:     "testdata/event_targets_duplicated.yaml",
+			expected: "trigger.event[0].targets[1].id `canary` is duplicated",
+		},
+		{
+			casename: "unknown_key",
+			path:     "testdata/unknown_key.yaml",
+			expected: "testdata/unknown_key.yaml:11:3: state_machine.logging_configration: unknown key `logging_configration`\n" +
+				"   9 |   definition: hello_world.asl.json\n" +
+				"  10 |   role_arn: arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role\n" +
+				"> 11 |   logging_configration:\n" +
+				"     |   ^",
+		},
+		{
+			casename: "unknown_key_toml",
+			path:     "testdata/unknown_key.toml",
+			expected: "testdata/unknown_key.toml: state_machine.logging_configration: unknown key `logging_configration`",
+		},
+		{
+			casename: "invalid_syntax_toml",
+			path:     "testdata/invalid_syntax.toml",
+			expected: "testdata/invalid_syntax.toml:5:14: expected value but found \"hello\" instead\n" +
+				"  3 | [state_machine]\n" +
+				"  4 | name = \"Hello\"\n" +
+				"> 5 | definition = hello_world.asl.toml\n" +
+				"    |              ^",
+		},
+		{
+			casename: "vars_undefined",
+			path:     "testdata/vars_undefined.yaml",
+			expected: "testdata/vars_undefined.yaml:9:28: template execute error: executing \"testdata/vars_undefined.yaml\" at <var `account_id`>: error calling var: var `account_id` is not defined in vars",
+		},
+		{
+			casename: "jsonnet_native_must_env",
+			path:     "testdata/native_funcs.jsonnet",
+			expected: "environment variable `START_AT` is not defined",
+		},
+		{
+			casename: "missing_env",
+			path:     "testdata/missing_env.yaml",
+			expected: "testdata/missing_env.yaml:6:17: missing 1 environment variables",
+		},
+		{
+			casename: "type_mismatch",
+			path:     "testdata/type_mismatch.yaml",
+			expected: "testdata/type_mismatch.yaml:12:36: trigger.schedule[0].flexible_time_window.maximum_window_in_minutes: expected integer, but got string",
+		},
+		{
+			casename: "event_archive_description_too_long",
+			path:     "testdata/event_archive_description_too_long.yaml",
+			expected: "trigger.event[0].archive.description must be at most 478 characters to append `[ManagedBy=stefunny:Scheduled-s3]`, but 500 characters",
+		},
+		{
+			casename: "schedule_description_too_long",
+			path:     "testdata/schedule_description_too_long.yaml",
+			expected: "trigger.schedule[0].description must be at most 491 characters to append `[ManagedBy=stefunny]`, but 500 characters",
+		},
+		{
+			casename: "schedule_group_default",
+			path:     "testdata/schedule_group_default.yaml",
+			expected: "trigger.schedule_group[0].name `default` is reserved by EventBridge Scheduler",
+		},
 	}
 
 	for _, c := range cases {
@@ -207,3 +368,195 @@ func TestConfigLoadInValid(t *testing.T) {
 	}
 
 }
+
+func TestConfigLoad__ConfigError(t *testing.T) {
+	LoggerSetup(t, "debug")
+	l := stefunny.NewConfigLoader(nil, nil)
+	_, err := l.Load(context.Background(), "testdata/hoge_type.yaml")
+	require.Error(t, err)
+	var cerr *stefunny.ConfigError
+	require.ErrorAs(t, err, &cerr)
+	require.Equal(t, "testdata/hoge_type.yaml", cerr.Path)
+	require.Equal(t, 5, cerr.Line)
+	require.Equal(t, 9, cerr.Column)
+	require.Contains(t, cerr.Excerpt, "> 5 |   type: hoge")
+}
+
+func TestConfigLoad__Override(t *testing.T) {
+	LoggerSetup(t, "debug")
+	t.Setenv("AWS_REGION", "ap-northeast-1")
+	t.Setenv("REQUIRED_VERSION", ">=v0.0.0")
+	l := stefunny.NewConfigLoader(nil, nil)
+	require.NoError(t, l.AppendOverride("state_machine.tracing_configuration.enabled", "true"))
+	require.NoError(t, l.AppendOverride("state_machine.name", "012345"))
+	require.NoError(t, l.AppendOverride("trigger.schedule[0].state", "DISABLED"))
+	cfg, err := l.Load(context.Background(), "testdata/schedule.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "ap-northeast-1", cfg.AWSRegion)
+	require.Equal(t, ">v0.0.0", cfg.RequiredVersion, "the config file takes precedence over the env")
+	require.True(t, cfg.StateMachine.Value.TracingConfiguration.Enabled)
+	require.Equal(t, "012345", cfg.StateMachineName())
+	require.EqualValues(t, "DISABLED", cfg.Trigger.Schedule[0].Value.State)
+	require.Equal(t, "Asia/Tokyo", *cfg.Trigger.Schedule[0].Value.ScheduleExpressionTimezone)
+}
+
+func TestConfigLoad__OverrideEnvFallback(t *testing.T) {
+	LoggerSetup(t, "debug")
+	t.Setenv("AWS_REGION", "ap-northeast-1")
+	t.Setenv("REQUIRED_VERSION", ">=v0.0.0")
+	l := stefunny.NewConfigLoader(nil, nil)
+	cfg, err := l.Load(context.Background(), "testdata/aws_region.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", cfg.AWSRegion, "aws_region of the config file wins over AWS_REGION")
+	require.Equal(t, ">=v0.0.0", cfg.RequiredVersion, "REQUIRED_VERSION is used if required_version is not set")
+
+	l = stefunny.NewConfigLoader(nil, nil)
+	require.NoError(t, l.AppendOverride("aws_region", "eu-west-1"))
+	cfg, err = l.Load(context.Background(), "testdata/aws_region.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "eu-west-1", cfg.AWSRegion, "--set takes precedence over the config file")
+}
+
+func TestConfigLoad__OverrideInvalid(t *testing.T) {
+	LoggerSetup(t, "debug")
+	l := stefunny.NewConfigLoader(nil, nil)
+	require.EqualError(t, l.AppendOverride("state_machine..name", "Hello"), "invalid key path `state_machine..name`: empty key")
+	require.EqualError(t, l.AppendOverride("trigger.schedule[a]", "Hello"), "invalid key path `trigger.schedule[a]`: invalid index `[a]`")
+	require.NoError(t, l.AppendOverride("trigger.schedule[1].state", "DISABLED"))
+	_, err := l.Load(context.Background(), "testdata/schedule.yaml")
+	require.ErrorContains(t, err, "--set trigger.schedule[1].state: trigger.schedule[1] is out of range, the length is 1")
+}
+
+func TestConfigLoad__Validate(t *testing.T) {
+	LoggerSetup(t, "debug")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("REQUIRED_VERSION", "")
+	l := stefunny.NewConfigLoader(nil, nil)
+	_, err := l.Load(context.Background(), "testdata/invalid_values.yaml")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "testdata/invalid_values.yaml:1:19: required_version `latest` is not a version constraint")
+	require.ErrorContains(t, err, "testdata/invalid_values.yaml:2:13: aws_region `Tokyo` is not an AWS region, such as us-east-1")
+}
+
+func TestConfigLoad__VarsInvalid(t *testing.T) {
+	LoggerSetup(t, "debug")
+	cases := []struct {
+		casename string
+		key      string
+		value    string
+		expected string
+	}{
+		{
+			casename: "undefined",
+			key:      "region",
+			value:    "us-east-1",
+			expected: "--var region: var `region` is not defined in vars",
+		},
+		{
+			casename: "type_mismatch",
+			key:      "tracing",
+			value:    "yes",
+			expected: "--var tracing: var `tracing` expects boolean, but got `yes`",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.casename, func(t *testing.T) {
+			l := stefunny.NewConfigLoader(nil, nil)
+			require.NoError(t, l.AppendVar(c.key, c.value))
+			_, err := l.Load(context.Background(), "testdata/vars.yaml")
+			require.ErrorContains(t, err, c.expected)
+		})
+	}
+}
+
+func TestConfigLoad__AWSTemplateFuncs(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ssmClient := mock.NewMockSSMClient(ctrl)
+	ssmClient.EXPECT().GetParameter(gomock.Any(), &ssm.GetParameterInput{
+		Name:           aws.String("/stefunny/role_arn"),
+		WithDecryption: aws.Bool(true),
+	}, gomock.Any()).Return(&ssm.GetParameterOutput{
+		Parameter: &ssmtypes.Parameter{
+			Type:  ssmtypes.ParameterTypeString,
+			Value: aws.String("arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role"),
+		},
+	}, nil).Times(1)
+	ssmClient.EXPECT().GetParameter(gomock.Any(), &ssm.GetParameterInput{
+		Name:           aws.String("/stefunny/token"),
+		WithDecryption: aws.Bool(true),
+	}, gomock.Any()).Return(&ssm.GetParameterOutput{
+		Parameter: &ssmtypes.Parameter{
+			Type:  ssmtypes.ParameterTypeSecureString,
+			Value: aws.String("s3cr3t-token"),
+		},
+	}, nil).Times(1)
+	smClient := mock.NewMockSecretsManagerClient(ctrl)
+	smClient.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("stefunny/api"),
+	}, gomock.Any()).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"api_key":"s3cr3t-api-key","port":80}`),
+	}, nil).Times(1)
+
+	l := stefunny.NewConfigLoader(nil, nil)
+	l.SetSSMClient(ssmClient)
+	l.SetSecretsManagerClient(smClient)
+	cfg, err := l.Load(context.Background(), "testdata/aws_funcs.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role", *cfg.StateMachine.Value.RoleArn)
+	def := cfg.StateMachineDefinition()
+	require.Contains(t, def, `"Token": "s3cr3t-token"`)
+	require.Contains(t, def, `"ApiKey": "s3cr3t-api-key"`)
+	require.Contains(t, def, `"Port": "80"`)
+
+	masked := cfg.MaskSecrets(def)
+	require.NotContains(t, masked, "s3cr3t")
+	require.Contains(t, masked, `"Token": "********"`)
+	require.Contains(t, masked, `"Port": "80"`, "too short to mask")
+
+	r := stefunny.NewRenderer(cfg)
+	var buf bytes.Buffer
+	require.NoError(t, r.RenderStateMachine(context.Background(), &buf, "json", false))
+	require.NotContains(t, buf.String(), "s3cr3t")
+	buf.Reset()
+	r.SetUseStefunnyLib(true)
+	require.NoError(t, r.RenderStateMachine(context.Background(), &buf, "jsonnet", false))
+	require.NotContains(t, buf.String(), "s3cr3t")
+	require.Contains(t, buf.String(), "import 'stefunny.libsonnet'")
+	buf.Reset()
+	require.NoError(t, r.RenderConfig(context.Background(), &buf, "yaml", false))
+	require.Contains(t, buf.String(), "arn:aws:iam::012345678901:role", "not secure")
+}
+
+func TestConfigLoad__AWSIdentity(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	stsClient := mock.NewMockSTSClient(ctrl)
+	stsClient.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+		Account: aws.String("012345678901"),
+		Arn:     aws.String("arn:aws:iam::012345678901:user/stefunny"),
+	}, nil).Times(1)
+
+	l := stefunny.NewConfigLoader(nil, nil)
+	l.SetSTSClient(stsClient)
+	ctx := context.Background()
+	cfg, err := l.Load(ctx, "testdata/aws_identity.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role", *cfg.StateMachine.Value.RoleArn)
+	require.Equal(t, "arn:aws:logs:us-east-1:012345678901:log-group:/steps/hello", *cfg.StateMachine.Value.LoggingConfiguration.Destinations[0].CloudWatchLogsLogGroup.LogGroupArn)
+	require.Contains(t, cfg.StateMachineDefinition(), `"FunctionName": "arn:aws:lambda:us-east-1:012345678901:function:hello"`)
+
+	r := stefunny.NewRenderer(cfg)
+	var buf bytes.Buffer
+	require.NoError(t, r.RenderConfig(ctx, &buf, "yaml", true))
+	require.Contains(t, buf.String(), "aws_region: us-east-1")
+	require.Contains(t, buf.String(), "role_arn: \"{{ arn `iam` `role/service-role/StepFunctions-Hello-role` }}\"")
+	require.Contains(t, buf.String(), "log_group_arn: \"{{ arn `logs` `log-group:/steps/hello` }}\"")
+	buf.Reset()
+	require.NoError(t, r.RenderStateMachine(ctx, &buf, "json", true))
+	require.Contains(t, buf.String(), `"FunctionName": "{{ arn `+"`lambda` `function:hello`"+` }}"`)
+	require.Contains(t, buf.String(), `"Resource": "arn:aws:states:::lambda:invoke"`)
+}
@@ -0,0 +1,81 @@
+package stefunny
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	gv "github.com/hashicorp/go-version"
+)
+
+// configValidators are the rules of the `validate` tag, such as `validate:"omitempty,region"`.
+var configValidators = map[string]func(value string) error{
+	"region":  validateRegion,
+	"version": validateVersionConstraint,
+}
+
+// regionPattern matches AWS regions, such as us-east-1, us-gov-west-1 and cn-north-1.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]*)?-[a-z]+-[0-9]+$`)
+
+func validateRegion(value string) error {
+	if !regionPattern.MatchString(value) {
+		return errors.New("is not an AWS region, such as us-east-1")
+	}
+	return nil
+}
+
+func validateVersionConstraint(value string) error {
+	if _, err := gv.NewConstraint(value); err != nil {
+		return fmt.Errorf("is not a version constraint, such as >=v0.6.0: %w", err)
+	}
+	return nil
+}
+
+// Validate validates the config by the `validate` tags, and reports all violations joined.
+func (cfg *Config) Validate() error {
+	var errs []error
+	v := reflect.ValueOf(cfg).Elem()
+	for _, f := range configTaggedFields(v.Type(), "validate") {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// the parent is nil.
+			continue
+		}
+		if err := validateField(fv, f.tag); err != nil {
+			errs = append(errs, fmt.Errorf("%s %w", joinKeyPath(f.keyPath), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateField(v reflect.Value, tag string) error {
+	for _, rule := range strings.Split(tag, ",") {
+		switch rule {
+		case "":
+			continue
+		case "omitempty":
+			if v.IsZero() {
+				return nil
+			}
+			continue
+		case "required":
+			if v.IsZero() {
+				return errors.New("is required")
+			}
+			continue
+		}
+		validator, ok := configValidators[rule]
+		if !ok {
+			return fmt.Errorf("has unknown validate rule `%s`", rule)
+		}
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("is %s, validate rule `%s` is for string", v.Kind(), rule)
+		}
+		if err := validator(v.String()); err != nil {
+			return fmt.Errorf("`%s` %w", v.String(), err)
+		}
+	}
+	return nil
+}
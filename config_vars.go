@@ -0,0 +1,197 @@
+package stefunny
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/goccy/go-yaml"
+)
+
+// varOverride is a value of var from --var or --var-file, that overrides the default in vars of the config.
+type varOverride struct {
+	key   string
+	value any
+	raw   bool // the value is a string of --var, which is parsed as the type of the default
+	from  string
+}
+
+// AppendVar appends the value of var, which is parsed as the type of the default in vars.
+func (l *ConfigLoader) AppendVar(key string, value string) error {
+	if key == "" {
+		return fmt.Errorf("var name is empty")
+	}
+	l.varOverrides = append(l.varOverrides, varOverride{
+		key:   key,
+		value: value,
+		raw:   true,
+		from:  "--var " + key,
+	})
+	return nil
+}
+
+// AppendVarFile appends the values of var in the file, such as vars.yaml. The file is a map of var name to value.
+func (l *ConfigLoader) AppendVarFile(path string) error {
+	var values map[string]any
+	if err := l.load(path, false, false, &values); err != nil {
+		return fmt.Errorf("load var file `%s`: %w", path, err)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		l.varOverrides = append(l.varOverrides, varOverride{
+			key:   key,
+			value: values[key],
+			from:  "--var-file " + path,
+		})
+	}
+	return nil
+}
+
+// ResolveVars returns vars that the overrides are applied to the defaults.
+// If undefined is true, vars not in the defaults are allowed as is, such as for init.
+func (l *ConfigLoader) ResolveVars(defaults map[string]any, undefined bool) (map[string]any, error) {
+	vars := make(map[string]any, len(defaults))
+	for key, value := range defaults {
+		vars[key] = value
+	}
+	for _, o := range l.varOverrides {
+		def, ok := defaults[o.key]
+		if !ok && !undefined {
+			return nil, fmt.Errorf("%s: var `%s` is not defined in vars", o.from, o.key)
+		}
+		value := o.value
+		if ok {
+			var err error
+			value, err = coerceVar(def, o)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", o.from, err)
+			}
+		}
+		log.Printf("[debug] var `%s` is overridden by %s", o.key, o.from)
+		vars[o.key] = value
+	}
+	return vars, nil
+}
+
+// setVars resolves vars, and makes them available to the var template function and std.extVar of Jsonnet.
+func (l *ConfigLoader) setVars(cfg *Config) error {
+	vars, err := l.ResolveVars(cfg.Vars, false)
+	if err != nil {
+		return err
+	}
+	l.vars = vars
+	for key, value := range vars {
+		bs, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("var `%s`: %w", key, err)
+		}
+		l.vm.ExtCode(key, string(bs))
+	}
+	cfg.VarValues = varValues(vars)
+	return nil
+}
+
+// varValues returns the string values of vars sorted by name, they are templateized to the var template function.
+func varValues(vars map[string]any) *OrderdMap[string, string] {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := NewOrderdMap[string, string]()
+	for _, key := range keys {
+		if s, ok := vars[key].(string); ok {
+			values.Set(key, s)
+		}
+	}
+	return values
+}
+
+func newTemplateFuncVar(vars map[string]any) func(string) (string, error) {
+	return func(key string) (string, error) {
+		value, ok := vars[key]
+		if !ok {
+			return "", fmt.Errorf("var `%s` is not defined in vars", key)
+		}
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		bs, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("var `%s`: %w", key, err)
+		}
+		return string(bs), nil
+	}
+}
+
+// coerceVar converts the value of the override to the type of the default.
+func coerceVar(def any, o varOverride) (any, error) {
+	kind := varKind(def)
+	if !o.raw {
+		if kind != "null" && varKind(o.value) != kind {
+			return nil, fmt.Errorf("var `%s` expects %s, but got %s", o.key, kind, varKind(o.value))
+		}
+		return o.value, nil
+	}
+	s := o.value.(string)
+	switch kind {
+	case "string":
+		return s, nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("var `%s` expects boolean, but got `%s`", o.key, s)
+		}
+		return b, nil
+	case "number":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("var `%s` expects number, but got `%s`", o.key, s)
+		}
+		return f, nil
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(s), &value); err != nil {
+		return nil, fmt.Errorf("var `%s` has invalid value `%s`: %w", o.key, s, err)
+	}
+	if kind != "null" && varKind(value) != kind {
+		return nil, fmt.Errorf("var `%s` expects %s, but got %s", o.key, kind, varKind(value))
+	}
+	return value, nil
+}
+
+// varKind returns the kind of the value in JSON Schema terms.
+func varKind(v any) string {
+	if v == nil {
+		return "null"
+	}
+	if _, ok := v.(json.Number); ok {
+		return "number"
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
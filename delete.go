@@ -32,12 +32,13 @@ func (app *App) Delete(ctx context.Context, opt DeleteOption) error {
 	log.Printf("[notice] delete state machine is %s\n%s", opt.DryRunString(), stateMachine)
 	currentRules, err := app.eventbridgeSvc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
 		StateMachineQualifiedArn: stateMachine.QualifiedArn(app.StateMachineAliasName()),
+		Qualifiers:               app.cfg.NewEventBridgeRules().Qualifiers(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to search related rules: %w", err)
 	}
 	if len(currentRules) > 0 {
-		log.Printf("[notice] delete related rules is %s\n%s", opt.DryRunString(), currentRules)
+		log.Printf("[notice] delete related rules and their archives is %s\n%s", opt.DryRunString(), currentRules)
 	}
 	currentSchedules, err := app.schedulerSvc.SearchRelatedSchedules(ctx, &SearchRelatedSchedulesInput{
 		StateMachineQualifiedArn: stateMachine.QualifiedArn(app.StateMachineAliasName()),
@@ -45,10 +46,30 @@ func (app *App) Delete(ctx context.Context, opt DeleteOption) error {
 	if err != nil {
 		return fmt.Errorf("failed to search related schedules: %w", err)
 	}
+	currentSchedules, oneOffs := currentSchedules.FilterOneOff()
 	if len(currentSchedules) > 0 {
 		log.Printf("[notice] delete related schedules is %s\n%s", opt.DryRunString(), currentSchedules)
 	}
+	if len(oneOffs) > 0 {
+		log.Printf("[notice] cancel one-off executions scheduled by `execute --at` is %s\n%s", opt.DryRunString(), oneOffs)
+	}
+	currentPipes, err := app.pipesSvc.SearchRelatedPipes(ctx, &SearchRelatedPipesInput{
+		StateMachineQualifiedArn: stateMachine.QualifiedArn(app.StateMachineAliasName()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search related pipes: %w", err)
+	}
+	if len(currentPipes) > 0 {
+		log.Printf("[notice] delete related pipes is %s\n%s", opt.DryRunString(), currentPipes)
+	}
 	if opt.DryRun {
+		groups, err := app.schedulerSvc.SearchScheduleGroups(ctx, app.cfg.StateMachineName(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to search schedule groups: %w", err)
+		}
+		for _, group := range groups {
+			log.Printf("[notice] delete schedule group `%s` if empty %s", group.Name, opt.DryRunString())
+		}
 		log.Println("[info] dry run ok")
 		return nil
 	}
@@ -78,6 +99,20 @@ func (app *App) Delete(ctx context.Context, opt DeleteOption) error {
 			return fmt.Errorf("failed to delete schedules: %w", err)
 		}
 	}
+	for _, oneOff := range oneOffs {
+		if err := app.schedulerSvc.DeleteOneOffSchedule(ctx, stateMachine.QualifiedArn(app.StateMachineAliasName()), oneOff.key()); err != nil {
+			return fmt.Errorf("failed to cancel one-off execution: %w", err)
+		}
+	}
+	if err := app.schedulerSvc.PruneScheduleGroups(ctx, app.cfg.StateMachineName(), ScheduleGroups{}); err != nil {
+		return fmt.Errorf("failed to delete schedule groups: %w", err)
+	}
+	if len(currentPipes) > 0 {
+		err := app.pipesSvc.DeployPipes(ctx, stateMachine.QualifiedArn(app.StateMachineAliasName()), Pipes{}, false)
+		if err != nil {
+			return fmt.Errorf("failed to delete pipes: %w", err)
+		}
+	}
 	log.Println("[info] finish delete", opt.DryRunString())
 	return nil
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
 	"github.com/aws/aws-sdk-go-v2/service/sfn"
 	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
 	"github.com/mashiike/stefunny"
@@ -55,6 +57,16 @@ func TestDelete(t *testing.T) {
 					stefunny.Schedules{},
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), &stefunny.SearchRelatedPipesInput{
+					StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:current",
+				}).Return(
+					stefunny.Pipes{},
+					nil,
+				).Times(1)
+				m.scheduler.EXPECT().SearchScheduleGroups(gomock.Any(), "Hello", nil).Return(
+					stefunny.ScheduleGroups{},
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -89,6 +101,13 @@ func TestDelete(t *testing.T) {
 					stefunny.Schedules{},
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Hello", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), &stefunny.SearchRelatedPipesInput{
+					StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:current",
+				}).Return(
+					stefunny.Pipes{},
+					nil,
+				).Times(1)
 				m.sfn.EXPECT().DeleteStateMachine(gomock.Any(), gomock.Cond(
 					func(input *stefunny.StateMachine) bool {
 						return assert.Contains(t, *input.StateMachineArn, "Hello")
@@ -98,6 +117,54 @@ func TestDelete(t *testing.T) {
 				).Times(1)
 			},
 		},
+		{
+			casename: "one-off scheduled",
+			path:     "testdata/stefunny.yaml",
+			DryRun:   false,
+			setupMocks: func(t *testing.T, m *mocks) {
+				m.sfn.EXPECT().DescribeStateMachine(gomock.Any(), &stefunny.DescribeStateMachineInput{
+					Name: "Hello",
+				}).Return(
+					&stefunny.StateMachine{
+						CreateStateMachineInput: sfn.CreateStateMachineInput{
+							Name:       aws.String("Hello"),
+							RoleArn:    aws.String("arn:aws:iam::123456789012:role/service-role/StatesExecutionRole-us-east-1"),
+							Definition: aws.String(`{}`),
+						},
+						StateMachineArn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Hello"),
+						Status:          sfntypes.StateMachineStatusActive,
+						CreationDate:    aws.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+					},
+					nil,
+				).Times(1)
+				m.eventBridge.EXPECT().SearchRelatedRules(gomock.Any(), gomock.Any()).Return(
+					stefunny.EventBridgeRules{},
+					nil,
+				).Times(1)
+				m.scheduler.EXPECT().SearchRelatedSchedules(gomock.Any(), gomock.Any()).Return(
+					stefunny.Schedules{
+						{
+							CreateScheduleInput: scheduler.CreateScheduleInput{
+								Name:        aws.String("Hello-at-20261018T000000"),
+								GroupName:   aws.String("default"),
+								Description: aws.String("[OneOff] [ManagedBy=stefunny]"),
+								Target: &schedulertypes.Target{
+									Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Hello:current"),
+								},
+							},
+						},
+					},
+					nil,
+				).Times(1)
+				m.scheduler.EXPECT().DeleteOneOffSchedule(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:current", "default/Hello-at-20261018T000000").Return(nil).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Hello", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), gomock.Any()).Return(
+					stefunny.Pipes{},
+					nil,
+				).Times(1)
+				m.sfn.EXPECT().DeleteStateMachine(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
 		{
 			casename: "scheduled dry run",
 			path:     "testdata/event.yaml",
@@ -147,6 +214,21 @@ func TestDelete(t *testing.T) {
 					stefunny.Schedules{},
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), &stefunny.SearchRelatedPipesInput{
+					StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current",
+				}).Return(
+					stefunny.Pipes{},
+					nil,
+				).Times(1)
+				m.scheduler.EXPECT().SearchScheduleGroups(gomock.Any(), "Scheduled", nil).Return(
+					stefunny.ScheduleGroups{
+						{
+							Name: "Scheduled-group",
+							Tags: map[string]string{"ManagedBy": "stefunny", "ManagedStateMachine": "Scheduled"},
+						},
+					},
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -198,6 +280,13 @@ func TestDelete(t *testing.T) {
 					stefunny.Schedules{},
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), &stefunny.SearchRelatedPipesInput{
+					StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current",
+				}).Return(
+					stefunny.Pipes{},
+					nil,
+				).Times(1)
 				m.sfn.EXPECT().DeleteStateMachine(gomock.Any(), gomock.Cond(
 					func(input *stefunny.StateMachine) bool {
 						return assert.Contains(t, *input.StateMachineArn, "Scheduled")
@@ -92,6 +92,9 @@ func (app *App) Deploy(ctx context.Context, opt DeployOption) error {
 		if err := app.deploySchedules(ctx, opt); err != nil {
 			return fmt.Errorf("failed to deploy schedules: %w", err)
 		}
+		if err := app.deployPipes(ctx, opt); err != nil {
+			return fmt.Errorf("failed to deploy pipes: %w", err)
+		}
 	}
 	log.Println("[info] finish deploy", opt.DryRunString())
 	return nil
@@ -114,7 +117,7 @@ func (app *App) deployStateMachine(ctx context.Context, opt DeployOption) error
 	if opt.DryRun {
 		diffString := stateMachine.DiffString(newStateMachine, opt.Unified)
 		log.Printf("[notice] change state machine %s\n", opt.DryRunString())
-		fmt.Println(diffString)
+		fmt.Println(app.cfg.MaskSecrets(diffString))
 		return nil
 	}
 	if opt.VersionDescription != "" {
@@ -159,6 +162,7 @@ func (app *App) deployEventBridgeRules(ctx context.Context, opt DeployOption) er
 			currentRules, err = app.eventbridgeSvc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
 				StateMachineQualifiedArn: targetArn,
 				RuleNames:                newRules.Names(),
+				Qualifiers:               newRules.Qualifiers(),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to search related rules: %w", err)
@@ -169,7 +173,7 @@ func (app *App) deployEventBridgeRules(ctx context.Context, opt DeployOption) er
 		}
 		diffString := currentRules.DiffString(newRules, opt.Unified)
 		log.Printf("[notice] change related rules %s\n", opt.DryRunString())
-		fmt.Println(diffString)
+		fmt.Println(app.cfg.MaskSecrets(diffString))
 		return nil
 	}
 	if err := app.eventbridgeSvc.DeployRules(ctx, targetArn, newRules, keepState); err != nil {
@@ -192,6 +196,7 @@ func (app *App) deploySchedules(ctx context.Context, opt DeployOption) error {
 		isStateMachineFound = false
 	}
 	newSchedules := app.cfg.NewSchedules()
+	newGroups := app.cfg.NewScheduleGroups()
 	targetArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
 	newSchedules.SetStateMachineQualifiedArn(targetArn)
 	keepState := true
@@ -204,22 +209,101 @@ func (app *App) deploySchedules(ctx context.Context, opt DeployOption) error {
 		if isStateMachineFound {
 			currentSchedules, err = app.schedulerSvc.SearchRelatedSchedules(ctx, &SearchRelatedSchedulesInput{
 				StateMachineQualifiedArn: targetArn,
-				ScheduleNames:            newSchedules.Names(),
+				ScheduleNames:            newSchedules.keys(),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to search related schedules: %w", err)
 			}
+			currentSchedules, _ = currentSchedules.FilterOneOff()
 		}
 		if keepState {
 			newSchedules.SyncState(currentSchedules)
 		}
+		if err := app.planScheduleGroups(ctx, newGroups, opt); err != nil {
+			return err
+		}
 		diffString := currentSchedules.DiffString(newSchedules, opt.Unified)
 		log.Printf("[notice] change related schedules %s", opt.DryRunString())
-		fmt.Println(diffString)
+		fmt.Println(app.cfg.MaskSecrets(diffString))
 		return nil
 	}
+	if err := app.schedulerSvc.DeployScheduleGroups(ctx, app.cfg.StateMachineName(), newGroups); err != nil {
+		return fmt.Errorf("failed to deploy schedule groups: %w", err)
+	}
 	if err := app.schedulerSvc.DeploySchedules(ctx, targetArn, newSchedules, keepState); err != nil {
 		return fmt.Errorf("failed to deploy schedules: %w", err)
 	}
+	if err := app.schedulerSvc.PruneScheduleGroups(ctx, app.cfg.StateMachineName(), newGroups); err != nil {
+		return fmt.Errorf("failed to prune schedule groups: %w", err)
+	}
+	return nil
+}
+
+func (app *App) planScheduleGroups(ctx context.Context, newGroups ScheduleGroups, opt DeployOption) error {
+	currentGroups, err := app.schedulerSvc.SearchScheduleGroups(ctx, app.cfg.StateMachineName(), newGroups.Names())
+	if err != nil {
+		return fmt.Errorf("failed to search schedule groups: %w", err)
+	}
+	for _, group := range newGroups {
+		current, ok := currentGroups.FindByName(group.Name)
+		if !ok {
+			log.Printf("[notice] create schedule group `%s` %s", group.Name, opt.DryRunString())
+			continue
+		}
+		if !current.hasSameTags(group) {
+			log.Printf("[notice] update tags of schedule group `%s` %s", group.Name, opt.DryRunString())
+		}
+	}
+	for _, current := range currentGroups {
+		if _, ok := newGroups.FindByName(current.Name); ok {
+			continue
+		}
+		log.Printf("[notice] delete schedule group `%s` if empty %s", current.Name, opt.DryRunString())
+	}
+	return nil
+}
+
+func (app *App) deployPipes(ctx context.Context, opt DeployOption) error {
+	stateMachineArn, err := app.sfnSvc.GetStateMachineArn(ctx, &GetStateMachineArnInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	isStateMachineFound := true
+	if err != nil {
+		if !errors.Is(err, ErrStateMachineDoesNotExist) {
+			return fmt.Errorf("failed to get state machine arn: %w", err)
+		}
+		stateMachineArn = "[known after deploy]"
+		isStateMachineFound = false
+	}
+	newPipes := app.cfg.NewPipes()
+	targetArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
+	newPipes.SetStateMachineQualifiedArn(targetArn)
+	keepState := true
+	if opt.TriggerEnabled != nil {
+		newPipes.SetEnabled(*opt.TriggerEnabled)
+		keepState = false
+	}
+	if opt.DryRun {
+		currentPipes := Pipes{}
+		if isStateMachineFound {
+			currentPipes, err = app.pipesSvc.SearchRelatedPipes(ctx, &SearchRelatedPipesInput{
+				StateMachineQualifiedArn: targetArn,
+				PipeNames:                newPipes.Names(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to search related pipes: %w", err)
+			}
+		}
+		if keepState {
+			newPipes.SyncState(currentPipes)
+		}
+		diffString := currentPipes.DiffString(newPipes, opt.Unified)
+		log.Printf("[notice] change related pipes %s", opt.DryRunString())
+		fmt.Println(app.cfg.MaskSecrets(diffString))
+		return nil
+	}
+	if err := app.pipesSvc.DeployPipes(ctx, targetArn, newPipes, keepState); err != nil {
+		return fmt.Errorf("failed to deploy pipes: %w", err)
+	}
 	return nil
 }
@@ -52,7 +52,7 @@ func TestDeploy(t *testing.T) {
 				}).Return(
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Hello",
 					nil,
-				).Times(2)
+				).Times(3)
 				m.eventBridge.EXPECT().SearchRelatedRules(gomock.Any(), &stefunny.SearchRelatedRulesInput{
 					StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test",
 					RuleNames:                []string{},
@@ -67,6 +67,17 @@ func TestDeploy(t *testing.T) {
 					stefunny.Schedules{},
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().SearchScheduleGroups(gomock.Any(), "Hello", []string{}).Return(
+					stefunny.ScheduleGroups{},
+					nil,
+				).Times(1)
+				m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), &stefunny.SearchRelatedPipesInput{
+					StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test",
+					PipeNames:                []string{},
+				}).Return(
+					stefunny.Pipes{},
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -107,7 +118,7 @@ func TestDeploy(t *testing.T) {
 				}).Return(
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Hello",
 					nil,
-				).Times(2)
+				).Times(3)
 				m.eventBridge.EXPECT().DeployRules(
 					gomock.Any(),
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test",
@@ -116,9 +127,14 @@ func TestDeploy(t *testing.T) {
 				).Return(
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().DeployScheduleGroups(gomock.Any(), "Hello", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Hello", stefunny.ScheduleGroups{}).Return(nil).Times(1)
 				m.scheduler.EXPECT().DeploySchedules(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test", stefunny.Schedules{}, true).Return(
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().DeployPipes(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test", stefunny.Pipes{}, true).Return(
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -151,7 +167,7 @@ func TestDeploy(t *testing.T) {
 				}).Return(
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Hello",
 					nil,
-				).Times(2)
+				).Times(3)
 				m.eventBridge.EXPECT().DeployRules(
 					gomock.Any(),
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test",
@@ -160,9 +176,14 @@ func TestDeploy(t *testing.T) {
 				).Return(
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().DeployScheduleGroups(gomock.Any(), "Hello", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Hello", stefunny.ScheduleGroups{}).Return(nil).Times(1)
 				m.scheduler.EXPECT().DeploySchedules(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test", stefunny.Schedules{}, true).Return(
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().DeployPipes(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:test", stefunny.Pipes{}, true).Return(
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -196,7 +217,7 @@ func TestDeploy(t *testing.T) {
 				}).Return(
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled",
 					nil,
-				).Times(2)
+				).Times(3)
 				m.eventBridge.EXPECT().DeployRules(
 					gomock.Any(),
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test",
@@ -229,9 +250,14 @@ func TestDeploy(t *testing.T) {
 					), true).Return(
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().DeployScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
 				m.scheduler.EXPECT().DeploySchedules(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test", stefunny.Schedules{}, true).Return(
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().DeployPipes(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test", stefunny.Pipes{}, true).Return(
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -273,7 +299,7 @@ func TestDeploy(t *testing.T) {
 				}).Return(
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled",
 					nil,
-				).Times(2)
+				).Times(3)
 				m.eventBridge.EXPECT().DeployRules(
 					gomock.Any(),
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test",
@@ -306,9 +332,14 @@ func TestDeploy(t *testing.T) {
 					), true).Return(
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().DeployScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
 				m.scheduler.EXPECT().DeploySchedules(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test", stefunny.Schedules{}, true).Return(
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().DeployPipes(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test", stefunny.Pipes{}, true).Return(
+					nil,
+				).Times(1)
 			},
 		},
 		{
@@ -347,7 +378,7 @@ func TestDeploy(t *testing.T) {
 				}).Return(
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled",
 					nil,
-				).Times(2)
+				).Times(3)
 				m.eventBridge.EXPECT().DeployRules(
 					gomock.Any(),
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test",
@@ -356,6 +387,8 @@ func TestDeploy(t *testing.T) {
 				).Return(
 					nil,
 				).Times(1)
+				m.scheduler.EXPECT().DeployScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
+				m.scheduler.EXPECT().PruneScheduleGroups(gomock.Any(), "Scheduled", stefunny.ScheduleGroups{}).Return(nil).Times(1)
 				m.scheduler.EXPECT().DeploySchedules(
 					gomock.Any(),
 					"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test",
@@ -368,6 +401,7 @@ func TestDeploy(t *testing.T) {
 								{
 									CreateScheduleInput: scheduler.CreateScheduleInput{
 										Name:                       aws.String("Scheduled-hourly"),
+										Description:                aws.String("[ManagedBy=stefunny]"),
 										ScheduleExpression:         aws.String("rate(1 hour)"),
 										ScheduleExpressionTimezone: aws.String("Asia/Tokyo"),
 										Target: &schedulertypes.Target{
@@ -382,6 +416,9 @@ func TestDeploy(t *testing.T) {
 					true).Return(
 					nil,
 				).Times(1)
+				m.pipes.EXPECT().DeployPipes(gomock.Any(), "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:test", stefunny.Pipes{}, true).Return(
+					nil,
+				).Times(1)
 			},
 		},
 	}
@@ -4,15 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 )
 
 type DiffOption struct {
-	Unified   bool   `name:"unified" help:"output in unified format" short:"u" default:"true" negatable:"" json:"unified,omitempty"`
-	Qualifier string `name:"qualifier" help:"qualifier for state machine" default:"" json:"qualifier,omitempty"`
+	Writer    io.Writer `kong:"-" json:"-"`
+	Unified   bool      `name:"unified" help:"output in unified format" short:"u" default:"true" negatable:"" json:"unified,omitempty"`
+	Qualifier string    `name:"qualifier" help:"qualifier for state machine" default:"" json:"qualifier,omitempty"`
 }
 
 func (app *App) Diff(ctx context.Context, opt DiffOption) error {
+	out := opt.Writer
+	if out == nil {
+		out = os.Stdout
+	}
 	newStateMachine := app.cfg.NewStateMachine()
 	var stateMachineArn string
 	currentStateMachine, err := app.sfnSvc.DescribeStateMachine(ctx, &DescribeStateMachineInput{
@@ -45,7 +52,7 @@ func (app *App) Diff(ctx context.Context, opt DiffOption) error {
 	})
 	ds := strings.TrimSpace(currentStateMachine.DiffString(newStateMachine, opt.Unified))
 	if ds != "" {
-		fmt.Println(ds)
+		fmt.Fprintln(out, app.cfg.MaskSecrets(ds))
 	}
 	var currentRules EventBridgeRules
 	newRules := app.cfg.NewEventBridgeRules()
@@ -53,6 +60,7 @@ func (app *App) Diff(ctx context.Context, opt DiffOption) error {
 		currentRules, err = app.eventbridgeSvc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
 			StateMachineQualifiedArn: stateMachineArn,
 			RuleNames:                newRules.Names(),
+			Qualifiers:               newRules.Qualifiers(),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to search related rules: %w", err)
@@ -65,24 +73,43 @@ func (app *App) Diff(ctx context.Context, opt DiffOption) error {
 	newRules.SyncState(currentRules)
 	ds = strings.TrimSpace(currentRules.DiffString(newRules, opt.Unified))
 	if ds != "" {
-		fmt.Println(ds)
+		fmt.Fprintln(out, app.cfg.MaskSecrets(ds))
 	}
 	var currentSchedules Schedules
 	newSchedules := app.cfg.NewSchedules()
 	if currentStateMachine != nil {
 		currentSchedules, err = app.schedulerSvc.SearchRelatedSchedules(ctx, &SearchRelatedSchedulesInput{
 			StateMachineQualifiedArn: stateMachineArn,
-			ScheduleNames:            newSchedules.Names(),
+			ScheduleNames:            newSchedules.keys(),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to search related schedules: %w", err)
 		}
+		// one-off executions of `execute --at` are not deleted by deploy
+		currentSchedules, _ = currentSchedules.FilterOneOff()
 	}
 	newSchedules.SetStateMachineQualifiedArn(stateMachineArn)
 	newSchedules.SyncState(currentSchedules)
 	ds = strings.TrimSpace(currentSchedules.DiffString(newSchedules, opt.Unified))
 	if ds != "" {
-		fmt.Println(ds)
+		fmt.Fprintln(out, app.cfg.MaskSecrets(ds))
+	}
+	var currentPipes Pipes
+	newPipes := app.cfg.NewPipes()
+	if currentStateMachine != nil {
+		currentPipes, err = app.pipesSvc.SearchRelatedPipes(ctx, &SearchRelatedPipesInput{
+			StateMachineQualifiedArn: stateMachineArn,
+			PipeNames:                newPipes.Names(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to search related pipes: %w", err)
+		}
+	}
+	newPipes.SetStateMachineQualifiedArn(stateMachineArn)
+	newPipes.SyncState(currentPipes)
+	ds = strings.TrimSpace(currentPipes.DiffString(newPipes, opt.Unified))
+	if ds != "" {
+		fmt.Fprintln(out, app.cfg.MaskSecrets(ds))
 	}
 	return nil
 }
@@ -0,0 +1,72 @@
+package stefunny_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDiff__OneOffSchedules(t *testing.T) {
+	LoggerSetup(t, "debug")
+	m := NewMocks(t)
+	defer m.Finish()
+	m.sfn.EXPECT().DescribeStateMachine(gomock.Any(), &stefunny.DescribeStateMachineInput{
+		Name: "Hello",
+	}).Return(
+		&stefunny.StateMachine{
+			CreateStateMachineInput: sfn.CreateStateMachineInput{
+				Name:       aws.String("Hello"),
+				RoleArn:    aws.String("arn:aws:iam::123456789012:role/service-role/StatesExecutionRole-us-east-1"),
+				Definition: aws.String(`{}`),
+			},
+			StateMachineArn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Hello"),
+			Status:          sfntypes.StateMachineStatusActive,
+			CreationDate:    aws.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		nil,
+	).Times(1)
+	m.eventBridge.EXPECT().SearchRelatedRules(gomock.Any(), gomock.Any()).Return(
+		stefunny.EventBridgeRules{},
+		nil,
+	).Times(1)
+	m.scheduler.EXPECT().SearchRelatedSchedules(gomock.Any(), gomock.Any()).Return(
+		stefunny.Schedules{
+			{
+				CreateScheduleInput: scheduler.CreateScheduleInput{
+					Name:               aws.String("Hello-at-20261018T000000"),
+					GroupName:          aws.String("default"),
+					Description:        aws.String("[OneOff] [ManagedBy=stefunny]"),
+					ScheduleExpression: aws.String("at(2026-10-18T00:00:00)"),
+					Target: &schedulertypes.Target{
+						Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Hello:current"),
+					},
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	m.pipes.EXPECT().SearchRelatedPipes(gomock.Any(), gomock.Any()).Return(
+		stefunny.Pipes{},
+		nil,
+	).Times(1)
+
+	app := newMockApp(t, "testdata/stefunny.yaml", m)
+	var buf bytes.Buffer
+	err := app.Diff(context.Background(), stefunny.DiffOption{
+		Writer:  &buf,
+		Unified: true,
+	})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "stateMachine:Hello", "diff of the state machine")
+	require.NotContains(t, buf.String(), "Hello-at-20261018T000000", "one-off schedules are not deleted by deploy")
+}
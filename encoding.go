@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/fatih/color"
+	jsonnet "github.com/google/go-jsonnet"
 	"github.com/google/go-jsonnet/formatter"
 	"github.com/hexops/gotextdiff"
 	"github.com/hexops/gotextdiff/myers"
@@ -24,6 +27,348 @@ func JSON2Jsonnet(filename string, data []byte) ([]byte, error) {
 	return []byte(formattted), nil
 }
 
+// JSON2StefunnyJsonnet converts the state machine definition to Jsonnet that uses the helpers of stefunny.libsonnet.
+// the top-level states are written with chain([...]) if they are a linear chain of Next, and with the helpers if they can build them.
+// it falls back to JSON2Jsonnet if the definition can not be written so, or is not evaluated to the same definition.
+func JSON2StefunnyJsonnet(filename string, data []byte) ([]byte, error) {
+	src, err := definitionToStefunnyJsonnet(data)
+	if err != nil {
+		log.Printf("[warn] %s is not used: %s", stefunnyLibsonnetName, err)
+		return JSON2Jsonnet(filename, data)
+	}
+	vm := jsonnet.MakeVM()
+	vm.Importer(newJsonnetImporter(nil))
+	evaluated, err := vm.EvaluateAnonymousSnippet(filename, src)
+	if err != nil {
+		log.Printf("[warn] %s is not used: %s", stefunnyLibsonnetName, err)
+		return JSON2Jsonnet(filename, data)
+	}
+	if !isSameJSON(evaluated, string(data)) {
+		log.Printf("[warn] %s is not used: the definition is changed by the helpers", stefunnyLibsonnetName)
+		return JSON2Jsonnet(filename, data)
+	}
+	return JSON2Jsonnet(filename, []byte(src))
+}
+
+// stefunnyTerminalTypes are the types of states that chain of stefunny.libsonnet does not add Next or End to.
+var stefunnyTerminalTypes = []string{"Succeed", "Fail", "Choice"}
+
+func definitionToStefunnyJsonnet(data []byte) (string, error) {
+	keys, fields, err := decodeJSONObject(data)
+	if err != nil {
+		return "", err
+	}
+	var startAt string
+	if err := json.Unmarshal(fields["StartAt"], &startAt); err != nil {
+		return "", fmt.Errorf("StartAt: %w", err)
+	}
+	stateNames, stateFields, err := decodeJSONObject(fields["States"])
+	if err != nil {
+		return "", fmt.Errorf("States: %w", err)
+	}
+	states := make(map[string]map[string]json.RawMessage, len(stateNames))
+	stateKeys := make(map[string][]string, len(stateNames))
+	for _, name := range stateNames {
+		stateKeys[name], states[name], err = decodeJSONObject(stateFields[name])
+		if err != nil {
+			return "", fmt.Errorf("States.%s: %w", name, err)
+		}
+	}
+	retry, err := stefunnyLibValue("lambdaRetry")
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "local stefunny = import '%s';\n\n", stefunnyLibsonnetName)
+	chain, ok := linearStateChain(startAt, stateNames, states)
+	if !ok {
+		b.WriteString("{\n")
+		for _, key := range keys {
+			if key != "States" {
+				fmt.Fprintf(&b, "%s: %s,\n", jsonQuote(key), fields[key])
+				continue
+			}
+			b.WriteString("States: {\n")
+			for _, name := range stateNames {
+				fmt.Fprintf(&b, "%s: %s,\n", jsonQuote(name), stateToStefunnyJsonnet(stateKeys[name], states[name], retry))
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("}\n")
+		return b.String(), nil
+	}
+	rest := slices.DeleteFunc(slices.Clone(keys), func(key string) bool {
+		return key == "StartAt" || key == "States"
+	})
+	if len(rest) > 0 {
+		b.WriteString(jsonnetObject(rest, fields) + " + ")
+	}
+	b.WriteString("stefunny.chain([\n")
+	for _, name := range chain {
+		state, keys := states[name], stateKeys[name]
+		if !slices.Contains(stefunnyTerminalTypes, jsonStringValue(state["Type"])) {
+			keys = slices.DeleteFunc(slices.Clone(keys), func(key string) bool {
+				return key == "Next" || key == "End"
+			})
+		}
+		fmt.Fprintf(&b, "{ %s: %s },\n", jsonQuote(name), stateToStefunnyJsonnet(keys, state, retry))
+	}
+	b.WriteString("])\n")
+	return b.String(), nil
+}
+
+// linearStateChain returns the names of the states in the order of Next, if all states are the chain from StartAt to End or a terminal state.
+func linearStateChain(startAt string, names []string, states map[string]map[string]json.RawMessage) ([]string, bool) {
+	chain := make([]string, 0, len(names))
+	for name := startAt; ; {
+		state, ok := states[name]
+		if !ok || slices.Contains(chain, name) {
+			return nil, false
+		}
+		chain = append(chain, name)
+		if slices.Contains(stefunnyTerminalTypes, jsonStringValue(state["Type"])) {
+			break
+		}
+		if next, ok := state["Next"]; ok {
+			name = jsonStringValue(next)
+			continue
+		}
+		if string(state["End"]) != "true" {
+			return nil, false
+		}
+		break
+	}
+	return chain, len(chain) == len(names)
+}
+
+// stateToStefunnyJsonnet writes the state with the helper of stefunny.libsonnet and the fields that the helper does not build.
+func stateToStefunnyJsonnet(keys []string, state map[string]json.RawMessage, lambdaRetry string) string {
+	call, used := stefunnyHelperCall(state, lambdaRetry)
+	rest := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !slices.Contains(used, key) {
+			rest = append(rest, key)
+		}
+	}
+	switch {
+	case call == "":
+		return jsonnetObject(keys, state)
+	case len(rest) == 0:
+		return call
+	default:
+		return call + " + " + jsonnetObject(rest, state)
+	}
+}
+
+// stefunnyHelperCall returns the call of the helper that builds the state, and the fields that it builds.
+func stefunnyHelperCall(state map[string]json.RawMessage, lambdaRetry string) (string, []string) {
+	switch jsonStringValue(state["Type"]) {
+	case "Pass":
+		if result, ok := state["Result"]; ok && string(result) != "null" {
+			return fmt.Sprintf("stefunny.pass(%s)", result), []string{"Type", "Result"}
+		}
+		return "stefunny.pass()", []string{"Type"}
+	case "Wait":
+		if seconds, ok := state["Seconds"]; ok {
+			return fmt.Sprintf("stefunny.wait(%s)", seconds), []string{"Type", "Seconds"}
+		}
+	case "Succeed":
+		return "stefunny.succeed()", []string{"Type"}
+	case "Fail":
+		args := make([]string, 0, 2)
+		if errorName, ok := state["Error"]; ok {
+			args = append(args, fmt.Sprintf("errorName=%s", errorName))
+		}
+		if cause, ok := state["Cause"]; ok {
+			args = append(args, fmt.Sprintf("cause=%s", cause))
+		}
+		return fmt.Sprintf("stefunny.fail(%s)", strings.Join(args, ", ")), []string{"Type", "Error", "Cause"}
+	case "Task":
+		return stefunnyTaskHelperCall(state, lambdaRetry)
+	}
+	return "", nil
+}
+
+func stefunnyTaskHelperCall(state map[string]json.RawMessage, lambdaRetry string) (string, []string) {
+	keys, params, err := decodeJSONObject(state["Parameters"])
+	if err != nil {
+		return "", nil
+	}
+	// has returns true if the parameters have the required keys, and no other keys than the optional keys.
+	has := func(required []string, optional ...string) bool {
+		for _, key := range required {
+			if _, ok := params[key]; !ok {
+				return false
+			}
+		}
+		return !slices.ContainsFunc(keys, func(key string) bool {
+			return !slices.Contains(required, key) && !slices.Contains(optional, key)
+		})
+	}
+	used := []string{"Type", "Resource", "Parameters"}
+	switch jsonStringValue(state["Resource"]) {
+	case "arn:aws:states:::lambda:invoke":
+		args := []string{string(params["FunctionName"])}
+		switch {
+		case has([]string{"FunctionName", "Payload.$"}) && jsonStringValue(params["Payload.$"]) == "$":
+		case has([]string{"FunctionName", "Payload"}):
+			args = append(args, fmt.Sprintf("payload=%s", params["Payload"]))
+		default:
+			return "", nil
+		}
+		retry, ok := state["Retry"]
+		switch {
+		case !ok:
+			args = append(args, "retry=[]")
+		case !isSameJSON(string(retry), lambdaRetry):
+			args = append(args, fmt.Sprintf("retry=%s", retry))
+		}
+		return fmt.Sprintf("stefunny.lambdaInvoke(%s)", strings.Join(args, ", ")), append(used, "Retry")
+	case "arn:aws:states:::ecs:runTask.sync":
+		if !has([]string{"LaunchType", "Cluster", "TaskDefinition"}, "NetworkConfiguration", "Overrides") {
+			return "", nil
+		}
+		args := []string{string(params["Cluster"]), string(params["TaskDefinition"])}
+		if jsonStringValue(params["LaunchType"]) != "FARGATE" {
+			args = append(args, fmt.Sprintf("launchType=%s", params["LaunchType"]))
+		}
+		if value, ok := params["NetworkConfiguration"]; ok {
+			args = append(args, fmt.Sprintf("networkConfiguration=%s", value))
+		}
+		if value, ok := params["Overrides"]; ok {
+			args = append(args, fmt.Sprintf("overrides=%s", value))
+		}
+		return fmt.Sprintf("stefunny.ecsRunTask(%s)", strings.Join(args, ", ")), used
+	case "arn:aws:states:::sqs:sendMessage":
+		args := []string{string(params["QueueUrl"])}
+		switch {
+		case has([]string{"QueueUrl", "MessageBody.$"}) && jsonStringValue(params["MessageBody.$"]) == "$":
+		case has([]string{"QueueUrl", "MessageBody"}):
+			args = append(args, fmt.Sprintf("messageBody=%s", params["MessageBody"]))
+		default:
+			return "", nil
+		}
+		return fmt.Sprintf("stefunny.sqsSendMessage(%s)", strings.Join(args, ", ")), used
+	case "arn:aws:states:::states:startExecution.sync:2":
+		if !has([]string{"StateMachineArn", "Input"}) {
+			return "", nil
+		}
+		inputKeys, input, err := decodeJSONObject(params["Input"])
+		if err != nil || jsonStringValue(input["AWS_STEP_FUNCTIONS_STARTED_BY_EXECUTION_ID.$"]) != "$$.Execution.Id" {
+			return "", nil
+		}
+		args := []string{string(params["StateMachineArn"])}
+		inputKeys = slices.DeleteFunc(inputKeys, func(key string) bool {
+			return key == "AWS_STEP_FUNCTIONS_STARTED_BY_EXECUTION_ID.$"
+		})
+		if len(inputKeys) > 0 {
+			args = append(args, fmt.Sprintf("input=%s", jsonnetObject(inputKeys, input)))
+		}
+		return fmt.Sprintf("stefunny.startExecution(%s)", strings.Join(args, ", ")), used
+	}
+	return "", nil
+}
+
+// isSameJSON compares JSON ignoring whitespace and key order.
+func isSameJSON(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb any
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		return false
+	}
+	return compactJSONString(va) == compactJSONString(vb)
+}
+
+// stefunnyLibValue evaluates the field of stefunny.libsonnet to JSON.
+func stefunnyLibValue(field string) (string, error) {
+	vm := jsonnet.MakeVM()
+	vm.Importer(newJsonnetImporter(nil))
+	return vm.EvaluateAnonymousSnippet(field, fmt.Sprintf("(import '%s').%s", stefunnyLibsonnetName, field))
+}
+
+// decodeJSONObject decodes the JSON object, and returns the keys in the order of the object.
+func decodeJSONObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected object, got %s", data)
+	}
+	keys := make([]string, 0)
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := t.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		fields[key] = value
+	}
+	return keys, fields, nil
+}
+
+// jsonnetObject writes the fields of the keys as the Jsonnet object, JSON values are valid in Jsonnet.
+func jsonnetObject(keys []string, fields map[string]json.RawMessage) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s: %s,\n", jsonQuote(key), fields[key])
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func jsonStringValue(data json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ""
+	}
+	return s
+}
+
+func jsonQuote(s string) string {
+	bs, _ := json.Marshal(s)
+	return string(bs)
+}
+
+// TOML2JSON converts TOML to JSON, TOML has a table at the top level.
+func TOML2JSON(data []byte) ([]byte, error) {
+	var v map[string]any
+	if _, err := toml.Decode(string(data), &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// JSON2TOML converts JSON object to TOML, null values are not allowed in TOML.
+func JSON2TOML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("toml requires an object at the top level, got %T", v)
+	}
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func toDiffString(s1 string) string {
 	if strings.EqualFold(s1, "null") || strings.EqualFold(s1, "null\n") {
 		return ""
@@ -42,6 +42,46 @@ func TestJSON2Jsonnet(t *testing.T) {
 	g.Assert(t, "json2jsonnet", bs)
 }
 
+func TestJSON2StefunnyJsonnet(t *testing.T) {
+	cases := []string{
+		"hello_world",
+		"stefunny_lib",
+		"workflow1",
+	}
+	g := goldie.New(
+		t,
+		goldie.WithFixtureDir("testdata/encoding"),
+		goldie.WithNameSuffix(".golden.asl.jsonnet"),
+	)
+	for _, name := range cases {
+		t.Run(name, func(t *testing.T) {
+			bs, err := stefunny.JSON2StefunnyJsonnet(name+".asl.json", []byte(LoadString(t, "testdata/"+name+".asl.json")))
+			require.NoError(t, err)
+			g.Assert(t, "json2stefunnyjsonnet_"+name, bs)
+		})
+	}
+}
+
+func TestTOML2JSON(t *testing.T) {
+	tomlASL := LoadString(t, "testdata/hello_world.asl.toml")
+	jsonASL := LoadString(t, "testdata/hello_world.asl.json")
+	bs, err := stefunny.TOML2JSON([]byte(tomlASL))
+	require.NoError(t, err)
+	require.JSONEq(t, jsonASL, string(bs))
+}
+
+func TestJSON2TOML(t *testing.T) {
+	jsonASL := LoadString(t, "testdata/hello_world.asl.json")
+	bs, err := stefunny.JSON2TOML([]byte(jsonASL))
+	require.NoError(t, err)
+	actual, err := stefunny.TOML2JSON(bs)
+	require.NoError(t, err)
+	require.JSONEq(t, jsonASL, string(actual))
+
+	_, err = stefunny.JSON2TOML([]byte(`["Hello"]`))
+	require.Error(t, err)
+}
+
 func TestKeysToSnakeCase__CreateStateMachineInput(t *testing.T) {
 	LoggerSetup(t, "debug")
 	yamlStr := `
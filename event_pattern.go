@@ -0,0 +1,509 @@
+package stefunny
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// MatchEventPattern evaluates EventBridge event pattern against the event in local.
+// supported: exact match, prefix, suffix, equals-ignore-case, anything-but, numeric, exists, wildcard, cidr and $or.
+func MatchEventPattern(pattern string, event []byte) (bool, error) {
+	var p map[string]any
+	if err := json.Unmarshal([]byte(pattern), &p); err != nil {
+		return false, fmt.Errorf("failed to parse event pattern: %w", err)
+	}
+	var e any
+	if err := json.Unmarshal(event, &e); err != nil {
+		return false, fmt.Errorf("failed to parse event: %w", err)
+	}
+	if _, ok := e.(map[string]any); !ok {
+		return false, fmt.Errorf("event must be JSON object")
+	}
+	return matchEventPatternObject(p, []any{e})
+}
+
+func matchEventPatternObject(pattern map[string]any, nodes []any) (bool, error) {
+	keys := make([]string, 0, len(pattern))
+	for key := range pattern {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var matched bool
+		var err error
+		switch v := pattern[key].(type) {
+		case map[string]any:
+			matched, err = matchEventPatternObject(v, eventChildValues(nodes, key))
+		case []any:
+			if key == "$or" {
+				matched, err = matchEventPatternOr(v, nodes)
+			} else {
+				matched, err = matchEventPatternValues(v, eventChildValues(nodes, key))
+			}
+		default:
+			return false, fmt.Errorf("pattern of `%s` must be object or array", key)
+		}
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", key, err)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchEventPatternOr(subPatterns []any, nodes []any) (bool, error) {
+	for i, sub := range subPatterns {
+		p, ok := sub.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("[%d] must be object", i)
+		}
+		matched, err := matchEventPatternObject(p, nodes)
+		if err != nil {
+			return false, fmt.Errorf("[%d]: %w", i, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// eventChildValues returns values of the key, arrays in event are flattened as EventBridge does.
+func eventChildValues(nodes []any, key string) []any {
+	values := make([]any, 0, len(nodes))
+	for _, node := range nodes {
+		obj, ok := node.(map[string]any)
+		if !ok {
+			continue
+		}
+		v, ok := obj[key]
+		if !ok {
+			continue
+		}
+		values = append(values, flattenEventValue(v)...)
+	}
+	return values
+}
+
+func flattenEventValue(v any) []any {
+	arr, ok := v.([]any)
+	if !ok {
+		return []any{v}
+	}
+	values := make([]any, 0, len(arr))
+	for _, item := range arr {
+		values = append(values, flattenEventValue(item)...)
+	}
+	return values
+}
+
+func matchEventPatternValues(matchers []any, values []any) (bool, error) {
+	for _, matcher := range matchers {
+		filter, ok := matcher.(map[string]any)
+		if !ok {
+			for _, value := range values {
+				if eventValueEquals(matcher, value) {
+					return true, nil
+				}
+			}
+			continue
+		}
+		matched, err := matchContentFilter(filter, values)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func matchContentFilter(filter map[string]any, values []any) (bool, error) {
+	if len(filter) != 1 {
+		return false, fmt.Errorf("content filter must have exactly one key: %s", MarshalJSONString(filter))
+	}
+	for name, arg := range filter {
+		switch name {
+		case "exists":
+			b, ok := arg.(bool)
+			if !ok {
+				return false, fmt.Errorf("exists must be boolean")
+			}
+			return (len(values) > 0) == b, nil
+		case "anything-but":
+			if len(values) == 0 {
+				return false, nil
+			}
+			for _, value := range values {
+				matched, err := matchAnythingButInner(arg, value)
+				if err != nil {
+					return false, err
+				}
+				if !matched {
+					return true, nil
+				}
+			}
+			return false, nil
+		case "numeric":
+			for _, value := range values {
+				matched, err := matchNumeric(arg, value)
+				if err != nil {
+					return false, err
+				}
+				if matched {
+					return true, nil
+				}
+			}
+			return false, nil
+		default:
+			for _, value := range values {
+				matched, err := matchStringFilter(name, arg, value)
+				if err != nil {
+					return false, err
+				}
+				if matched {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+func matchAnythingButInner(arg any, value any) (bool, error) {
+	switch v := arg.(type) {
+	case []any:
+		for _, item := range v {
+			if eventValueEquals(item, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		if len(v) != 1 {
+			return false, fmt.Errorf("anything-but filter must have exactly one key")
+		}
+		for name, inner := range v {
+			if innerList, ok := inner.([]any); ok {
+				for _, item := range innerList {
+					matched, err := matchStringFilter(name, item, value)
+					if err != nil || matched {
+						return matched, err
+					}
+				}
+				return false, nil
+			}
+			return matchStringFilter(name, inner, value)
+		}
+	}
+	return eventValueEquals(arg, value), nil
+}
+
+func matchStringFilter(name string, arg any, value any) (bool, error) {
+	str, isString := value.(string)
+	switch name {
+	case "prefix", "suffix":
+		expected, ignoreCase, err := stringFilterArg(name, arg)
+		if err != nil {
+			return false, err
+		}
+		if !isString {
+			return false, nil
+		}
+		if ignoreCase {
+			str, expected = strings.ToLower(str), strings.ToLower(expected)
+		}
+		if name == "prefix" {
+			return strings.HasPrefix(str, expected), nil
+		}
+		return strings.HasSuffix(str, expected), nil
+	case "equals-ignore-case":
+		expected, ok := arg.(string)
+		if !ok {
+			return false, fmt.Errorf("equals-ignore-case must be string")
+		}
+		return isString && strings.EqualFold(str, expected), nil
+	case "wildcard":
+		expected, ok := arg.(string)
+		if !ok {
+			return false, fmt.Errorf("wildcard must be string")
+		}
+		return isString && matchWildcard(expected, str), nil
+	case "cidr":
+		expected, ok := arg.(string)
+		if !ok {
+			return false, fmt.Errorf("cidr must be string")
+		}
+		prefix, err := netip.ParsePrefix(expected)
+		if err != nil {
+			return false, fmt.Errorf("invalid cidr `%s`: %w", expected, err)
+		}
+		if !isString {
+			return false, nil
+		}
+		addr, err := netip.ParseAddr(str)
+		if err != nil {
+			return false, nil
+		}
+		return prefix.Contains(addr), nil
+	}
+	return false, fmt.Errorf("unsupported content filter `%s`", name)
+}
+
+func stringFilterArg(name string, arg any) (string, bool, error) {
+	switch v := arg.(type) {
+	case string:
+		return v, false, nil
+	case map[string]any:
+		if s, ok := v["equals-ignore-case"].(string); ok && len(v) == 1 {
+			return s, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("%s must be string or {\"equals-ignore-case\": string}", name)
+}
+
+// matchWildcard matches `*` as any characters, `\*` is literal asterisk.
+func matchWildcard(pattern, str string) bool {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern) && (pattern[i+1] == '*' || pattern[i+1] == '\\'):
+			current.WriteByte(pattern[i+1])
+			i++
+		case pattern[i] == '*':
+			tokens = append(tokens, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(pattern[i])
+		}
+	}
+	tokens = append(tokens, current.String())
+	if len(tokens) == 1 {
+		return str == tokens[0]
+	}
+	if !strings.HasPrefix(str, tokens[0]) {
+		return false
+	}
+	str = str[len(tokens[0]):]
+	last := tokens[len(tokens)-1]
+	for _, token := range tokens[1 : len(tokens)-1] {
+		idx := strings.Index(str, token)
+		if idx < 0 {
+			return false
+		}
+		str = str[idx+len(token):]
+	}
+	return strings.HasSuffix(str, last)
+}
+
+func matchNumeric(arg any, value any) (bool, error) {
+	conds, ok := arg.([]any)
+	if !ok || len(conds) == 0 || len(conds)%2 != 0 {
+		return false, fmt.Errorf("numeric must be array of operator and number pairs")
+	}
+	num, ok := value.(float64)
+	if !ok {
+		return false, nil
+	}
+	for i := 0; i < len(conds); i += 2 {
+		op, ok := conds[i].(string)
+		if !ok {
+			return false, fmt.Errorf("numeric operator must be string")
+		}
+		operand, ok := conds[i+1].(float64)
+		if !ok {
+			return false, fmt.Errorf("numeric operand must be number")
+		}
+		var matched bool
+		switch op {
+		case "=":
+			matched = num == operand
+		case "<":
+			matched = num < operand
+		case "<=":
+			matched = num <= operand
+		case ">":
+			matched = num > operand
+		case ">=":
+			matched = num >= operand
+		default:
+			return false, fmt.Errorf("unsupported numeric operator `%s`", op)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func eventValueEquals(expected, actual any) bool {
+	switch e := expected.(type) {
+	case nil:
+		return actual == nil
+	case string:
+		a, ok := actual.(string)
+		return ok && a == e
+	case float64:
+		a, ok := actual.(float64)
+		return ok && a == e
+	case bool:
+		a, ok := actual.(bool)
+		return ok && a == e
+	}
+	return false
+}
+
+// RenderTargetInput returns the input that the target receives for the event.
+func RenderTargetInput(target eventbridgetypes.Target, event []byte, ruleArn, ruleName string) (string, error) {
+	if target.Input != nil {
+		return *target.Input, nil
+	}
+	var e any
+	if err := json.Unmarshal(event, &e); err != nil {
+		return "", fmt.Errorf("failed to parse event: %w", err)
+	}
+	if target.InputPath != nil {
+		v, err := evalEventJSONPath(e, *target.InputPath)
+		if err != nil {
+			return "", fmt.Errorf("input_path: %w", err)
+		}
+		return compactJSONString(v), nil
+	}
+	if target.InputTransformer == nil {
+		return string(event), nil
+	}
+	vars := make(map[string]any, len(target.InputTransformer.InputPathsMap)+5)
+	for name, path := range target.InputTransformer.InputPathsMap {
+		v, err := evalEventJSONPath(e, path)
+		if err != nil {
+			return "", fmt.Errorf("input_transformer.input_paths_map.%s: %w", name, err)
+		}
+		vars[name] = v
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, event); err != nil {
+		return "", fmt.Errorf("failed to compact event: %w", err)
+	}
+	vars["aws.events.event"] = json.RawMessage(compact.Bytes())
+	vars["aws.events.event.json"] = json.RawMessage(compact.Bytes())
+	vars["aws.events.rule-arn"] = ruleArn
+	vars["aws.events.rule-name"] = ruleName
+	vars["aws.events.event.ingestion-time"] = time.Now().UTC().Format(time.RFC3339)
+	return renderInputTemplate(coalesce(target.InputTransformer.InputTemplate), vars), nil
+}
+
+// renderInputTemplate replaces <name> placeholders, inside of JSON string the value is embedded without quotes.
+func renderInputTemplate(template string, vars map[string]any) string {
+	var builder strings.Builder
+	inString := false
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case c == '\\' && inString && i+1 < len(template):
+			builder.WriteByte(c)
+			builder.WriteByte(template[i+1])
+			i++
+			continue
+		case c == '"':
+			inString = !inString
+		case c == '<':
+			end := strings.IndexByte(template[i:], '>')
+			if end > 0 {
+				name := template[i+1 : i+end]
+				if v, ok := vars[name]; ok {
+					builder.WriteString(inputTemplateValue(v, inString))
+					i += end
+					continue
+				}
+			}
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
+
+func inputTemplateValue(v any, inString bool) string {
+	if !inString {
+		return compactJSONString(v)
+	}
+	str, ok := v.(string)
+	if !ok {
+		str = compactJSONString(v)
+	}
+	quoted := compactJSONString(str)
+	return quoted[1 : len(quoted)-1]
+}
+
+func compactJSONString(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "null"
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// evalEventJSONPath evaluates simple JSONPath such as `$.detail.items[0].id`.
+func evalEventJSONPath(v any, path string) (any, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path must start with `$`: %s", path)
+	}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, nil
+			}
+			v = obj[rest[:end]]
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path: %s", path)
+			}
+			key := rest[1:end]
+			rest = rest[end+1:]
+			if unquoted, err := strconv.Unquote(strings.ReplaceAll(key, "'", `"`)); err == nil {
+				obj, ok := v.(map[string]any)
+				if !ok {
+					return nil, nil
+				}
+				v = obj[unquoted]
+				continue
+			}
+			idx, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid json path: %s", path)
+			}
+			arr, ok := v.([]any)
+			if !ok || idx < 0 || idx >= len(arr) {
+				return nil, nil
+			}
+			v = arr[idx]
+		default:
+			return nil, fmt.Errorf("invalid json path: %s", path)
+		}
+	}
+	return v, nil
+}
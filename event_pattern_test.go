@@ -0,0 +1,167 @@
+package stefunny_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchEventPattern(t *testing.T) {
+	event := []byte(LoadString(t, "testdata/event_sample.json"))
+	cases := []struct {
+		name     string
+		pattern  string
+		expected bool
+		isErr    bool
+	}{
+		{
+			name:     "exact",
+			pattern:  `{"source":["aws.s3"],"detail-type":["Object Created"]}`,
+			expected: true,
+		},
+		{
+			name:     "exact not matched",
+			pattern:  `{"source":["aws.ec2"]}`,
+			expected: false,
+		},
+		{
+			name:     "nested",
+			pattern:  `{"detail":{"bucket":{"name":["hello-bucket"]}}}`,
+			expected: true,
+		},
+		{
+			name:     "array value",
+			pattern:  `{"detail":{"tags":["cat"]}}`,
+			expected: true,
+		},
+		{
+			name:     "prefix",
+			pattern:  `{"detail":{"object":{"key":[{"prefix":"images/"}]}}}`,
+			expected: true,
+		},
+		{
+			name:     "prefix equals-ignore-case",
+			pattern:  `{"detail":{"object":{"key":[{"prefix":{"equals-ignore-case":"IMAGES/"}}]}}}`,
+			expected: true,
+		},
+		{
+			name:     "suffix",
+			pattern:  `{"detail":{"object":{"key":[{"suffix":".jpg"}]}}}`,
+			expected: false,
+		},
+		{
+			name:     "anything-but",
+			pattern:  `{"detail":{"bucket":{"name":[{"anything-but":["other-bucket"]}]}}}`,
+			expected: true,
+		},
+		{
+			name:     "anything-but prefix",
+			pattern:  `{"detail":{"object":{"key":[{"anything-but":{"prefix":"images/"}}]}}}`,
+			expected: false,
+		},
+		{
+			name:     "anything-but missing field",
+			pattern:  `{"detail":{"missing":[{"anything-but":"x"}]}}`,
+			expected: false,
+		},
+		{
+			name:     "numeric",
+			pattern:  `{"detail":{"object":{"size":[{"numeric":[">",1024,"<=",4096]}]}}}`,
+			expected: true,
+		},
+		{
+			name:     "numeric not matched",
+			pattern:  `{"detail":{"object":{"size":[{"numeric":["<",1024]}]}}}`,
+			expected: false,
+		},
+		{
+			name:     "exists",
+			pattern:  `{"detail":{"object":{"key":[{"exists":true}],"etag":[{"exists":false}]}}}`,
+			expected: true,
+		},
+		{
+			name:     "wildcard",
+			pattern:  `{"detail":{"object":{"key":[{"wildcard":"images/*.png"}]}}}`,
+			expected: true,
+		},
+		{
+			name:     "cidr",
+			pattern:  `{"detail":{"source-ip-address":[{"cidr":"10.0.0.0/16"}]}}`,
+			expected: true,
+		},
+		{
+			name:     "$or",
+			pattern:  `{"source":["aws.s3"],"$or":[{"detail":{"object":{"size":[{"numeric":["<",10]}]}}},{"detail":{"tags":["cat"]}}]}`,
+			expected: true,
+		},
+		{
+			name:     "$or not matched",
+			pattern:  `{"$or":[{"source":["aws.ec2"]},{"detail-type":["Object Deleted"]}]}`,
+			expected: false,
+		},
+		{
+			name:    "unsupported filter",
+			pattern: `{"source":[{"regex":"aws.*"}]}`,
+			isErr:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := stefunny.MatchEventPattern(c.pattern, event)
+			if c.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestRenderTargetInput(t *testing.T) {
+	event := []byte(LoadString(t, "testdata/event_sample.json"))
+	cases := []struct {
+		name     string
+		target   eventbridgetypes.Target
+		expected string
+	}{
+		{
+			name: "input",
+			target: eventbridgetypes.Target{
+				Input: aws.String(`{"hello":"world"}`),
+			},
+			expected: `{"hello":"world"}`,
+		},
+		{
+			name: "input path",
+			target: eventbridgetypes.Target{
+				InputPath: aws.String("$.detail.object"),
+			},
+			expected: `{"key":"images/cat.png","size":2048}`,
+		},
+		{
+			name: "input transformer",
+			target: eventbridgetypes.Target{
+				InputTransformer: &eventbridgetypes.InputTransformer{
+					InputPathsMap: map[string]string{
+						"bucket": "$.detail.bucket.name",
+						"object": "$.detail.object",
+						"tag":    "$.detail.tags[1]",
+					},
+					InputTemplate: aws.String(`{"path":"s3://<bucket>/<tag>","object":<object>,"rule":"<aws.events.rule-name>"}`),
+				},
+			},
+			expected: `{"path":"s3://hello-bucket/cat","object":{"key":"images/cat.png","size":2048},"rule":"Hello"}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := stefunny.RenderTargetInput(c.target, event, "", "Hello")
+			require.NoError(t, err)
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
@@ -3,21 +3,28 @@ package stefunny
 import (
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
 )
 
 type EventBridgeRule struct {
 	eventbridge.PutRuleInput
-	RuleArn           *string                   `yaml:"RuleArn,omitempty" json:"RuleArn,omitempty"`
-	CreatedBy         *string                   `yaml:"CreatedBy,omitempty" json:"CreatedBy,omitempty"`
-	Target            eventbridgetypes.Target   `yaml:"Target,omitempty" json:"Target,omitempty"`
-	AdditionalTargets []eventbridgetypes.Target `yaml:"AdditionalTargets,omitempty" json:"AdditionalTargets,omitempty"`
-	ConfigFilePath    *string                   `yaml:"ConfigFilePath,omitempty" json:"ConfigFilePath,omitempty"`
-	ConfigFileIndex   int                       `yaml:"ConfigFileIndex,omitempty" json:"ConfigFileIndex,omitempty"`
+	RuleArn           *string                         `yaml:"RuleArn,omitempty" json:"RuleArn,omitempty"`
+	CreatedBy         *string                         `yaml:"CreatedBy,omitempty" json:"CreatedBy,omitempty"`
+	Target            eventbridgetypes.Target         `yaml:"Target,omitempty" json:"Target,omitempty"`
+	AdditionalTargets []eventbridgetypes.Target       `yaml:"AdditionalTargets,omitempty" json:"AdditionalTargets,omitempty"`
+	Qualifier         *string                         `yaml:"Qualifier,omitempty" json:"Qualifier,omitempty"`
+	Archive           *eventbridge.CreateArchiveInput `yaml:"Archive,omitempty" json:"Archive,omitempty"`
+	ConfigFilePath    *string                         `yaml:"ConfigFilePath,omitempty" json:"ConfigFilePath,omitempty"`
+	ConfigFileIndex   int                             `yaml:"ConfigFileIndex,omitempty" json:"ConfigFileIndex,omitempty"`
 }
 
 func (rule *EventBridgeRule) Source() string {
@@ -36,11 +43,66 @@ func (rule *EventBridgeRule) Source() string {
 	return knownAfterDeployArn
 }
 
+var defaultEventBridgeTargetID = fmt.Sprintf("%s-managed-state-machine", appName)
+
+// SetStateMachineQualifiedArn sets the target arn, if the rule has own qualifier, it takes precedence.
 func (rule *EventBridgeRule) SetStateMachineQualifiedArn(stateMachineArn string) {
-	rule.Target.Arn = aws.String(stateMachineArn)
+	rule.Target.Arn = aws.String(qualifyTriggerTargetArn(stateMachineArn, rule.Qualifier))
 	if rule.Target.Id == nil {
-		rule.Target.Id = aws.String(fmt.Sprintf("%s-managed-state-machine", appName))
+		rule.Target.Id = aws.String(defaultEventBridgeTargetID)
+	}
+}
+
+// archiveManagedByMarker returns the ownership marker of the archive of the rule in description, because archives can not be tagged.
+func archiveManagedByMarker(ruleName string) string {
+	return fmt.Sprintf("[%s=%s:%s]", tagManagedBy, appName, ruleName)
+}
+
+// maxArchiveDescriptionLength is the limit of the description of EventBridge archive, including the marker.
+const maxArchiveDescriptionLength = 512
+
+// validateArchiveDescription returns an error if the description exceeds the limit after the marker of the rule is appended.
+func validateArchiveDescription(ruleName string, desc string) error {
+	marker := archiveManagedByMarker(ruleName)
+	if strings.Contains(desc, marker) {
+		return nil
+	}
+	limit := maxArchiveDescriptionLength - len(marker) - 1
+	if n := utf8.RuneCountInString(desc); n > limit {
+		return fmt.Errorf("description must be at most %d characters to append `%s`, but %d characters", limit, marker, n)
 	}
+	return nil
+}
+
+// AppendArchiveManagedByMarker appends the ownership marker of the rule to the archive description.
+func (rule *EventBridgeRule) AppendArchiveManagedByMarker() {
+	if rule.Archive == nil {
+		return
+	}
+	marker := archiveManagedByMarker(coalesce(rule.Name))
+	desc := coalesce(rule.Archive.Description)
+	if strings.Contains(desc, marker) {
+		return
+	}
+	if desc != "" {
+		rule.Archive.Description = aws.String(desc + " " + marker)
+		return
+	}
+	rule.Archive.Description = aws.String(marker)
+}
+
+// TargetIDs returns ids of the state machine target and the additional targets.
+func (rule *EventBridgeRule) TargetIDs() []string {
+	ids := make([]string, 0, len(rule.AdditionalTargets)+1)
+	if id := coalesce(rule.Target.Id); id != "" {
+		ids = append(ids, id)
+	}
+	for _, target := range rule.AdditionalTargets {
+		if id := coalesce(target.Id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 func (rule *EventBridgeRule) IsManagedBy() bool {
@@ -90,11 +152,43 @@ func (rule *EventBridgeRule) configureJSON() string {
 	for _, tag := range rule.Tags {
 		tags[coalesce(tag.Key)] = coalesce(tag.Value)
 	}
-	return MarshalJSONString(rule.PutRuleInput, map[string]interface{}{
+	// additional targets are sorted by id, the order of ListTargetsByRule differs from the config.
+	var additionalTargets []eventbridgetypes.Target
+	if len(rule.AdditionalTargets) > 0 {
+		additionalTargets = make([]eventbridgetypes.Target, len(rule.AdditionalTargets))
+		copy(additionalTargets, rule.AdditionalTargets)
+	}
+	sort.SliceStable(additionalTargets, func(i, j int) bool {
+		return coalesce(additionalTargets[i].Id) < coalesce(additionalTargets[j].Id)
+	})
+	overrides := map[string]interface{}{
 		"Target":            rule.Target,
-		"AdditionalTargets": rule.AdditionalTargets,
+		"AdditionalTargets": additionalTargets,
 		"Tags":              tags,
-	})
+	}
+	if archive := rule.configureArchive(); archive != nil {
+		overrides["Archive"] = archive
+	}
+	return MarshalJSONString(rule.PutRuleInput, overrides)
+}
+
+// configureArchive returns the archive without the values that stefunny sets on deploy, the marker and the event source.
+func (rule *EventBridgeRule) configureArchive() *eventbridge.CreateArchiveInput {
+	if rule.Archive == nil {
+		return nil
+	}
+	archive := *rule.Archive
+	archive.EventSourceArn = nil
+	archive.Description = nil
+	desc := strings.TrimSpace(strings.ReplaceAll(coalesce(rule.Archive.Description), archiveManagedByMarker(coalesce(rule.Name)), ""))
+	if desc != "" {
+		archive.Description = aws.String(desc)
+	}
+	// the archive without retention_days keeps events indefinitely, DescribeArchive returns 0 for it.
+	if coalesce(archive.RetentionDays) == 0 {
+		archive.RetentionDays = nil
+	}
+	return &archive
 }
 
 func (rule *EventBridgeRule) String() string {
@@ -115,9 +209,28 @@ func (rule *EventBridgeRule) DiffString(newRule *EventBridgeRule, unified bool)
 			JSONDiffUnified(unified),
 		),
 	)
+	if rule != nil && newRule != nil {
+		now := time.Now()
+		before, errBefore := rule.NextRuns(now, diffNextRuns)
+		after, errAfter := newRule.NextRuns(now, diffNextRuns)
+		if errBefore == nil && errAfter == nil {
+			builder.WriteString(nextRunsDiffString(before, after))
+		}
+	}
 	return builder.String()
 }
 
+// NextRuns returns at most n upcoming fire times after from, nil if the rule is not scheduled.
+// schedule expression of EventBridge rule is always evaluated in UTC.
+func (rule *EventBridgeRule) NextRuns(from time.Time, n int) ([]ScheduledRun, error) {
+	if coalesce(rule.ScheduleExpression) == "" {
+		return nil, nil
+	}
+	return NextScheduledRuns(&NextScheduledRunsInput{
+		ScheduleExpression: *rule.ScheduleExpression,
+	}, from, n)
+}
+
 func (rule *EventBridgeRule) SetEnabled(enabled bool) {
 	if enabled {
 		rule.State = eventbridgetypes.RuleStateEnabled
@@ -126,6 +239,27 @@ func (rule *EventBridgeRule) SetEnabled(enabled bool) {
 	}
 }
 
+// eventBusArnFromRuleArn returns the event bus arn of the rule, the event bus name may be an arn.
+func eventBusArnFromRuleArn(ruleArn string, eventBusName string) string {
+	if strings.HasPrefix(eventBusName, "arn:") {
+		return eventBusName
+	}
+	if eventBusName == "" {
+		eventBusName = "default"
+	}
+	arnObj, err := arn.Parse(ruleArn)
+	if err != nil {
+		return ruleArn
+	}
+	arnObj.Resource = "event-bus/" + eventBusName
+	return arnObj.String()
+}
+
+// isSameEventPattern compares event patterns ignoring whitespace and key order.
+func isSameEventPattern(a, b string) bool {
+	return isSameJSON(a, b)
+}
+
 type EventBridgeRules []*EventBridgeRule
 
 func (rules EventBridgeRules) SetStateMachineQualifiedArn(stateMachineArn string) {
@@ -205,6 +339,17 @@ func (rules EventBridgeRules) Names() []string {
 	return names
 }
 
+// Qualifiers returns the qualifiers of the rules that target other versions or aliases, nil if no rule has it.
+func (rules EventBridgeRules) Qualifiers() []string {
+	var qualifiers []string
+	for _, rule := range rules {
+		if qualifier := coalesce(rule.Qualifier); qualifier != "" && !slices.Contains(qualifiers, qualifier) {
+			qualifiers = append(qualifiers, qualifier)
+		}
+	}
+	return qualifiers
+}
+
 func (rules EventBridgeRules) FindByName(name string) (*EventBridgeRule, bool) {
 	for _, rule := range rules {
 		if coalesce(rule.Name) == name {
@@ -2,16 +2,23 @@ package stefunny
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/aws/smithy-go"
 	"github.com/mashiike/stefunny/internal/eventbridgex"
+	"github.com/mashiike/stefunny/internal/sfnx"
 )
 
 //go:generate go tool mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
@@ -25,6 +32,22 @@ type EventBridgeClient interface {
 	RemoveTargets(ctx context.Context, params *eventbridge.RemoveTargetsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.RemoveTargetsOutput, error)
 	ListTagsForResource(ctx context.Context, params *eventbridge.ListTagsForResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTagsForResourceOutput, error)
 	TagResource(ctx context.Context, params *eventbridge.TagResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.TagResourceOutput, error)
+	UntagResource(ctx context.Context, params *eventbridge.UntagResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.UntagResourceOutput, error)
+	TestEventPattern(ctx context.Context, params *eventbridge.TestEventPatternInput, optFns ...func(*eventbridge.Options)) (*eventbridge.TestEventPatternOutput, error)
+	EnableRule(ctx context.Context, params *eventbridge.EnableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.EnableRuleOutput, error)
+	DisableRule(ctx context.Context, params *eventbridge.DisableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DisableRuleOutput, error)
+	DescribeArchive(ctx context.Context, params *eventbridge.DescribeArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DescribeArchiveOutput, error)
+	CreateArchive(ctx context.Context, params *eventbridge.CreateArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.CreateArchiveOutput, error)
+	UpdateArchive(ctx context.Context, params *eventbridge.UpdateArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.UpdateArchiveOutput, error)
+	DeleteArchive(ctx context.Context, params *eventbridge.DeleteArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DeleteArchiveOutput, error)
+	StartReplay(ctx context.Context, params *eventbridge.StartReplayInput, optFns ...func(*eventbridge.Options)) (*eventbridge.StartReplayOutput, error)
+	DescribeReplay(ctx context.Context, params *eventbridge.DescribeReplayInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DescribeReplayOutput, error)
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// StateMachineQualifiersClient lists the aliases of the state machine.
+type StateMachineQualifiersClient interface {
+	sfnx.ListStateMachineAliasesAPIClient
 }
 
 var (
@@ -34,15 +57,23 @@ var (
 type EventBridgeService interface {
 	SearchRelatedRules(ctx context.Context, params *SearchRelatedRulesInput) (EventBridgeRules, error)
 	DeployRules(ctx context.Context, stateMachineArn string, rules EventBridgeRules, keepState bool) error
+	TestEventPattern(ctx context.Context, eventPattern string, event string) (bool, error)
+	SetRuleEnabled(ctx context.Context, rule *EventBridgeRule, enabled bool) error
+	DeployArchive(ctx context.Context, archive *eventbridge.CreateArchiveInput) (string, error)
+	ReplayArchive(ctx context.Context, params *ReplayArchiveInput) (*eventbridge.DescribeReplayOutput, error)
+	PutEvent(ctx context.Context, eventBusName string, event []byte) (string, error)
 }
 
 var _ EventBridgeService = (*EventBridgeServiceImpl)(nil)
 
 type EventBridgeServiceImpl struct {
 	client             EventBridgeClient
+	qualifiersClient   StateMachineQualifiersClient
 	cacheRuleByName    map[string]*eventbridge.DescribeRuleOutput
 	cacheTargetsByName map[string]*eventbridge.ListTargetsByRuleOutput
 	cacheTagsByName    map[string]*eventbridge.ListTagsForResourceOutput
+	cacheAliasArns     map[string][]string
+	cacheArchiveByName map[string]*eventbridge.DescribeArchiveOutput
 }
 
 func NewEventBridgeService(client EventBridgeClient) *EventBridgeServiceImpl {
@@ -51,12 +82,22 @@ func NewEventBridgeService(client EventBridgeClient) *EventBridgeServiceImpl {
 		cacheRuleByName:    make(map[string]*eventbridge.DescribeRuleOutput),
 		cacheTargetsByName: make(map[string]*eventbridge.ListTargetsByRuleOutput),
 		cacheTagsByName:    make(map[string]*eventbridge.ListTagsForResourceOutput),
+		cacheAliasArns:     make(map[string][]string),
+		cacheArchiveByName: make(map[string]*eventbridge.DescribeArchiveOutput),
 	}
 }
 
+// SetStateMachineQualifiersClient sets the client to search the managed rules that target other aliases of the state machine.
+// without it, the rule of the qualifier removed from the config is not found.
+func (svc *EventBridgeServiceImpl) SetStateMachineQualifiersClient(client StateMachineQualifiersClient) {
+	svc.qualifiersClient = client
+}
+
 type SearchRelatedRulesInput struct {
 	StateMachineQualifiedArn string
 	RuleNames                []string
+	// Qualifiers are the qualifiers of the rules in config, the managed rules that target them are related as well as the aliases.
+	Qualifiers []string
 }
 
 func (svc *EventBridgeServiceImpl) SearchRelatedRules(ctx context.Context, params *SearchRelatedRulesInput) (EventBridgeRules, error) {
@@ -79,7 +120,11 @@ func (svc *EventBridgeServiceImpl) SearchRelatedRules(ctx context.Context, param
 		ruleNames = append(ruleNames, params.RuleNames...)
 		ruleNames = unique(ruleNames)
 	}
-	rules := make(EventBridgeRules, 0, len(ruleNames))
+	otherRuleNames, err := svc.searchOtherQualifierRuleNames(ctx, stateMachineArn, params.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+	rules := make(EventBridgeRules, 0, len(ruleNames)+len(otherRuleNames))
 	for _, name := range ruleNames {
 		rule, err := svc.describeRule(ctx, name, stateMachineArn)
 		if err != nil {
@@ -91,6 +136,24 @@ func (svc *EventBridgeServiceImpl) SearchRelatedRules(ctx context.Context, param
 		}
 		rules = append(rules, rule)
 	}
+	for _, name := range otherRuleNames {
+		if slices.Contains(ruleNames, name) {
+			continue
+		}
+		rule, err := svc.describeRule(ctx, name, stateMachineArn)
+		if err != nil {
+			if !errors.Is(err, ErrEventBridgeRuleDoesNotExist) {
+				return nil, err
+			}
+			continue
+		}
+		// the rule of other qualifiers is related only if stefunny manages it, such as the rule of `qualifier`.
+		if !rule.IsManagedBy() {
+			log.Printf("[debug] rule `%s` targets other qualifier, but not managed by %s", name, appName)
+			continue
+		}
+		rules = append(rules, rule)
+	}
 	sort.Sort(rules)
 	log.Printf("[debug] end SearchRelatedRules() %d rules found", len(rules))
 	return rules, nil
@@ -111,6 +174,67 @@ func (svc *EventBridgeServiceImpl) searchRelatedRuleNames(ctx context.Context, s
 	return ruleNames, nil
 }
 
+// searchOtherQualifierRuleNames returns names of rules that target other aliases of the state machine and the qualifiers in config.
+// the versions are not listed, there may be hundreds of them.
+func (svc *EventBridgeServiceImpl) searchOtherQualifierRuleNames(ctx context.Context, stateMachineArn string, qualifiers []string) ([]string, error) {
+	if svc.qualifiersClient == nil || strings.HasPrefix(stateMachineArn, knownAfterDeployArn) {
+		return nil, nil
+	}
+	unqualified := removeQualifierFromArn(stateMachineArn)
+	qualifiedArns, err := svc.listStateMachineAliasArns(ctx, unqualified)
+	if err != nil {
+		return nil, err
+	}
+	for _, qualifier := range qualifiers {
+		qualifiedArns = append(qualifiedArns, qualifyTriggerTargetArn(stateMachineArn, aws.String(qualifier)))
+	}
+	ruleNames := make([]string, 0)
+	for _, qualifiedArn := range unique(qualifiedArns) {
+		if qualifiedArn == stateMachineArn || qualifiedArn == unqualified {
+			continue
+		}
+		names, err := svc.searchRelatedRuleNames(ctx, qualifiedArn)
+		if err != nil {
+			return nil, err
+		}
+		ruleNames = append(ruleNames, names...)
+	}
+	return unique(ruleNames), nil
+}
+
+// listStateMachineAliasArns returns the arns of the aliases of the state machine.
+func (svc *EventBridgeServiceImpl) listStateMachineAliasArns(ctx context.Context, stateMachineArn string) ([]string, error) {
+	if arns, ok := svc.cacheAliasArns[stateMachineArn]; ok {
+		return arns, nil
+	}
+	arns := make([]string, 0)
+	p := sfnx.NewListStateMachineAliasesPaginator(svc.qualifiersClient, &sfn.ListStateMachineAliasesInput{
+		StateMachineArn: aws.String(stateMachineArn),
+		MaxResults:      32,
+	})
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			var notExist *sfntypes.StateMachineDoesNotExist
+			if errors.As(err, &notExist) {
+				return nil, nil
+			}
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException" {
+				log.Printf("[warn] can not list aliases of `%s`, the rules of other aliases are not searched: %v", stateMachineArn, err)
+				svc.cacheAliasArns[stateMachineArn] = nil
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to list state machine aliases: %w", err)
+		}
+		for _, alias := range output.StateMachineAliases {
+			arns = append(arns, coalesce(alias.StateMachineAliasArn))
+		}
+	}
+	svc.cacheAliasArns[stateMachineArn] = arns
+	return arns, nil
+}
+
 func (svc *EventBridgeServiceImpl) describeRule(ctx context.Context, ruleName string, stateMachineArn string) (*EventBridgeRule, error) {
 	var describeOutput *eventbridge.DescribeRuleOutput
 	var ok bool
@@ -148,6 +272,15 @@ func (svc *EventBridgeServiceImpl) describeRule(ctx context.Context, ruleName st
 		}
 		svc.cacheTagsByName[*describeOutput.Arn] = tagsOutput
 	}
+	// the archive tag is not a tag of the config, it is shown as the archive of the rule.
+	tags := tagsOutput.Tags
+	var archiveName string
+	if i := slices.IndexFunc(tags, func(tag eventbridgetypes.Tag) bool {
+		return coalesce(tag.Key) == tagManagedArchive
+	}); i >= 0 {
+		archiveName = coalesce(tags[i].Value)
+		tags = slices.Delete(slices.Clone(tags), i, i+1)
+	}
 	rule := &EventBridgeRule{
 		PutRuleInput: eventbridge.PutRuleInput{
 			Name:               describeOutput.Name,
@@ -157,43 +290,46 @@ func (svc *EventBridgeServiceImpl) describeRule(ctx context.Context, ruleName st
 			RoleArn:            describeOutput.RoleArn,
 			ScheduleExpression: describeOutput.ScheduleExpression,
 			State:              describeOutput.State,
-			Tags:               tagsOutput.Tags,
+			Tags:               tags,
 		},
 		RuleArn: describeOutput.Arn,
 	}
-	additional := make([]eventbridgetypes.Target, 0, len(listTargetsOutput.Targets))
-	var target *eventbridgetypes.Target
+	if archiveName != "" {
+		archive, err := svc.describeRuleArchive(ctx, ruleName, archiveName)
+		if err != nil {
+			return nil, err
+		}
+		rule.Archive = archive
+	}
 	unqualified := removeQualifierFromArn(stateMachineArn)
 	log.Printf("[debug] state machine arn: %s", stateMachineArn)
 	log.Printf("[debug] unqualified arn: %s", unqualified)
+	// the state machine target is chosen in order of same arn, unqualified arn, other alias arn.
+	// the other targets are additional targets.
+	targetIndex, targetRank := -1, 0
 	for i, t := range listTargetsOutput.Targets {
 		currentArn := coalesce(t.Arn)
 		log.Printf("[debug] current target arn: %s", currentArn)
-		if currentArn == stateMachineArn {
-			if target != nil {
-				additional = append(additional, t)
-			}
+		var rank int
+		switch {
+		case currentArn == stateMachineArn:
 			log.Println("[debug] found same arn target")
-			target = &t
-			additional = append(additional, listTargetsOutput.Targets[:i]...)
-			break
-		}
-		if currentArn == unqualified {
-			if target != nil {
-				additional = append(additional, t)
-				continue
-			}
-			log.Printf("[debug] found unqualified arn target")
-			cloned := t
-			target = &cloned
-			continue
+			rank = 3
+		case currentArn == unqualified:
+			log.Println("[debug] found unqualified arn target")
+			rank = 2
+		case removeQualifierFromArn(currentArn) == unqualified:
+			log.Println("[debug] found other alias arn target")
+			rank = 1
 		}
-		if removeQualifierFromArn(currentArn) == unqualified {
-			if target != nil {
-				additional = append(additional, t)
-				continue
-			}
-			log.Printf("[debug] found other alias arn target")
+		if rank > targetRank {
+			targetIndex, targetRank = i, rank
+		}
+	}
+	additional := make([]eventbridgetypes.Target, 0, len(listTargetsOutput.Targets))
+	var target *eventbridgetypes.Target
+	for i, t := range listTargetsOutput.Targets {
+		if i == targetIndex {
 			cloned := t
 			target = &cloned
 			continue
@@ -212,6 +348,7 @@ func (svc *EventBridgeServiceImpl) DeployRules(ctx context.Context, stateMachine
 	currentRules, err := svc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
 		StateMachineQualifiedArn: stateMachineArn,
 		RuleNames:                rules.Names(),
+		Qualifiers:               rules.Qualifiers(),
 	})
 	if err != nil {
 		return err
@@ -234,16 +371,106 @@ func (svc *EventBridgeServiceImpl) DeployRules(ctx context.Context, stateMachine
 		if err := svc.putRule(ctx, c.After); err != nil {
 			return fmt.Errorf("update rule %s: %w", coalesce(c.After.Name), err)
 		}
+		if err := svc.removeStaleTargets(ctx, c.Before, c.After); err != nil {
+			return fmt.Errorf("update rule %s: %w", coalesce(c.After.Name), err)
+		}
+		if err := svc.deployRuleArchive(ctx, c.After); err != nil {
+			return fmt.Errorf("update rule %s: %w", coalesce(c.After.Name), err)
+		}
+		if err := svc.deleteStaleRuleArchive(ctx, c.Before, c.After); err != nil {
+			return fmt.Errorf("update rule %s: %w", coalesce(c.After.Name), err)
+		}
 	}
 	for _, rule := range plan.Add {
 		log.Println("[info] creating rule:", coalesce(rule.Name))
 		if err := svc.putRule(ctx, rule); err != nil {
 			return fmt.Errorf("create rule %s: %w", coalesce(rule.Name), err)
 		}
+		if err := svc.deployRuleArchive(ctx, rule); err != nil {
+			return fmt.Errorf("create rule %s: %w", coalesce(rule.Name), err)
+		}
+	}
+	return nil
+}
+
+func (svc *EventBridgeServiceImpl) deployRuleArchive(ctx context.Context, rule *EventBridgeRule) error {
+	if rule.Archive == nil {
+		return nil
+	}
+	rule.Archive.EventSourceArn = aws.String(eventBusArnFromRuleArn(coalesce(rule.RuleArn), coalesce(rule.EventBusName)))
+	rule.AppendArchiveManagedByMarker()
+	if _, err := svc.DeployArchive(ctx, rule.Archive); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deleteStaleRuleArchive deletes the archive of the current rule, if it is removed from or renamed in the new rule.
+// rule is nil when the current rule is deleted. the archives that stefunny did not create are not found as the archive of the rule.
+func (svc *EventBridgeServiceImpl) deleteStaleRuleArchive(ctx context.Context, current *EventBridgeRule, rule *EventBridgeRule) error {
+	if current.Archive == nil {
+		return nil
+	}
+	name := coalesce(current.Archive.ArchiveName)
+	if rule != nil && rule.Archive != nil && coalesce(rule.Archive.ArchiveName) == name {
+		return nil
+	}
+	log.Println("[info] delete archive", name)
+	if _, err := svc.client.DeleteArchive(ctx, &eventbridge.DeleteArchiveInput{
+		ArchiveName: aws.String(name),
+	}); err != nil {
+		return fmt.Errorf("failed to delete archive `%s`: %w", name, err)
+	}
+	delete(svc.cacheArchiveByName, name)
+	if rule == nil || rule.Archive != nil {
+		return nil
+	}
+	if _, err := svc.client.UntagResource(ctx, &eventbridge.UntagResourceInput{
+		ResourceARN: rule.RuleArn,
+		TagKeys:     []string{tagManagedArchive},
+	}); err != nil {
+		return fmt.Errorf("untag resource: %w", err)
 	}
 	return nil
 }
 
+// describeRuleArchive returns the archive of the rule, or nil if the archive does not exist or stefunny did not create it.
+func (svc *EventBridgeServiceImpl) describeRuleArchive(ctx context.Context, ruleName string, archiveName string) (*eventbridge.CreateArchiveInput, error) {
+	archive, err := svc.describeArchive(ctx, archiveName)
+	if err != nil {
+		if strings.Contains(err.Error(), "ResourceNotFoundException") {
+			log.Printf("[debug] archive `%s` of rule `%s` does not exist", archiveName, ruleName)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !strings.Contains(coalesce(archive.Description), archiveManagedByMarker(ruleName)) {
+		log.Printf("[warn] archive `%s` of rule `%s` is not created by %s, ignored", archiveName, ruleName, appName)
+		return nil, nil
+	}
+	return &eventbridge.CreateArchiveInput{
+		ArchiveName:    archive.ArchiveName,
+		Description:    archive.Description,
+		EventPattern:   archive.EventPattern,
+		EventSourceArn: archive.EventSourceArn,
+		RetentionDays:  archive.RetentionDays,
+	}, nil
+}
+
+func (svc *EventBridgeServiceImpl) describeArchive(ctx context.Context, name string) (*eventbridge.DescribeArchiveOutput, error) {
+	if archive, ok := svc.cacheArchiveByName[name]; ok {
+		return archive, nil
+	}
+	archive, err := svc.client.DescribeArchive(ctx, &eventbridge.DescribeArchiveInput{
+		ArchiveName: aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe archive `%s`: %w", name, err)
+	}
+	svc.cacheArchiveByName[name] = archive
+	return archive, nil
+}
+
 func (svc *EventBridgeServiceImpl) putRule(ctx context.Context, rule *EventBridgeRule) error {
 	log.Println("[debug] deploy put rule")
 	rule.AppendTags(map[string]string{
@@ -275,9 +502,16 @@ func (svc *EventBridgeServiceImpl) putRule(ctx context.Context, rule *EventBridg
 	rule.AppendTags(map[string]string{
 		tagManagedBy: appName,
 	})
+	tags := rule.Tags
+	if rule.Archive != nil {
+		tags = append(slices.Clone(rule.Tags), eventbridgetypes.Tag{
+			Key:   aws.String(tagManagedArchive),
+			Value: rule.Archive.ArchiveName,
+		})
+	}
 	_, err = svc.client.TagResource(ctx, &eventbridge.TagResourceInput{
 		ResourceARN: putRuleOutput.RuleArn,
-		Tags:        rule.Tags,
+		Tags:        tags,
 	})
 	if err != nil {
 		return err
@@ -290,14 +524,9 @@ func (svc *EventBridgeServiceImpl) deleteRule(ctx context.Context, rule *EventBr
 		log.Printf("[warn] event bridge rule `%s` that %s does not manage. skip delete this rule", coalesce(rule.Name), appName)
 		return nil
 	}
-	targetIDs := make([]string, 0, len(rule.AdditionalTargets)+1)
-	targetIDs = append(targetIDs, coalesce(rule.Target.Id))
-	for _, target := range rule.AdditionalTargets {
-		targetIDs = append(targetIDs, coalesce(target.Id))
-	}
 	log.Println("[debug] deploy remove targets for rule:", coalesce(rule.Name))
 	_, err := svc.client.RemoveTargets(ctx, &eventbridge.RemoveTargetsInput{
-		Ids:          targetIDs,
+		Ids:          rule.TargetIDs(),
 		Rule:         rule.Name,
 		EventBusName: rule.EventBusName,
 	})
@@ -312,5 +541,242 @@ func (svc *EventBridgeServiceImpl) deleteRule(ctx context.Context, rule *EventBr
 	if err != nil {
 		return fmt.Errorf("delete rule: %w", err)
 	}
+	if err := svc.deleteStaleRuleArchive(ctx, rule, nil); err != nil {
+		return fmt.Errorf("delete archives: %w", err)
+	}
+	return nil
+}
+
+// removeStaleTargets removes targets of the current rule that are not in the new rule.
+func (svc *EventBridgeServiceImpl) removeStaleTargets(ctx context.Context, current *EventBridgeRule, rule *EventBridgeRule) error {
+	newIDs := make(map[string]struct{}, len(rule.AdditionalTargets)+1)
+	for _, id := range rule.TargetIDs() {
+		newIDs[id] = struct{}{}
+	}
+	staleIDs := make([]string, 0)
+	for _, id := range current.TargetIDs() {
+		if _, ok := newIDs[id]; !ok {
+			staleIDs = append(staleIDs, id)
+		}
+	}
+	if len(staleIDs) == 0 {
+		return nil
+	}
+	if !current.IsManagedBy() {
+		log.Printf("[warn] event bridge rule `%s` that %s does not manage. skip remove targets %v", coalesce(current.Name), appName, staleIDs)
+		return nil
+	}
+	log.Printf("[debug] deploy remove stale targets %v for rule: %s", staleIDs, coalesce(rule.Name))
+	_, err := svc.client.RemoveTargets(ctx, &eventbridge.RemoveTargetsInput{
+		Ids:          staleIDs,
+		Rule:         rule.Name,
+		EventBusName: rule.EventBusName,
+	})
+	if err != nil {
+		return fmt.Errorf("remove targets: %w", err)
+	}
+	return nil
+}
+
+func (svc *EventBridgeServiceImpl) TestEventPattern(ctx context.Context, eventPattern string, event string) (bool, error) {
+	output, err := svc.client.TestEventPattern(ctx, &eventbridge.TestEventPatternInput{
+		EventPattern: aws.String(eventPattern),
+		Event:        aws.String(event),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to test event pattern: %w", err)
+	}
+	return output.Result, nil
+}
+
+// SetRuleEnabled changes only the state of the rule, other configuration is kept.
+func (svc *EventBridgeServiceImpl) SetRuleEnabled(ctx context.Context, rule *EventBridgeRule, enabled bool) error {
+	if enabled {
+		_, err := svc.client.EnableRule(ctx, &eventbridge.EnableRuleInput{
+			Name:         rule.Name,
+			EventBusName: rule.EventBusName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enable rule `%s`: %w", coalesce(rule.Name), err)
+		}
+	} else {
+		_, err := svc.client.DisableRule(ctx, &eventbridge.DisableRuleInput{
+			Name:         rule.Name,
+			EventBusName: rule.EventBusName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to disable rule `%s`: %w", coalesce(rule.Name), err)
+		}
+	}
+	rule.SetEnabled(enabled)
+	delete(svc.cacheRuleByName, coalesce(rule.Name))
 	return nil
 }
+
+// DeployArchive creates the archive, or updates it if the pattern, the retention or the description differs.
+// it returns the archive arn.
+func (svc *EventBridgeServiceImpl) DeployArchive(ctx context.Context, archive *eventbridge.CreateArchiveInput) (string, error) {
+	name := coalesce(archive.ArchiveName)
+	current, err := svc.client.DescribeArchive(ctx, &eventbridge.DescribeArchiveInput{
+		ArchiveName: archive.ArchiveName,
+	})
+	if err != nil {
+		if !strings.Contains(err.Error(), "ResourceNotFoundException") {
+			return "", fmt.Errorf("failed to describe archive `%s`: %w", name, err)
+		}
+		log.Println("[info] create archive", name)
+		output, err := svc.client.CreateArchive(ctx, archive)
+		if err != nil {
+			return "", fmt.Errorf("failed to create archive `%s`: %w", name, err)
+		}
+		return coalesce(output.ArchiveArn), nil
+	}
+	if coalesce(current.EventSourceArn) != coalesce(archive.EventSourceArn) {
+		return "", fmt.Errorf("archive `%s` already exists for other event bus `%s`", name, coalesce(current.EventSourceArn))
+	}
+	if isSameEventPattern(coalesce(current.EventPattern), coalesce(archive.EventPattern)) &&
+		coalesce(current.RetentionDays) == coalesce(archive.RetentionDays) &&
+		coalesce(current.Description) == coalesce(archive.Description) {
+		log.Println("[debug] archive is up to date", name)
+		return coalesce(current.ArchiveArn), nil
+	}
+	log.Println("[info] update archive", name)
+	output, err := svc.client.UpdateArchive(ctx, &eventbridge.UpdateArchiveInput{
+		ArchiveName:   archive.ArchiveName,
+		Description:   archive.Description,
+		EventPattern:  archive.EventPattern,
+		RetentionDays: archive.RetentionDays,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update archive `%s`: %w", name, err)
+	}
+	return coalesce(output.ArchiveArn), nil
+}
+
+type ReplayArchiveInput struct {
+	ArchiveName    string
+	RuleArn        string
+	EventStartTime time.Time
+	EventEndTime   time.Time
+	// PollInterval is the interval to check the state of the replay, defaults to defaultReplayPollInterval.
+	PollInterval time.Duration
+}
+
+const defaultReplayPollInterval = 5 * time.Second
+
+// maxReplayNameLength is the limit of the replay name of EventBridge.
+const maxReplayNameLength = 64
+
+// ReplayArchive replays the archived events to the rule, and waits until the replay ends.
+func (svc *EventBridgeServiceImpl) ReplayArchive(ctx context.Context, params *ReplayArchiveInput) (*eventbridge.DescribeReplayOutput, error) {
+	archive, err := svc.client.DescribeArchive(ctx, &eventbridge.DescribeArchiveInput{
+		ArchiveName: aws.String(params.ArchiveName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe archive `%s`: %w", params.ArchiveName, err)
+	}
+	if archive.CreationTime != nil && archive.CreationTime.After(params.EventStartTime) {
+		log.Printf("[warn] archive `%s` is created at %s, events before that are not replayed", params.ArchiveName, archive.CreationTime.Format(time.RFC3339))
+	}
+	prefix := params.ArchiveName
+	suffix := "-" + time.Now().UTC().Format("20060102T150405Z")
+	if len(prefix)+len(suffix) > maxReplayNameLength {
+		prefix = prefix[:maxReplayNameLength-len(suffix)]
+	}
+	replayName := prefix + suffix
+	log.Printf("[info] start replay `%s` from %s to %s", replayName, params.EventStartTime.Format(time.RFC3339), params.EventEndTime.Format(time.RFC3339))
+	_, err = svc.client.StartReplay(ctx, &eventbridge.StartReplayInput{
+		ReplayName:     aws.String(replayName),
+		EventSourceArn: archive.ArchiveArn,
+		EventStartTime: aws.Time(params.EventStartTime),
+		EventEndTime:   aws.Time(params.EventEndTime),
+		Destination: &eventbridgetypes.ReplayDestination{
+			Arn:        archive.EventSourceArn,
+			FilterArns: []string{params.RuleArn},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start replay: %w", err)
+	}
+	input := &eventbridge.DescribeReplayInput{
+		ReplayName: aws.String(replayName),
+	}
+	output, err := svc.client.DescribeReplay(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe replay: %w", err)
+	}
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = defaultReplayPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for isReplayRunning(output.State) {
+		log.Printf("[info] replay state: %s", output.State)
+		select {
+		case <-ctx.Done():
+			log.Printf("[warn] stop waiting replay `%s`, the replay continues", replayName)
+			return output, ctx.Err()
+		case <-ticker.C:
+		}
+		output, err = svc.client.DescribeReplay(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe replay: %w", err)
+		}
+	}
+	log.Printf("[info] replay state: %s", output.State)
+	return output, nil
+}
+
+func isReplayRunning(state eventbridgetypes.ReplayState) bool {
+	switch state {
+	case eventbridgetypes.ReplayStateStarting, eventbridgetypes.ReplayStateRunning, eventbridgetypes.ReplayStateCancelling:
+		return true
+	}
+	return false
+}
+
+// PutEvent puts the event to the event bus, the event is the same format as the event that the rule receives.
+// it returns the event id.
+func (svc *EventBridgeServiceImpl) PutEvent(ctx context.Context, eventBusName string, event []byte) (string, error) {
+	var e struct {
+		Source     string          `json:"source"`
+		DetailType string          `json:"detail-type"`
+		Detail     json.RawMessage `json:"detail"`
+		Resources  []string        `json:"resources"`
+		Time       *time.Time      `json:"time"`
+	}
+	if err := json.Unmarshal(event, &e); err != nil {
+		return "", fmt.Errorf("failed to parse event: %w", err)
+	}
+	if e.Source == "" || e.DetailType == "" || len(e.Detail) == 0 {
+		return "", errors.New("event requires source, detail-type and detail")
+	}
+	if strings.HasPrefix(e.Source, "aws.") {
+		log.Printf("[warn] source `%s` is reserved by AWS services, PutEvents may reject the event", e.Source)
+	}
+	entry := eventbridgetypes.PutEventsRequestEntry{
+		Source:     aws.String(e.Source),
+		DetailType: aws.String(e.DetailType),
+		Detail:     aws.String(string(e.Detail)),
+		Resources:  e.Resources,
+		Time:       e.Time,
+	}
+	if eventBusName != "" {
+		entry.EventBusName = aws.String(eventBusName)
+	}
+	output, err := svc.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []eventbridgetypes.PutEventsRequestEntry{entry},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to put events: %w", err)
+	}
+	if output.FailedEntryCount > 0 || len(output.Entries) == 0 {
+		var errorCode, errorMessage string
+		if len(output.Entries) > 0 {
+			errorCode, errorMessage = coalesce(output.Entries[0].ErrorCode), coalesce(output.Entries[0].ErrorMessage)
+		}
+		return "", fmt.Errorf("failed to put event: %s %s", errorCode, errorMessage)
+	}
+	return coalesce(output.Entries[0].EventId), nil
+}
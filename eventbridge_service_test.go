@@ -2,14 +2,19 @@ package stefunny_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
 	"github.com/aws/smithy-go"
 	"github.com/mashiike/stefunny"
 	"github.com/mashiike/stefunny/mock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -169,6 +174,72 @@ func TestEventBridgeService__SearchRealtedRules(t *testing.T) {
 	}, rules)
 }
 
+func TestEventBridgeService__SearchRelatedRulesOfOtherQualifiers(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockEventBridgeClient(ctrl)
+	q := mock.NewMockStateMachineQualifiersClient(ctrl)
+	defer ctrl.Finish()
+
+	const stateMachineArn = "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled"
+	q.EXPECT().ListStateMachineAliases(gomock.Any(), gomock.Any()).Return(
+		&sfn.ListStateMachineAliasesOutput{
+			StateMachineAliases: []sfntypes.StateMachineAliasListItem{
+				{StateMachineAliasArn: aws.String(stateMachineArn + ":current")},
+				{StateMachineAliasArn: aws.String(stateMachineArn + ":canary")},
+			},
+		},
+		nil,
+	).Times(1)
+	// the versions are not listed, only the version in config is searched.
+	ruleNamesByTarget := map[string][]string{
+		stateMachineArn + ":current": {},
+		stateMachineArn:              {},
+		// the rule of the qualifier removed from the config, and the rule created by hand.
+		stateMachineArn + ":canary": {"Canary", "Handmade"},
+		stateMachineArn + ":1":      {},
+	}
+	for targetArn, names := range ruleNamesByTarget {
+		m.EXPECT().ListRuleNamesByTarget(gomock.Any(), &eventbridge.ListRuleNamesByTargetInput{
+			TargetArn: aws.String(targetArn),
+		}).Return(&eventbridge.ListRuleNamesByTargetOutput{RuleNames: names}, nil).Times(1)
+	}
+	for name, tags := range map[string][]eventbridgetypes.Tag{
+		"Canary":   {{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")}},
+		"Handmade": {},
+	} {
+		m.EXPECT().DescribeRule(gomock.Any(), &eventbridge.DescribeRuleInput{
+			Name: aws.String(name),
+		}).Return(&eventbridge.DescribeRuleOutput{
+			Name:         aws.String(name),
+			State:        eventbridgetypes.RuleStateEnabled,
+			Arn:          aws.String("arn:aws:events:us-east-1:000000000000:rule/" + name),
+			EventBusName: aws.String("default"),
+		}, nil).Times(1)
+		m.EXPECT().ListTargetsByRule(gomock.Any(), gomock.Cond(func(input *eventbridge.ListTargetsByRuleInput) bool {
+			return aws.ToString(input.Rule) == name
+		})).Return(&eventbridge.ListTargetsByRuleOutput{
+			Targets: []eventbridgetypes.Target{
+				{Id: aws.String("stefunny-managed"), Arn: aws.String(stateMachineArn + ":canary")},
+			},
+		}, nil).Times(1)
+		m.EXPECT().ListTagsForResource(gomock.Any(), &eventbridge.ListTagsForResourceInput{
+			ResourceARN: aws.String("arn:aws:events:us-east-1:000000000000:rule/" + name),
+		}).Return(&eventbridge.ListTagsForResourceOutput{Tags: tags}, nil).Times(1)
+	}
+
+	svc := stefunny.NewEventBridgeService(m)
+	svc.SetStateMachineQualifiersClient(q)
+	rules, err := svc.SearchRelatedRules(context.Background(), &stefunny.SearchRelatedRulesInput{
+		StateMachineQualifiedArn: stateMachineArn + ":current",
+		Qualifiers:               []string{"1", "$LATEST"},
+	})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, "Canary", aws.ToString(rules[0].Name))
+	require.Equal(t, stateMachineArn+":canary", aws.ToString(rules[0].Target.Arn))
+}
+
 func TestEventBridgeService__DeployRules(t *testing.T) {
 	LoggerSetup(t, "debug")
 	ctrl := gomock.NewController(t)
@@ -216,6 +287,10 @@ func TestEventBridgeService__DeployRules(t *testing.T) {
 					Key:   aws.String("ManagedBy"),
 					Value: aws.String("stefunny"),
 				},
+				{
+					Key:   aws.String("ManagedArchive"),
+					Value: aws.String("Scheduled"),
+				},
 			},
 		},
 		nil,
@@ -246,6 +321,10 @@ func TestEventBridgeService__DeployRules(t *testing.T) {
 					Key:   aws.String("ManagedBy"),
 					Value: aws.String("stefunny"),
 				},
+				{
+					Key:   aws.String("ManagedArchive"),
+					Value: aws.String("Unqualified"),
+				},
 			},
 		},
 		nil,
@@ -301,6 +380,32 @@ func TestEventBridgeService__DeployRules(t *testing.T) {
 		&eventbridge.DeleteRuleOutput{},
 		nil,
 	).Times(1)
+	// the archive of the deleted rule is deleted, and the archive removed from the config of the changed rule is deleted.
+	for _, name := range []string{"Scheduled", "Unqualified"} {
+		m.EXPECT().DescribeArchive(gomock.Any(), &eventbridge.DescribeArchiveInput{
+			ArchiveName: aws.String(name),
+		}).Return(
+			&eventbridge.DescribeArchiveOutput{
+				ArchiveName:    aws.String(name),
+				Description:    aws.String("[ManagedBy=stefunny:" + name + "]"),
+				EventSourceArn: aws.String("arn:aws:events:us-east-1:000000000000:event-bus/default"),
+			},
+			nil,
+		).Times(1)
+		m.EXPECT().DeleteArchive(gomock.Any(), &eventbridge.DeleteArchiveInput{
+			ArchiveName: aws.String(name),
+		}).Return(
+			&eventbridge.DeleteArchiveOutput{},
+			nil,
+		).Times(1)
+	}
+	m.EXPECT().UntagResource(gomock.Any(), &eventbridge.UntagResourceInput{
+		ResourceARN: aws.String("arn:aws:events:us-east-1:000000000000:rule/Scheduled"),
+		TagKeys:     []string{"ManagedArchive"},
+	}).Return(
+		&eventbridge.UntagResourceOutput{},
+		nil,
+	).Times(1)
 
 	m.EXPECT().PutRule(gomock.Any(), &eventbridge.PutRuleInput{
 		Name:         aws.String("Scheduled"),
@@ -420,3 +525,377 @@ func TestEventBridgeService__DeployRules(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestEventBridgeService__DeployRulesRemoveStaleTargets(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockEventBridgeClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().ListRuleNamesByTarget(gomock.Any(), gomock.Any()).Return(
+		&eventbridge.ListRuleNamesByTargetOutput{
+			RuleNames: []string{"Scheduled"},
+		},
+		nil,
+	).Times(2)
+	m.EXPECT().DescribeRule(gomock.Any(), &eventbridge.DescribeRuleInput{
+		Name: aws.String("Scheduled"),
+	}).Return(
+		&eventbridge.DescribeRuleOutput{
+			Name:               aws.String("Scheduled"),
+			State:              eventbridgetypes.RuleStateEnabled,
+			Arn:                aws.String("arn:aws:events:us-east-1:000000000000:rule/Scheduled"),
+			ScheduleExpression: aws.String("rate(1 hour)"),
+			EventBusName:       aws.String("default"),
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().ListTagsForResource(gomock.Any(), &eventbridge.ListTagsForResourceInput{
+		ResourceARN: aws.String("arn:aws:events:us-east-1:000000000000:rule/Scheduled"),
+	}).Return(
+		&eventbridge.ListTagsForResourceOutput{
+			Tags: []eventbridgetypes.Tag{
+				{
+					Key:   aws.String("ManagedBy"),
+					Value: aws.String("stefunny"),
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().ListTargetsByRule(gomock.Any(), gomock.Any()).Return(
+		&eventbridge.ListTargetsByRuleOutput{
+			Targets: []eventbridgetypes.Target{
+				{
+					Id:  aws.String("canary"),
+					Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:canary"),
+				},
+				{
+					Id:  aws.String("old-dlq"),
+					Arn: aws.String("arn:aws:sqs:us-east-1:000000000000:old-dlq"),
+				},
+				{
+					Id:  aws.String("stefunny-managed-state-machine"),
+					Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current"),
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().PutRule(gomock.Any(), gomock.Any()).Return(
+		&eventbridge.PutRuleOutput{
+			RuleArn: aws.String("arn:aws:events:us-east-1:000000000000:rule/Scheduled"),
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().PutTargets(gomock.Any(), &eventbridge.PutTargetsInput{
+		Rule: aws.String("Scheduled"),
+		Targets: []eventbridgetypes.Target{
+			{
+				Id:  aws.String("stefunny-managed-state-machine"),
+				Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current"),
+			},
+			{
+				Id:  aws.String("canary"),
+				Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:canary"),
+			},
+		},
+	}).Return(
+		&eventbridge.PutTargetsOutput{},
+		nil,
+	).Times(1)
+	m.EXPECT().TagResource(gomock.Any(), gomock.Any()).Return(
+		&eventbridge.TagResourceOutput{},
+		nil,
+	).Times(1)
+	m.EXPECT().RemoveTargets(gomock.Any(), &eventbridge.RemoveTargetsInput{
+		Rule:         aws.String("Scheduled"),
+		Ids:          []string{"old-dlq"},
+		EventBusName: aws.String("default"),
+	}).Return(
+		&eventbridge.RemoveTargetsOutput{},
+		nil,
+	).Times(1)
+
+	ctx := context.Background()
+	svc := stefunny.NewEventBridgeService(m)
+	err := svc.DeployRules(ctx, "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current",
+		stefunny.EventBridgeRules{
+			{
+				PutRuleInput: eventbridge.PutRuleInput{
+					Name:               aws.String("Scheduled"),
+					ScheduleExpression: aws.String("rate(1 hour)"),
+					EventBusName:       aws.String("default"),
+				},
+				AdditionalTargets: []eventbridgetypes.Target{
+					{
+						Id:  aws.String("canary"),
+						Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:canary"),
+					},
+				},
+			},
+		},
+		true,
+	)
+	require.NoError(t, err)
+}
+
+func TestEventBridgeRule__SetStateMachineQualifiedArn(t *testing.T) {
+	cases := []struct {
+		name      string
+		qualifier *string
+		expected  string
+	}{
+		{
+			name:     "default",
+			expected: "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current",
+		},
+		{
+			name:      "alias",
+			qualifier: aws.String("canary"),
+			expected:  "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:canary",
+		},
+		{
+			name:      "latest",
+			qualifier: aws.String("$LATEST"),
+			expected:  "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule := &stefunny.EventBridgeRule{Qualifier: c.qualifier}
+			rule.SetStateMachineQualifiedArn("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current")
+			require.Equal(t, c.expected, aws.ToString(rule.Target.Arn))
+
+			schedule := &stefunny.Schedule{Qualifier: c.qualifier}
+			schedule.SetStateMachineQualifiedArn("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current")
+			require.Equal(t, c.expected, aws.ToString(schedule.Target.Arn))
+		})
+	}
+}
+
+func TestEventBridgeRule__DiffStringArchive(t *testing.T) {
+	newRule := func(archive *eventbridge.CreateArchiveInput) *stefunny.EventBridgeRule {
+		return &stefunny.EventBridgeRule{
+			PutRuleInput: eventbridge.PutRuleInput{
+				Name:         aws.String("Event"),
+				EventPattern: aws.String(`{"source":["stefunny"]}`),
+			},
+			Archive: archive,
+		}
+	}
+	current := newRule(&eventbridge.CreateArchiveInput{
+		ArchiveName:    aws.String("Event"),
+		Description:    aws.String("events [ManagedBy=stefunny:Event]"),
+		EventPattern:   aws.String(`{"source":["stefunny"]}`),
+		EventSourceArn: aws.String("arn:aws:events:us-east-1:000000000000:event-bus/default"),
+		RetentionDays:  aws.Int32(0),
+	})
+	t.Run("same", func(t *testing.T) {
+		diff := current.DiffString(newRule(&eventbridge.CreateArchiveInput{
+			ArchiveName:  aws.String("Event"),
+			Description:  aws.String("events"),
+			EventPattern: aws.String(`{"source":["stefunny"]}`),
+		}), true)
+		require.NotContains(t, diff, "Archive")
+	})
+	t.Run("changed", func(t *testing.T) {
+		diff := current.DiffString(newRule(&eventbridge.CreateArchiveInput{
+			ArchiveName:   aws.String("Event"),
+			Description:   aws.String("events"),
+			EventPattern:  aws.String(`{"source":["stefunny"]}`),
+			RetentionDays: aws.Int32(7),
+		}), true)
+		require.Contains(t, diff, `+    "RetentionDays": 7`)
+	})
+	t.Run("removed", func(t *testing.T) {
+		diff := current.DiffString(newRule(nil), true)
+		require.Contains(t, diff, `-  "Archive": {`)
+	})
+}
+
+func TestEventBridgeService__DeployArchive(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockEventBridgeClient(ctrl)
+	defer ctrl.Finish()
+
+	busArn := "arn:aws:events:us-east-1:000000000000:event-bus/default"
+	m.EXPECT().DescribeArchive(gomock.Any(), &eventbridge.DescribeArchiveInput{
+		ArchiveName: aws.String("Created"),
+	}).Return(nil, errors.New("ResourceNotFoundException: archive not found")).Times(1)
+	m.EXPECT().CreateArchive(gomock.Any(), &eventbridge.CreateArchiveInput{
+		ArchiveName:    aws.String("Created"),
+		EventSourceArn: aws.String(busArn),
+		EventPattern:   aws.String(`{"source":["aws.s3"]}`),
+	}).Return(&eventbridge.CreateArchiveOutput{
+		ArchiveArn: aws.String("arn:aws:events:us-east-1:000000000000:archive/Created"),
+	}, nil).Times(1)
+	m.EXPECT().DescribeArchive(gomock.Any(), &eventbridge.DescribeArchiveInput{
+		ArchiveName: aws.String("UpToDate"),
+	}).Return(&eventbridge.DescribeArchiveOutput{
+		ArchiveName:    aws.String("UpToDate"),
+		ArchiveArn:     aws.String("arn:aws:events:us-east-1:000000000000:archive/UpToDate"),
+		EventSourceArn: aws.String(busArn),
+		EventPattern:   aws.String(`{ "source": [ "aws.s3" ] }`),
+	}, nil).Times(1)
+	m.EXPECT().DescribeArchive(gomock.Any(), &eventbridge.DescribeArchiveInput{
+		ArchiveName: aws.String("Updated"),
+	}).Return(&eventbridge.DescribeArchiveOutput{
+		ArchiveName:    aws.String("Updated"),
+		ArchiveArn:     aws.String("arn:aws:events:us-east-1:000000000000:archive/Updated"),
+		EventSourceArn: aws.String(busArn),
+		EventPattern:   aws.String(`{"source":["aws.s3"]}`),
+	}, nil).Times(1)
+	m.EXPECT().UpdateArchive(gomock.Any(), &eventbridge.UpdateArchiveInput{
+		ArchiveName:   aws.String("Updated"),
+		EventPattern:  aws.String(`{"source":["aws.s3"]}`),
+		RetentionDays: aws.Int32(7),
+	}).Return(&eventbridge.UpdateArchiveOutput{
+		ArchiveArn: aws.String("arn:aws:events:us-east-1:000000000000:archive/Updated"),
+	}, nil).Times(1)
+
+	svc := stefunny.NewEventBridgeService(m)
+	for _, c := range []struct {
+		name          string
+		retentionDays *int32
+	}{
+		{name: "Created"},
+		{name: "UpToDate"},
+		{name: "Updated", retentionDays: aws.Int32(7)},
+	} {
+		archiveArn, err := svc.DeployArchive(context.Background(), &eventbridge.CreateArchiveInput{
+			ArchiveName:    aws.String(c.name),
+			EventSourceArn: aws.String(busArn),
+			EventPattern:   aws.String(`{"source":["aws.s3"]}`),
+			RetentionDays:  c.retentionDays,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "arn:aws:events:us-east-1:000000000000:archive/"+c.name, archiveArn)
+	}
+}
+
+func TestEventBridgeService__ReplayArchive(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockEventBridgeClient(ctrl)
+	defer ctrl.Finish()
+
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	m.EXPECT().DescribeArchive(gomock.Any(), &eventbridge.DescribeArchiveInput{
+		ArchiveName: aws.String("Hello"),
+	}).Return(&eventbridge.DescribeArchiveOutput{
+		ArchiveName:    aws.String("Hello"),
+		ArchiveArn:     aws.String("arn:aws:events:us-east-1:000000000000:archive/Hello"),
+		EventSourceArn: aws.String("arn:aws:events:us-east-1:000000000000:event-bus/default"),
+		CreationTime:   aws.Time(from.Add(-24 * time.Hour)),
+	}, nil).Times(1)
+	var replayName string
+	m.EXPECT().StartReplay(gomock.Any(), gomock.Cond(func(input *eventbridge.StartReplayInput) bool {
+		replayName = aws.ToString(input.ReplayName)
+		return assert.EqualValues(t, "arn:aws:events:us-east-1:000000000000:archive/Hello", aws.ToString(input.EventSourceArn)) &&
+			assert.EqualValues(t, from, aws.ToTime(input.EventStartTime)) &&
+			assert.EqualValues(t, to, aws.ToTime(input.EventEndTime)) &&
+			assert.EqualValues(t, &eventbridgetypes.ReplayDestination{
+				Arn:        aws.String("arn:aws:events:us-east-1:000000000000:event-bus/default"),
+				FilterArns: []string{"arn:aws:events:us-east-1:000000000000:rule/Hello"},
+			}, input.Destination)
+	})).Return(&eventbridge.StartReplayOutput{
+		State: eventbridgetypes.ReplayStateStarting,
+	}, nil).Times(1)
+	m.EXPECT().DescribeReplay(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *eventbridge.DescribeReplayInput, _ ...func(*eventbridge.Options)) (*eventbridge.DescribeReplayOutput, error) {
+			require.Equal(t, replayName, aws.ToString(input.ReplayName))
+			return &eventbridge.DescribeReplayOutput{
+				ReplayName: input.ReplayName,
+				State:      eventbridgetypes.ReplayStateCompleted,
+			}, nil
+		},
+	).Times(1)
+
+	svc := stefunny.NewEventBridgeService(m)
+	output, err := svc.ReplayArchive(context.Background(), &stefunny.ReplayArchiveInput{
+		ArchiveName:    "Hello",
+		RuleArn:        "arn:aws:events:us-east-1:000000000000:rule/Hello",
+		EventStartTime: from,
+		EventEndTime:   to,
+	})
+	require.NoError(t, err)
+	require.Equal(t, eventbridgetypes.ReplayStateCompleted, output.State)
+}
+
+func TestEventBridgeService__ReplayArchiveTimeout(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockEventBridgeClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().DescribeArchive(gomock.Any(), gomock.Any()).Return(&eventbridge.DescribeArchiveOutput{
+		ArchiveName:    aws.String("Hello"),
+		ArchiveArn:     aws.String("arn:aws:events:us-east-1:000000000000:archive/Hello"),
+		EventSourceArn: aws.String("arn:aws:events:us-east-1:000000000000:event-bus/default"),
+	}, nil).Times(1)
+	m.EXPECT().StartReplay(gomock.Any(), gomock.Any()).Return(&eventbridge.StartReplayOutput{
+		State: eventbridgetypes.ReplayStateStarting,
+	}, nil).Times(1)
+	m.EXPECT().DescribeReplay(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *eventbridge.DescribeReplayInput, _ ...func(*eventbridge.Options)) (*eventbridge.DescribeReplayOutput, error) {
+			return &eventbridge.DescribeReplayOutput{
+				ReplayName: input.ReplayName,
+				State:      eventbridgetypes.ReplayStateRunning,
+			}, nil
+		},
+	).MinTimes(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	svc := stefunny.NewEventBridgeService(m)
+	output, err := svc.ReplayArchive(ctx, &stefunny.ReplayArchiveInput{
+		ArchiveName:    "Hello",
+		RuleArn:        "arn:aws:events:us-east-1:000000000000:rule/Hello",
+		EventStartTime: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		EventEndTime:   time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC),
+		PollInterval:   10 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, eventbridgetypes.ReplayStateRunning, output.State, "the replay continues")
+}
+
+func TestEventBridgeService__PutEvent(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockEventBridgeClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().PutEvents(gomock.Any(), &eventbridge.PutEventsInput{
+		Entries: []eventbridgetypes.PutEventsRequestEntry{
+			{
+				Source:       aws.String("aws.s3"),
+				DetailType:   aws.String("Object Created"),
+				Detail:       aws.String(`{"bucket":{"name":"hello-bucket"}}`),
+				Resources:    []string{"arn:aws:s3:::hello-bucket"},
+				Time:         aws.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+				EventBusName: aws.String("custom"),
+			},
+		},
+	}).Return(&eventbridge.PutEventsOutput{
+		Entries: []eventbridgetypes.PutEventsResultEntry{
+			{EventId: aws.String("11111111-2222-3333-4444-555555555555")},
+		},
+	}, nil).Times(1)
+
+	svc := stefunny.NewEventBridgeService(m)
+	eventID, err := svc.PutEvent(context.Background(), "custom", []byte(`{
+		"source": "aws.s3",
+		"detail-type": "Object Created",
+		"time": "2024-01-01T00:00:00Z",
+		"resources": ["arn:aws:s3:::hello-bucket"],
+		"detail": {"bucket":{"name":"hello-bucket"}}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, "11111111-2222-3333-4444-555555555555", eventID)
+
+	_, err = svc.PutEvent(context.Background(), "", []byte(`{"source": "hello"}`))
+	require.Error(t, err)
+}
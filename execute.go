@@ -1,16 +1,23 @@
 package stefunny
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/big"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/goccy/go-yaml"
+	"github.com/itchyny/gojq"
 	"github.com/olekukonko/tablewriter"
 	"golang.org/x/term"
 )
@@ -24,9 +31,19 @@ type ExecuteOption struct {
 	Async         bool    `name:"async" help:"start execution and return immediately" json:"async,omitempty"`
 	DumpHistory   bool    `name:"dump-history" help:"dump execution history" json:"dump_history,omitempty"`
 	Qualifier     *string `name:"qualifier" help:"state machine version qualifier" json:"qualifier,omitempty"`
+	Query         string  `name:"query" help:"jq expression to extract fields from execution output" json:"query,omitempty"`
+	OutputFile    string  `name:"output-file" help:"write execution output to file instead of stdout" type:"path" json:"output_file,omitempty"`
+	Format        string  `name:"format" help:"execution output format(raw,json,yaml)" default:"raw" enum:"raw,json,yaml" json:"format,omitempty"`
+
+	At              string `name:"at" help:"schedule a one-off execution at the time instead of starting now (e.g. 2026-10-17T03:00)" json:"at,omitempty"`
+	Timezone        string `name:"timezone" help:"timezone of --at" default:"UTC" json:"timezone,omitempty"`
+	CancelScheduled string `name:"cancel-scheduled" help:"cancel the one-off execution scheduled by --at" placeholder:"SCHEDULE_NAME" json:"cancel_scheduled,omitempty"`
 }
 
 func (app *App) Execute(ctx context.Context, opt ExecuteOption) error {
+	if opt.CancelScheduled != "" {
+		return app.cancelScheduledExecution(ctx, opt)
+	}
 	var inputReader io.Reader
 	if opt.Input == "-" {
 		if term.IsTerminal(int(os.Stdin.Fd())) {
@@ -55,6 +72,9 @@ func (app *App) Execute(ctx context.Context, opt ExecuteOption) error {
 	}
 	input := string(bs)
 	log.Printf("[info] input:\n%s\n", input)
+	if opt.At != "" {
+		return app.scheduleExecution(ctx, opt, input)
+	}
 	stateMachine, err := app.sfnSvc.DescribeStateMachine(ctx, &DescribeStateMachineInput{
 		Name: app.cfg.StateMachineName(),
 	})
@@ -74,9 +94,141 @@ func (app *App) Execute(ctx context.Context, opt ExecuteOption) error {
 		return nil
 	}
 	log.Printf("[info] execution time: %s", output.Elapsed())
-	if !opt.DumpHistory {
+	if opt.DumpHistory {
+		if err := app.dumpExecutionHistory(ctx, opt, output); err != nil {
+			return err
+		}
+	}
+	if output.Datail != nil {
+		log.Printf("[info] execution detail:\n%s", MarshalJSONString(output.Datail))
+	}
+	if output.Failed != nil && *output.Failed {
+		return errors.New("state machine execution failed")
+	}
+	log.Printf("[info] execution success")
+	if output.Output == nil || len(*output.Output) == 0 {
+		return nil
+	}
+	result, err := FormatExecutionOutput(*output.Output, opt.Query, opt.Format)
+	if err != nil {
+		return fmt.Errorf("failed to format execution output: %w", err)
+	}
+	if opt.OutputFile != "" {
+		f, err := createFileWithMkdir(opt.OutputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		if _, err := f.Write(result); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		log.Printf("[info] execution output saved to %s", opt.OutputFile)
 		return nil
 	}
+	if opt.Stdout != nil {
+		if _, err := opt.Stdout.Write(result); err != nil {
+			return fmt.Errorf("failed to write execution output: %w", err)
+		}
+	}
+	return nil
+}
+
+// scheduleExecutionLayouts are the accepted layouts of `execute --at`.
+var scheduleExecutionLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// maxScheduleNameLength is the limit of the schedule name of EventBridge Scheduler.
+const maxScheduleNameLength = 64
+
+func parseScheduleExecutionTime(at string, timezone string) (time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone `%s`: %w", timezone, err)
+	}
+	for _, layout := range scheduleExecutionLayouts {
+		if t, err := time.ParseInLocation(layout, at, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --at `%s`: please YYYY-MM-DDThh:mm[:ss]", at)
+}
+
+func (app *App) scheduleExecution(ctx context.Context, opt ExecuteOption, input string) error {
+	at, err := parseScheduleExecutionTime(opt.At, opt.Timezone)
+	if err != nil {
+		return err
+	}
+	if !at.After(time.Now()) {
+		return fmt.Errorf("--at `%s` has already passed", opt.At)
+	}
+	roleArn := app.scheduledExecutionRoleArn()
+	if roleArn == "" {
+		return errors.New("role for the scheduled execution is not found: please set trigger.schedule[].target.role_arn in config")
+	}
+	stateMachineArn, err := app.sfnSvc.GetStateMachineArn(ctx, &GetStateMachineArnInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get state machine arn: %w", err)
+	}
+	targetArn := qualifyTriggerTargetArn(addQualifierToArn(stateMachineArn, app.StateMachineAliasName()), opt.Qualifier)
+	prefix := app.cfg.StateMachineName()
+	suffix := "-at-" + at.UTC().Format("20060102T150405Z")
+	if len(prefix)+len(suffix) > maxScheduleNameLength {
+		prefix = prefix[:maxScheduleNameLength-len(suffix)]
+	}
+	schedule := &Schedule{
+		CreateScheduleInput: scheduler.CreateScheduleInput{
+			Name:                       aws.String(prefix + suffix),
+			Description:                aws.String(scheduleOneOffMarker + " " + scheduleManagedByMarker),
+			ScheduleExpression:         aws.String("at(" + at.Format("2006-01-02T15:04:05") + ")"),
+			ScheduleExpressionTimezone: aws.String(opt.Timezone),
+			ActionAfterCompletion:      schedulertypes.ActionAfterCompletionDelete,
+			FlexibleTimeWindow: &schedulertypes.FlexibleTimeWindow{
+				Mode: schedulertypes.FlexibleTimeWindowModeOff,
+			},
+			Target: &schedulertypes.Target{
+				Arn:     aws.String(targetArn),
+				RoleArn: aws.String(roleArn),
+				Input:   aws.String(input),
+			},
+		},
+	}
+	if err := app.schedulerSvc.CreateOneOffSchedule(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to schedule execution: %w", err)
+	}
+	log.Printf("[info] execution is scheduled at %s as `%s`", at.Format(time.RFC3339), coalesce(schedule.Name))
+	log.Printf("[info] to cancel, run `%s execute --cancel-scheduled %s`", appName, coalesce(schedule.Name))
+	return nil
+}
+
+// scheduledExecutionRoleArn returns the role of the first schedule in config, that EventBridge Scheduler can assume.
+func (app *App) scheduledExecutionRoleArn() string {
+	for _, schedule := range app.cfg.NewSchedules() {
+		if schedule.Target != nil && coalesce(schedule.Target.RoleArn) != "" {
+			return *schedule.Target.RoleArn
+		}
+	}
+	return ""
+}
+
+func (app *App) cancelScheduledExecution(ctx context.Context, opt ExecuteOption) error {
+	stateMachineArn, err := app.sfnSvc.GetStateMachineArn(ctx, &GetStateMachineArnInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get state machine arn: %w", err)
+	}
+	if err := app.schedulerSvc.DeleteOneOffSchedule(ctx, stateMachineArn, opt.CancelScheduled); err != nil {
+		return fmt.Errorf("failed to cancel scheduled execution: %w", err)
+	}
+	log.Printf("[info] scheduled execution `%s` is canceled", opt.CancelScheduled)
+	return nil
+}
+
+func (app *App) dumpExecutionHistory(ctx context.Context, opt ExecuteOption, output *StartExecutionOutput) error {
 	if output.CanNotDumpHistory {
 		log.Println("[warn] this state machine can not dump history.")
 		return nil
@@ -97,17 +249,107 @@ func (app *App) Execute(ctx context.Context, opt ExecuteOption) error {
 		})
 	}
 	table.Render()
+	return nil
+}
 
-	if output.Datail != nil {
-		log.Printf("[info] execution detail:\n%s", MarshalJSONString(output.Datail))
+// FormatExecutionOutput applies jq query to execution output and formats the results.
+// format is one of raw, json or yaml. raw writes string results without quotes, like `jq -r`.
+func FormatExecutionOutput(output string, query string, format string) ([]byte, error) {
+	if query == "" && (format == "" || format == "raw") {
+		return []byte(output + "\n"), nil
 	}
-	if output.Failed != nil && *output.Failed {
-		return errors.New("state machine execution failed")
+	dec := json.NewDecoder(strings.NewReader(output))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("execution output is not JSON: %w", err)
 	}
-	log.Printf("[info] execution success")
-	if opt.Stdout != nil && output.Output != nil && len(*output.Output) > 0 {
-		io.WriteString(opt.Stdout, *output.Output)
-		io.WriteString(opt.Stdout, "\n")
+	v = normalizeJSONNumbers(v)
+	results := []any{v}
+	if query != "" {
+		q, err := gojq.Parse(query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query: %w", err)
+		}
+		results = results[:0]
+		iter := q.Run(v)
+		for {
+			r, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, ok := r.(error); ok {
+				return nil, fmt.Errorf("failed to run query: %w", err)
+			}
+			results = append(results, r)
+		}
 	}
-	return nil
+	var buf bytes.Buffer
+	for i, r := range results {
+		switch format {
+		case "", "raw":
+			if str, ok := r.(string); ok {
+				buf.WriteString(str + "\n")
+				continue
+			}
+			if err := encodeExecutionOutput(&buf, r, ""); err != nil {
+				return nil, err
+			}
+		case "json":
+			if err := encodeExecutionOutput(&buf, r, "  "); err != nil {
+				return nil, err
+			}
+		case "yaml":
+			if i > 0 {
+				buf.WriteString("---\n")
+			}
+			var b bytes.Buffer
+			if err := encodeExecutionOutput(&b, r, ""); err != nil {
+				return nil, err
+			}
+			bs, err := yaml.JSONToYAML(b.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(bs)
+		default:
+			return nil, fmt.Errorf("unknown format: %s", format)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeJSONNumbers converts json.Number to the numbers of gojq, int or *big.Int for integers not to lose the precision.
+func normalizeJSONNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil && int64(int(i)) == i {
+			return int(i)
+		}
+		if i, ok := new(big.Int).SetString(x.String(), 10); ok {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, vv := range x {
+			x[k] = normalizeJSONNumbers(vv)
+		}
+		return x
+	case []any:
+		for i, vv := range x {
+			x[i] = normalizeJSONNumbers(vv)
+		}
+		return x
+	default:
+		return v
+	}
+}
+
+// encodeExecutionOutput writes v as JSON with the newline, `<`, `>` and `&` are not escaped like jq.
+func encodeExecutionOutput(w io.Writer, v any, indent string) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	return enc.Encode(v)
 }
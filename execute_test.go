@@ -0,0 +1,204 @@
+package stefunny_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFormatExecutionOutput(t *testing.T) {
+	output := `{"name":"hoge","items":[{"id":1},{"id":2}]}`
+	cases := []struct {
+		name     string
+		output   string
+		query    string
+		format   string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "raw",
+			format:   "raw",
+			expected: output + "\n",
+		},
+		{
+			name:     "json",
+			format:   "json",
+			expected: "{\n  \"items\": [\n    {\n      \"id\": 1\n    },\n    {\n      \"id\": 2\n    }\n  ],\n  \"name\": \"hoge\"\n}\n",
+		},
+		{
+			name:     "raw string query",
+			query:    ".name",
+			format:   "raw",
+			expected: "hoge\n",
+		},
+		{
+			name:     "json string query",
+			query:    ".name",
+			format:   "json",
+			expected: "\"hoge\"\n",
+		},
+		{
+			name:     "multiple results",
+			query:    ".items[].id",
+			format:   "raw",
+			expected: "1\n2\n",
+		},
+		{
+			name:     "yaml",
+			query:    ".items[]",
+			format:   "yaml",
+			expected: "id: 1\n---\nid: 2\n",
+		},
+		{
+			name:     "large integer",
+			output:   `{"id":9007199254740993,"epoch_ms":1760745600123,"ratio":0.5}`,
+			query:    ".",
+			format:   "json",
+			expected: "{\n  \"epoch_ms\": 1760745600123,\n  \"id\": 9007199254740993,\n  \"ratio\": 0.5\n}\n",
+		},
+		{
+			name:     "large integer query",
+			output:   `{"ids":[12345678901234567890123]}`,
+			query:    ".ids[0]",
+			format:   "raw",
+			expected: "12345678901234567890123\n",
+		},
+		{
+			name:     "html characters",
+			output:   `{"html":"<a href=\"?a=1&b=2\">"}`,
+			query:    ".",
+			format:   "raw",
+			expected: `{"html":"<a href=\"?a=1&b=2\">"}` + "\n",
+		},
+		{
+			name:   "invalid query",
+			query:  ".items[",
+			format: "raw",
+			isErr:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := output
+			if c.output != "" {
+				o = c.output
+			}
+			actual, err := stefunny.FormatExecutionOutput(o, c.query, c.format)
+			if c.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, string(actual))
+		})
+	}
+}
+
+func TestExecute__OutputFile(t *testing.T) {
+	LoggerSetup(t, "debug")
+	m := NewMocks(t)
+	defer m.Finish()
+	app := newMockApp(t, "testdata/stefunny.yaml", m)
+	stateMachine := &stefunny.StateMachine{
+		CreateStateMachineInput: sfn.CreateStateMachineInput{
+			Name: aws.String("Hello"),
+			Type: sfntypes.StateMachineTypeStandard,
+		},
+		StateMachineArn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Hello"),
+	}
+	m.sfn.EXPECT().DescribeStateMachine(gomock.Any(), &stefunny.DescribeStateMachineInput{
+		Name: "Hello",
+	}).Return(stateMachine, nil).Times(1)
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.sfn.EXPECT().StartExecution(gomock.Any(), stateMachine, gomock.Any()).Return(
+		&stefunny.StartExecutionOutput{
+			ExecutionArn: "arn:aws:states:us-east-1:000000000000:execution:Hello:test",
+			StartDate:    startDate,
+			StopDate:     aws.Time(startDate.Add(time.Second)),
+			Success:      aws.Bool(true),
+			Failed:       aws.Bool(false),
+			Output:       aws.String(`{"result":{"status":"ok"}}`),
+		},
+		nil,
+	).Times(1)
+	var stdout bytes.Buffer
+	outputFile := filepath.Join(t.TempDir(), "output", "result.json")
+	err := app.Execute(context.Background(), stefunny.ExecuteOption{
+		Stdout:     &stdout,
+		Stderr:     &stdout,
+		Input:      "testdata/input.json",
+		Query:      ".result",
+		OutputFile: outputFile,
+		Format:     "json",
+	})
+	require.NoError(t, err)
+	require.Empty(t, stdout.String())
+	bs, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"status\": \"ok\"\n}\n", string(bs))
+}
+
+func TestExecute__At(t *testing.T) {
+	LoggerSetup(t, "debug")
+	m := NewMocks(t)
+	defer m.Finish()
+	app := newMockApp(t, "testdata/schedule.yaml", m)
+	at := time.Now().In(time.UTC).Add(24 * time.Hour).Truncate(time.Minute)
+	m.sfn.EXPECT().GetStateMachineArn(gomock.Any(), &stefunny.GetStateMachineArnInput{
+		Name: "Scheduled",
+	}).Return("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled", nil).Times(1)
+	m.scheduler.EXPECT().CreateOneOffSchedule(gomock.Any(), gomock.Cond(func(schedule *stefunny.Schedule) bool {
+		return aws.ToString(schedule.Name) == "Scheduled-at-"+at.Format("20060102T150405Z") &&
+			aws.ToString(schedule.ScheduleExpression) == "at("+at.Format("2006-01-02T15:04:05")+")" &&
+			aws.ToString(schedule.ScheduleExpressionTimezone) == "UTC" &&
+			schedule.ActionAfterCompletion == schedulertypes.ActionAfterCompletionDelete &&
+			aws.ToString(schedule.Target.RoleArn) == "arn:aws:iam::012345678901:role/service-role/Eventbridge-Hello-role" &&
+			aws.ToString(schedule.Target.Arn) == "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current" &&
+			aws.ToString(schedule.Target.Input) == "{\n  \"Comment\": \"This is a comment\"\n}" &&
+			schedule.IsOneOff()
+	})).Return(nil).Times(1)
+	err := app.Execute(context.Background(), stefunny.ExecuteOption{
+		Input:    "testdata/input.json",
+		At:       at.Format("2006-01-02T15:04"),
+		Timezone: "UTC",
+	})
+	require.NoError(t, err)
+
+	err = app.Execute(context.Background(), stefunny.ExecuteOption{
+		Input:    "testdata/input.json",
+		At:       "2000-01-01T00:00",
+		Timezone: "UTC",
+	})
+	require.ErrorContains(t, err, "has already passed")
+}
+
+func TestExecute__CancelScheduled(t *testing.T) {
+	LoggerSetup(t, "debug")
+	m := NewMocks(t)
+	defer m.Finish()
+	app := newMockApp(t, "testdata/schedule.yaml", m)
+	m.sfn.EXPECT().GetStateMachineArn(gomock.Any(), &stefunny.GetStateMachineArnInput{
+		Name: "Scheduled",
+	}).Return("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled", nil).Times(1)
+	m.scheduler.EXPECT().DeleteOneOffSchedule(
+		gomock.Any(),
+		"arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled",
+		"Scheduled-at-20261016T180000Z",
+	).Return(nil).Times(1)
+	err := app.Execute(context.Background(), stefunny.ExecuteOption{
+		CancelScheduled: "Scheduled-at-20261016T180000Z",
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,225 @@
+package stefunny
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+type ExecutionsOption struct {
+	Diff ExecutionsDiffOption `cmd:"" help:"Show diff of two executions" json:"diff,omitempty"`
+}
+
+type ExecutionsDiffOption struct {
+	Base    string `arg:"" name:"a" help:"base execution arn or name" json:"base,omitempty"`
+	Target  string `arg:"" name:"b" help:"target execution arn or name" json:"target,omitempty"`
+	Unified bool   `name:"unified" help:"output in unified format" short:"u" default:"true" negatable:"" json:"unified,omitempty"`
+}
+
+func (app *App) ExecutionsDiff(ctx context.Context, opt ExecutionsDiffOption) error {
+	baseArn, err := app.resolveExecutionArn(ctx, opt.Base)
+	if err != nil {
+		return fmt.Errorf("failed to resolve execution `%s`: %w", opt.Base, err)
+	}
+	targetArn, err := app.resolveExecutionArn(ctx, opt.Target)
+	if err != nil {
+		return fmt.Errorf("failed to resolve execution `%s`: %w", opt.Target, err)
+	}
+	baseEvents, err := app.sfnSvc.GetExecutionHistory(ctx, baseArn)
+	if err != nil {
+		return fmt.Errorf("failed to get execution history `%s`: %w", baseArn, err)
+	}
+	targetEvents, err := app.sfnSvc.GetExecutionHistory(ctx, targetArn)
+	if err != nil {
+		return fmt.Errorf("failed to get execution history `%s`: %w", targetArn, err)
+	}
+	base := NewExecutionTrace(baseArn, baseEvents)
+	target := NewExecutionTrace(targetArn, targetEvents)
+	ds := strings.TrimSpace(base.DiffString(target, opt.Unified))
+	if ds != "" {
+		fmt.Println(ds)
+	}
+	return nil
+}
+
+// resolveExecutionArn returns execution arn, if given execution name, build arn from the state machine arn.
+func (app *App) resolveExecutionArn(ctx context.Context, nameOrArn string) (string, error) {
+	if strings.HasPrefix(nameOrArn, "arn:") {
+		return nameOrArn, nil
+	}
+	stateMachineArn, err := app.sfnSvc.GetStateMachineArn(ctx, &GetStateMachineArnInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return executionArnFromStateMachineArn(stateMachineArn, nameOrArn)
+}
+
+// ExecutionTrace is the path and state details of an execution, built from the execution history.
+type ExecutionTrace struct {
+	ExecutionArn string
+	States       *OrderdMap[string, *StateTrace]
+}
+
+type StateTrace struct {
+	Key       string
+	Name      string
+	Input     *string
+	Output    *string
+	EnteredAt time.Time
+	ExitedAt  *time.Time
+}
+
+func (s *StateTrace) Duration() time.Duration {
+	if s == nil || s.ExitedAt == nil {
+		return -1
+	}
+	return s.ExitedAt.Sub(s.EnteredAt)
+}
+
+// NewExecutionTrace builds ExecutionTrace from history events.
+// A state visited more than once is keyed as `Name[n]` (n is the 0 origin visit count)
+// The exited event is matched to the entered event by the PreviousEventId chain,
+// because the iterations of Map and Parallel states run concurrently and their events are interleaved.
+func NewExecutionTrace(executionArn string, events []HistoryEvent) *ExecutionTrace {
+	trace := &ExecutionTrace{
+		ExecutionArn: executionArn,
+		States:       NewOrderdMap[string, *StateTrace](),
+	}
+	visits := make(map[string]int)
+	// owners is the innermost state not exited yet on the chain of the event id.
+	owners := make(map[int64]*StateTrace)
+	parents := make(map[*StateTrace]*StateTrace)
+	for _, event := range events {
+		owner := owners[event.PreviousEventId]
+		if event.StateEnteredEventDetails != nil {
+			name := coalesce(event.StateEnteredEventDetails.Name)
+			key := name
+			if n := visits[name]; n > 0 {
+				key = fmt.Sprintf("%s[%d]", name, n)
+			}
+			visits[name]++
+			state := &StateTrace{
+				Key:       key,
+				Name:      name,
+				Input:     event.StateEnteredEventDetails.Input,
+				EnteredAt: coalesce(event.Timestamp),
+			}
+			parents[state] = owner
+			owners[event.Id] = state
+			trace.States.Set(key, state)
+			continue
+		}
+		if event.StateExitedEventDetails != nil {
+			name := coalesce(event.StateExitedEventDetails.Name)
+			state := owner
+			for state != nil && state.Name != name {
+				state = parents[state]
+			}
+			if state == nil {
+				log.Printf("[debug] state `%s` exited without entered event", name)
+				continue
+			}
+			state.Output = event.StateExitedEventDetails.Output
+			state.ExitedAt = event.Timestamp
+			if parent := parents[state]; parent != nil {
+				owners[event.Id] = parent
+			}
+			continue
+		}
+		if owner != nil {
+			owners[event.Id] = owner
+		}
+	}
+	return trace
+}
+
+func (t *ExecutionTrace) Path() []string {
+	path := make([]string, 0, t.States.Len())
+	for _, state := range t.States.Values() {
+		path = append(path, state.Name)
+	}
+	return path
+}
+
+func (t *ExecutionTrace) DiffString(other *ExecutionTrace, unified bool) string {
+	var builder strings.Builder
+	pathFrom := MarshalJSONString(t.Path())
+	pathTo := MarshalJSONString(other.Path())
+	if ds := JSONDiffString(pathFrom, pathTo,
+		JSONDiffFromURI(t.ExecutionArn+"#path"),
+		JSONDiffToURI(other.ExecutionArn+"#path"),
+		JSONDiffUnified(unified),
+	); strings.TrimSpace(ds) != "" {
+		builder.WriteString(colorRestString("[Path]\n"))
+		builder.WriteString(strings.TrimRight(ds, "\n"))
+		builder.WriteString("\n\n")
+	}
+	keys := make([]string, 0, t.States.Len()+other.States.Len())
+	keys = append(keys, t.States.Keys()...)
+	for _, key := range other.States.Keys() {
+		if _, ok := t.States.Get(key); !ok {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		from, _ := t.States.Get(key)
+		to, _ := other.States.Get(key)
+		builder.WriteString(colorRestString(fmt.Sprintf("[State: %s]\n", key)))
+		fmt.Fprintf(&builder, "Duration: %s -> %s", formatStateDuration(from), formatStateDuration(to))
+		if from.Duration() >= 0 && to.Duration() >= 0 {
+			fmt.Fprintf(&builder, " (%+.3fs)", (to.Duration() - from.Duration()).Seconds())
+		}
+		builder.WriteRune('\n')
+		for _, field := range []string{"input", "output"} {
+			ds := JSONDiffString(
+				stateTraceJSON(from, field),
+				stateTraceJSON(to, field),
+				JSONDiffFromURI(t.ExecutionArn+"#"+key+"."+field),
+				JSONDiffToURI(other.ExecutionArn+"#"+key+"."+field),
+				JSONDiffUnified(unified),
+			)
+			if strings.TrimSpace(ds) != "" {
+				builder.WriteString(strings.TrimRight(ds, "\n"))
+				builder.WriteRune('\n')
+			}
+		}
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+func formatStateDuration(s *StateTrace) string {
+	if s == nil {
+		return "-"
+	}
+	d := s.Duration()
+	if d < 0 {
+		return "(not exited)"
+	}
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+func stateTraceJSON(s *StateTrace, field string) string {
+	if s == nil {
+		return "null"
+	}
+	var str string
+	switch field {
+	case "input":
+		str = coalesce(s.Input)
+	case "output":
+		str = coalesce(s.Output)
+	}
+	if str == "" {
+		return "null"
+	}
+	if !json.Valid([]byte(str)) {
+		return MarshalJSONString(str)
+	}
+	return str
+}
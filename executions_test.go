@@ -0,0 +1,209 @@
+package stefunny_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/fatih/color"
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestHistory(start time.Time, steps ...[3]string) []stefunny.HistoryEvent {
+	events := make([]stefunny.HistoryEvent, 0, len(steps)*2)
+	ts := start
+	for i, step := range steps {
+		events = append(events, stefunny.HistoryEvent{
+			StartDate: start,
+			Step:      step[0],
+			HistoryEvent: sfntypes.HistoryEvent{
+				Id:              int64(i*2 + 1),
+				PreviousEventId: int64(i * 2),
+				Type:            sfntypes.HistoryEventTypePassStateEntered,
+				Timestamp:       aws.Time(ts),
+				StateEnteredEventDetails: &sfntypes.StateEnteredEventDetails{
+					Name:  aws.String(step[0]),
+					Input: aws.String(step[1]),
+				},
+			},
+		})
+		ts = ts.Add(time.Second)
+		events = append(events, stefunny.HistoryEvent{
+			StartDate: start,
+			Step:      step[0],
+			HistoryEvent: sfntypes.HistoryEvent{
+				Id:              int64(i*2 + 2),
+				PreviousEventId: int64(i*2 + 1),
+				Type:            sfntypes.HistoryEventTypePassStateExited,
+				Timestamp:       aws.Time(ts),
+				StateExitedEventDetails: &sfntypes.StateExitedEventDetails{
+					Name:   aws.String(step[0]),
+					Output: aws.String(step[2]),
+				},
+			},
+		})
+	}
+	return events
+}
+
+func TestExecutionTrace(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trace := stefunny.NewExecutionTrace("arn:aws:states:us-east-1:000000000000:execution:Hello:a", newTestHistory(
+		start,
+		[3]string{"Hello", `{}`, `{"count":1}`},
+		[3]string{"Loop", `{"count":1}`, `{"count":2}`},
+		[3]string{"Loop", `{"count":2}`, `{"count":3}`},
+	))
+	require.Equal(t, []string{"Hello", "Loop", "Loop"}, trace.Path())
+	require.Equal(t, []string{"Hello", "Loop", "Loop[1]"}, trace.States.Keys())
+	state, ok := trace.States.Get("Loop[1]")
+	require.True(t, ok)
+	require.Equal(t, `{"count":3}`, *state.Output)
+	require.Equal(t, time.Second, state.Duration())
+}
+
+func TestExecutionTrace__MapIterations(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := func(id, previousID int64, sec int, e sfntypes.HistoryEvent) stefunny.HistoryEvent {
+		e.Id = id
+		e.PreviousEventId = previousID
+		e.Timestamp = aws.Time(start.Add(time.Duration(sec) * time.Second))
+		return stefunny.HistoryEvent{StartDate: start, HistoryEvent: e}
+	}
+	entered := func(name, input string) sfntypes.HistoryEvent {
+		return sfntypes.HistoryEvent{
+			StateEnteredEventDetails: &sfntypes.StateEnteredEventDetails{Name: aws.String(name), Input: aws.String(input)},
+		}
+	}
+	exited := func(name, output string) sfntypes.HistoryEvent {
+		return sfntypes.HistoryEvent{
+			StateExitedEventDetails: &sfntypes.StateExitedEventDetails{Name: aws.String(name), Output: aws.String(output)},
+		}
+	}
+	// two iterations of the Map state run concurrently, and the second one exits first.
+	trace := stefunny.NewExecutionTrace("arn:aws:states:us-east-1:000000000000:execution:Hello:a", []stefunny.HistoryEvent{
+		event(1, 0, 0, entered("Map", `[1,2]`)),
+		event(2, 1, 0, sfntypes.HistoryEvent{Type: sfntypes.HistoryEventTypeMapStateStarted}),
+		event(3, 2, 0, sfntypes.HistoryEvent{Type: sfntypes.HistoryEventTypeMapIterationStarted}),
+		event(4, 2, 0, sfntypes.HistoryEvent{Type: sfntypes.HistoryEventTypeMapIterationStarted}),
+		event(5, 3, 1, entered("Double", `1`)),
+		event(6, 4, 1, entered("Double", `2`)),
+		event(7, 6, 2, exited("Double", `4`)),
+		event(8, 5, 4, exited("Double", `2`)),
+		event(9, 7, 4, sfntypes.HistoryEvent{Type: sfntypes.HistoryEventTypeMapIterationSucceeded}),
+		event(10, 8, 4, sfntypes.HistoryEvent{Type: sfntypes.HistoryEventTypeMapIterationSucceeded}),
+		event(11, 10, 5, sfntypes.HistoryEvent{Type: sfntypes.HistoryEventTypeMapStateSucceeded}),
+		event(12, 11, 5, exited("Map", `[2,4]`)),
+	})
+	require.Equal(t, []string{"Map", "Double", "Double[1]"}, trace.States.Keys())
+	first, ok := trace.States.Get("Double")
+	require.True(t, ok)
+	require.Equal(t, `2`, *first.Output)
+	require.Equal(t, 3*time.Second, first.Duration())
+	second, ok := trace.States.Get("Double[1]")
+	require.True(t, ok)
+	require.Equal(t, `4`, *second.Output)
+	require.Equal(t, time.Second, second.Duration())
+	m, ok := trace.States.Get("Map")
+	require.True(t, ok)
+	require.Equal(t, `[2,4]`, *m.Output)
+	require.Equal(t, 5*time.Second, m.Duration())
+}
+
+func TestExecutionTrace__DiffString(t *testing.T) {
+	color.NoColor = true
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := stefunny.NewExecutionTrace("a", newTestHistory(
+		start,
+		[3]string{"Hello", `{}`, `{"count":1}`},
+		[3]string{"World", `{"count":1}`, `{"count":2}`},
+	))
+	target := stefunny.NewExecutionTrace("b", newTestHistory(
+		start,
+		[3]string{"Hello", `{}`, `{"count":10}`},
+		[3]string{"Wait", `{"count":10}`, `{"count":10}`},
+	))
+	expected := `[Path]
+--- a#path
++++ b#path
+@@ -1,4 +1,4 @@
+ [
+   "Hello",
+-  "World"
++  "Wait"
+ ]
+
+[State: Hello]
+Duration: 1.000s -> 1.000s (+0.000s)
+--- a#Hello.output
++++ b#Hello.output
+@@ -1,3 +1,3 @@
+ {
+-  "count": 1
++  "count": 10
+ }
+
+[State: World]
+Duration: 1.000s -> -
+--- a#World.input
++++ b#World.input
+@@ -1,3 +1 @@
+-{
+-  "count": 1
+-}
++
+--- a#World.output
++++ b#World.output
+@@ -1,3 +1 @@
+-{
+-  "count": 2
+-}
++
+
+[State: Wait]
+Duration: - -> 1.000s
+--- a#Wait.input
++++ b#Wait.input
+@@ -1 +1,3 @@
+-
++{
++  "count": 10
++}
+--- a#Wait.output
++++ b#Wait.output
+@@ -1 +1,3 @@
+-
++{
++  "count": 10
++}
+
+`
+	require.Equal(t, expected, base.DiffString(target, true))
+}
+
+func TestExecutionsDiff__ResolveName(t *testing.T) {
+	LoggerSetup(t, "debug")
+	m := NewMocks(t)
+	defer m.Finish()
+	app := newMockApp(t, "testdata/stefunny.yaml", m)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.sfn.EXPECT().GetStateMachineArn(gomock.Any(), &stefunny.GetStateMachineArnInput{
+		Name: "Hello",
+	}).Return("arn:aws:states:us-east-1:000000000000:stateMachine:Hello", nil).AnyTimes()
+	m.sfn.EXPECT().GetExecutionHistory(gomock.Any(), "arn:aws:states:us-east-1:000000000000:execution:Hello:first").Return(
+		newTestHistory(start, [3]string{"Hello", `{}`, `{}`}), nil,
+	).Times(1)
+	m.sfn.EXPECT().GetExecutionHistory(gomock.Any(), "arn:aws:states:us-east-1:000000000000:execution:Hello:second").Return(
+		newTestHistory(start, [3]string{"Hello", `{}`, `{}`}), nil,
+	).Times(1)
+	err := app.ExecutionsDiff(context.Background(), stefunny.ExecutionsDiffOption{
+		Base:    "first",
+		Target:  "arn:aws:states:us-east-1:000000000000:execution:Hello:second",
+		Unified: true,
+	})
+	require.NoError(t, err)
+}
@@ -6,18 +6,21 @@ import (
 	"log"
 	"path/filepath"
 
-	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/pipes"
 )
 
 type InitOption struct {
-	StateMachineName   string   `name:"state-machine" help:"AWS StepFunctions state machine name" required:"" env:"STATE_MACHINE_NAME" json:"state_machine_name,omitempty"`
-	DefinitionFilePath string   `name:"definition" short:"d" help:"Path to state machine definition file" type:"path" env:"DEFINITION_FILE_PATH" json:"definition_file_path,omitempty"`
-	TFState            string   `kong:"-" help:"Path to terraform state file" type:"path" json:"tfstate,omitempty"` // TODO: if removed global flag, not ignore this flag for kong
-	Envs               []string `name:"env" help:"templateize environment variables" json:"envs,omitempty"`
-	MustEnvs           []string `name:"must-env" help:"templateize must environment variables" json:"must_envs,omitempty"`
-	SkipTrigger        bool     `name:"skip-trigger" help:"Skip trigger" json:"skip_trigger,omitempty"`
-	ConfigPath         string   `kong:"-" json:"-"`
-	AWSRegion          string   `kong:"-" json:"-"`
+	StateMachineName   string         `name:"state-machine" help:"AWS StepFunctions state machine name" required:"" env:"STATE_MACHINE_NAME" json:"state_machine_name,omitempty"`
+	DefinitionFilePath string         `name:"definition" short:"d" help:"Path to state machine definition file" type:"path" env:"DEFINITION_FILE_PATH" json:"definition_file_path,omitempty"`
+	TFState            string         `kong:"-" help:"Path to terraform state file" type:"path" json:"tfstate,omitempty"` // TODO: if removed global flag, not ignore this flag for kong
+	CFnStacks          []string       `name:"cfn-stack" help:"templateize outputs and exports of CloudFormation stack" json:"cfn_stacks,omitempty"`
+	Envs               []string       `name:"template-env" help:"templateize environment variables" json:"envs,omitempty"`
+	MustEnvs           []string       `name:"must-env" help:"templateize must environment variables" json:"must_envs,omitempty"`
+	SkipTrigger        bool           `name:"skip-trigger" help:"Skip trigger" json:"skip_trigger,omitempty"`
+	TemplateizeAWS     bool           `name:"templateize-aws-identity" help:"templateize ARNs, account ids and regions of the caller with aws_account_id, aws_region and arn" json:"templateize_aws_identity,omitempty"`
+	ConfigPath         string         `kong:"-" json:"-"`
+	AWSRegion          string         `kong:"-" json:"-"`
+	Vars               map[string]any `kong:"-" json:"-"`
 }
 
 func (app *App) Init(ctx context.Context, opt InitOption) error {
@@ -43,13 +46,17 @@ func (app *App) Init(ctx context.Context, opt InitOption) error {
 	if opt.AWSRegion != "" {
 		cfg.AWSRegion = opt.AWSRegion
 	}
+	if opt.TemplateizeAWS {
+		cfg.AWSIdentity = app.cfg.AWSIdentity
+	}
 
-	templateize, err := prepareForTamplatize(cfg, opt.TFState, opt.Envs, opt.MustEnvs)
+	templateize, err := prepareForTamplatize(cfg, opt.TFState, opt.CFnStacks, opt.Envs, opt.MustEnvs, opt.Vars)
 	if err != nil {
 		return fmt.Errorf("failed prepare for templateize: %w", err)
 	}
 
 	renderer := NewRenderer(cfg)
+	renderer.SetCloudFormationClient(app.cfnClient)
 	log.Printf("[notice] StateMachine/%s save config to %s", opt.StateMachineName, opt.ConfigPath)
 	if err := renderer.CreateConfigFile(ctx, opt.ConfigPath, templateize); err != nil {
 		return fmt.Errorf("failed create config file: %w", err)
@@ -99,16 +106,25 @@ func (app *App) makeTrigerConfig(ctx context.Context, stateMachine *StateMachine
 	if err != nil {
 		return nil, fmt.Errorf("failed search related schedules: %w", err)
 	}
+	relatedPipes, err := app.pipesSvc.SearchRelatedPipes(ctx, &SearchRelatedPipesInput{
+		StateMachineQualifiedArn: stateMachine.QualifiedArn(app.StateMachineAliasName()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed search related pipes: %w", err)
+	}
+	stateMachineQualifiedArn := stateMachine.QualifiedArn(app.StateMachineAliasName())
 	trigger := &TriggerConfig{}
 	if len(rules) > 0 {
 		for _, rule := range rules {
 			rule.DeleteTag(tagManagedBy)
+			qualifier := triggerQualifierFromArn(coalesce(rule.Target.Arn), stateMachineQualifiedArn)
 			rule.Target.Arn = nil
 			eventsRule := TriggerEventConfig{
 				KeysToSnakeCase: KeysToSnakeCase[TriggerEventConfigInner]{
 					Value: TriggerEventConfigInner{
 						PutRuleInput: rule.PutRuleInput,
 						Target:       rule.Target,
+						Qualifier:    qualifier,
 					},
 					Strict: true,
 				},
@@ -122,19 +138,25 @@ func (app *App) makeTrigerConfig(ctx context.Context, stateMachine *StateMachine
 				eventsRule.Value.RoleArn = nil
 			}
 			if len(rule.AdditionalTargets) > 0 {
-				log.Printf("[debug] StateMachine/%s has additional targets, skip non related target", coalesce(stateMachine.Name))
+				eventsRule.Value.Targets = rule.AdditionalTargets
 			}
 			trigger.Event = append(trigger.Event, eventsRule)
 		}
 	}
 	if len(schedules) > 0 {
 		for _, schedule := range schedules {
+			var qualifier *string
 			if schedule.Target != nil {
+				qualifier = triggerQualifierFromArn(coalesce(schedule.Target.Arn), stateMachineQualifiedArn)
 				schedule.Target.Arn = nil
 			}
+			schedule.RemoveManagedByMarker()
 			scheduleRule := TriggerScheduleConfig{
-				KeysToSnakeCase: KeysToSnakeCase[scheduler.CreateScheduleInput]{
-					Value:  schedule.CreateScheduleInput,
+				KeysToSnakeCase: KeysToSnakeCase[TriggerScheduleConfigInner]{
+					Value: TriggerScheduleConfigInner{
+						CreateScheduleInput: schedule.CreateScheduleInput,
+						Qualifier:           qualifier,
+					},
 					Strict: true,
 				},
 			}
@@ -142,11 +164,28 @@ func (app *App) makeTrigerConfig(ctx context.Context, stateMachine *StateMachine
 			trigger.Schedule = append(trigger.Schedule, scheduleRule)
 		}
 	}
+	for _, p := range relatedPipes {
+		p.DeleteTag(tagManagedBy)
+		p.Target = nil
+		pipeCfg := TriggerPipeConfig{
+			KeysToSnakeCase: KeysToSnakeCase[pipes.CreatePipeInput]{
+				Value:  p.CreatePipeInput,
+				Strict: true,
+			},
+		}
+		pipeCfg.Value.DesiredState = ""
+		trigger.Pipe = append(trigger.Pipe, pipeCfg)
+	}
 	return trigger, nil
 }
 
-func prepareForTamplatize(cfg *Config, tfstateLoc string, envs []string, mustEnvs []string) (bool, error) {
-	var templateize bool
+func prepareForTamplatize(cfg *Config, tfstateLoc string, cfnStacks []string, envs []string, mustEnvs []string, vars map[string]any) (bool, error) {
+	templateize := cfg.AWSIdentity != nil
+	if len(vars) > 0 {
+		cfg.Vars = vars
+		cfg.VarValues = varValues(vars)
+		templateize = true
+	}
 	if len(envs) > 0 {
 		envsMap := NewOrderdMap[string, string]()
 		envFunc := newTemplateFuncEnv(envsMap)
@@ -158,7 +197,7 @@ func prepareForTamplatize(cfg *Config, tfstateLoc string, envs []string, mustEnv
 	}
 	if len(mustEnvs) > 0 {
 		mustEnvsMap := NewOrderdMap[string, string]()
-		missingEnvs := make(map[string]struct{})
+		missingEnvs := make(map[string]string)
 		mustEnvFunc := newTemplatefuncMustEnv(mustEnvsMap, missingEnvs)
 		for _, env := range mustEnvs {
 			mustEnvFunc(env)
@@ -180,5 +219,11 @@ func prepareForTamplatize(cfg *Config, tfstateLoc string, envs []string, mustEnv
 		}
 		templateize = true
 	}
+	for _, stack := range cfnStacks {
+		cfg.CFn = append(cfg.CFn, &CFnConfig{
+			Stack: stack,
+		})
+		templateize = true
+	}
 	return templateize, nil
 }
@@ -13,7 +13,11 @@ import (
 	context "context"
 	reflect "reflect"
 
+	cloudformation "github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	cloudwatchlogs "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	secretsmanager "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	ssm "github.com/aws/aws-sdk-go-v2/service/ssm"
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -60,3 +64,199 @@ func (mr *MockCloudWatchLogsClientMockRecorder) DescribeLogGroups(arg0, arg1 any
 	varargs := append([]any{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroups", reflect.TypeOf((*MockCloudWatchLogsClient)(nil).DescribeLogGroups), varargs...)
 }
+
+// MockSSMClient is a mock of SSMClient interface.
+type MockSSMClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSMClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSSMClientMockRecorder is the mock recorder for MockSSMClient.
+type MockSSMClientMockRecorder struct {
+	mock *MockSSMClient
+}
+
+// NewMockSSMClient creates a new mock instance.
+func NewMockSSMClient(ctrl *gomock.Controller) *MockSSMClient {
+	mock := &MockSSMClient{ctrl: ctrl}
+	mock.recorder = &MockSSMClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSMClient) EXPECT() *MockSSMClientMockRecorder {
+	return m.recorder
+}
+
+// GetParameter mocks base method.
+func (m *MockSSMClient) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetParameter", varargs...)
+	ret0, _ := ret[0].(*ssm.GetParameterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParameter indicates an expected call of GetParameter.
+func (mr *MockSSMClientMockRecorder) GetParameter(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameter", reflect.TypeOf((*MockSSMClient)(nil).GetParameter), varargs...)
+}
+
+// MockSecretsManagerClient is a mock of SecretsManagerClient interface.
+type MockSecretsManagerClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretsManagerClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSecretsManagerClientMockRecorder is the mock recorder for MockSecretsManagerClient.
+type MockSecretsManagerClientMockRecorder struct {
+	mock *MockSecretsManagerClient
+}
+
+// NewMockSecretsManagerClient creates a new mock instance.
+func NewMockSecretsManagerClient(ctrl *gomock.Controller) *MockSecretsManagerClient {
+	mock := &MockSecretsManagerClient{ctrl: ctrl}
+	mock.recorder = &MockSecretsManagerClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretsManagerClient) EXPECT() *MockSecretsManagerClientMockRecorder {
+	return m.recorder
+}
+
+// GetSecretValue mocks base method.
+func (m *MockSecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetSecretValue", varargs...)
+	ret0, _ := ret[0].(*secretsmanager.GetSecretValueOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretValue indicates an expected call of GetSecretValue.
+func (mr *MockSecretsManagerClientMockRecorder) GetSecretValue(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretValue", reflect.TypeOf((*MockSecretsManagerClient)(nil).GetSecretValue), varargs...)
+}
+
+// MockSTSClient is a mock of STSClient interface.
+type MockSTSClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSTSClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSTSClientMockRecorder is the mock recorder for MockSTSClient.
+type MockSTSClientMockRecorder struct {
+	mock *MockSTSClient
+}
+
+// NewMockSTSClient creates a new mock instance.
+func NewMockSTSClient(ctrl *gomock.Controller) *MockSTSClient {
+	mock := &MockSTSClient{ctrl: ctrl}
+	mock.recorder = &MockSTSClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSTSClient) EXPECT() *MockSTSClientMockRecorder {
+	return m.recorder
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockSTSClient) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockSTSClientMockRecorder) GetCallerIdentity(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockSTSClient)(nil).GetCallerIdentity), varargs...)
+}
+
+// MockCloudFormationClient is a mock of CloudFormationClient interface.
+type MockCloudFormationClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudFormationClientMockRecorder
+	isgomock struct{}
+}
+
+// MockCloudFormationClientMockRecorder is the mock recorder for MockCloudFormationClient.
+type MockCloudFormationClientMockRecorder struct {
+	mock *MockCloudFormationClient
+}
+
+// NewMockCloudFormationClient creates a new mock instance.
+func NewMockCloudFormationClient(ctrl *gomock.Controller) *MockCloudFormationClient {
+	mock := &MockCloudFormationClient{ctrl: ctrl}
+	mock.recorder = &MockCloudFormationClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudFormationClient) EXPECT() *MockCloudFormationClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeStacks mocks base method.
+func (m *MockCloudFormationClient) DescribeStacks(arg0 context.Context, arg1 *cloudformation.DescribeStacksInput, arg2 ...func(*cloudformation.Options)) (*cloudformation.DescribeStacksOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeStacks", varargs...)
+	ret0, _ := ret[0].(*cloudformation.DescribeStacksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeStacks indicates an expected call of DescribeStacks.
+func (mr *MockCloudFormationClientMockRecorder) DescribeStacks(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeStacks", reflect.TypeOf((*MockCloudFormationClient)(nil).DescribeStacks), varargs...)
+}
+
+// ListExports mocks base method.
+func (m *MockCloudFormationClient) ListExports(arg0 context.Context, arg1 *cloudformation.ListExportsInput, arg2 ...func(*cloudformation.Options)) (*cloudformation.ListExportsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListExports", varargs...)
+	ret0, _ := ret[0].(*cloudformation.ListExportsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExports indicates an expected call of ListExports.
+func (mr *MockCloudFormationClientMockRecorder) ListExports(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExports", reflect.TypeOf((*MockCloudFormationClient)(nil).ListExports), varargs...)
+}
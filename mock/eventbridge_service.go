@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	eventbridge "github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	sfn "github.com/aws/aws-sdk-go-v2/service/sfn"
 	stefunny "github.com/mashiike/stefunny"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,46 @@ func (m *MockEventBridgeClient) EXPECT() *MockEventBridgeClientMockRecorder {
 	return m.recorder
 }
 
+// CreateArchive mocks base method.
+func (m *MockEventBridgeClient) CreateArchive(ctx context.Context, params *eventbridge.CreateArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.CreateArchiveOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateArchive", varargs...)
+	ret0, _ := ret[0].(*eventbridge.CreateArchiveOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateArchive indicates an expected call of CreateArchive.
+func (mr *MockEventBridgeClientMockRecorder) CreateArchive(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateArchive", reflect.TypeOf((*MockEventBridgeClient)(nil).CreateArchive), varargs...)
+}
+
+// DeleteArchive mocks base method.
+func (m *MockEventBridgeClient) DeleteArchive(ctx context.Context, params *eventbridge.DeleteArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DeleteArchiveOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteArchive", varargs...)
+	ret0, _ := ret[0].(*eventbridge.DeleteArchiveOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteArchive indicates an expected call of DeleteArchive.
+func (mr *MockEventBridgeClientMockRecorder) DeleteArchive(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArchive", reflect.TypeOf((*MockEventBridgeClient)(nil).DeleteArchive), varargs...)
+}
+
 // DeleteRule mocks base method.
 func (m *MockEventBridgeClient) DeleteRule(ctx context.Context, params *eventbridge.DeleteRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DeleteRuleOutput, error) {
 	m.ctrl.T.Helper()
@@ -62,6 +103,46 @@ func (mr *MockEventBridgeClientMockRecorder) DeleteRule(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockEventBridgeClient)(nil).DeleteRule), varargs...)
 }
 
+// DescribeArchive mocks base method.
+func (m *MockEventBridgeClient) DescribeArchive(ctx context.Context, params *eventbridge.DescribeArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DescribeArchiveOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeArchive", varargs...)
+	ret0, _ := ret[0].(*eventbridge.DescribeArchiveOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeArchive indicates an expected call of DescribeArchive.
+func (mr *MockEventBridgeClientMockRecorder) DescribeArchive(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeArchive", reflect.TypeOf((*MockEventBridgeClient)(nil).DescribeArchive), varargs...)
+}
+
+// DescribeReplay mocks base method.
+func (m *MockEventBridgeClient) DescribeReplay(ctx context.Context, params *eventbridge.DescribeReplayInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DescribeReplayOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeReplay", varargs...)
+	ret0, _ := ret[0].(*eventbridge.DescribeReplayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeReplay indicates an expected call of DescribeReplay.
+func (mr *MockEventBridgeClientMockRecorder) DescribeReplay(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeReplay", reflect.TypeOf((*MockEventBridgeClient)(nil).DescribeReplay), varargs...)
+}
+
 // DescribeRule mocks base method.
 func (m *MockEventBridgeClient) DescribeRule(ctx context.Context, params *eventbridge.DescribeRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DescribeRuleOutput, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +163,46 @@ func (mr *MockEventBridgeClientMockRecorder) DescribeRule(ctx, params any, optFn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRule", reflect.TypeOf((*MockEventBridgeClient)(nil).DescribeRule), varargs...)
 }
 
+// DisableRule mocks base method.
+func (m *MockEventBridgeClient) DisableRule(ctx context.Context, params *eventbridge.DisableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DisableRuleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisableRule", varargs...)
+	ret0, _ := ret[0].(*eventbridge.DisableRuleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableRule indicates an expected call of DisableRule.
+func (mr *MockEventBridgeClientMockRecorder) DisableRule(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableRule", reflect.TypeOf((*MockEventBridgeClient)(nil).DisableRule), varargs...)
+}
+
+// EnableRule mocks base method.
+func (m *MockEventBridgeClient) EnableRule(ctx context.Context, params *eventbridge.EnableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.EnableRuleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnableRule", varargs...)
+	ret0, _ := ret[0].(*eventbridge.EnableRuleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableRule indicates an expected call of EnableRule.
+func (mr *MockEventBridgeClientMockRecorder) EnableRule(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableRule", reflect.TypeOf((*MockEventBridgeClient)(nil).EnableRule), varargs...)
+}
+
 // ListRuleNamesByTarget mocks base method.
 func (m *MockEventBridgeClient) ListRuleNamesByTarget(ctx context.Context, params *eventbridge.ListRuleNamesByTargetInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRuleNamesByTargetOutput, error) {
 	m.ctrl.T.Helper()
@@ -142,6 +263,26 @@ func (mr *MockEventBridgeClientMockRecorder) ListTargetsByRule(ctx, params any,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTargetsByRule", reflect.TypeOf((*MockEventBridgeClient)(nil).ListTargetsByRule), varargs...)
 }
 
+// PutEvents mocks base method.
+func (m *MockEventBridgeClient) PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutEvents", varargs...)
+	ret0, _ := ret[0].(*eventbridge.PutEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutEvents indicates an expected call of PutEvents.
+func (mr *MockEventBridgeClientMockRecorder) PutEvents(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutEvents", reflect.TypeOf((*MockEventBridgeClient)(nil).PutEvents), varargs...)
+}
+
 // PutRule mocks base method.
 func (m *MockEventBridgeClient) PutRule(ctx context.Context, params *eventbridge.PutRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutRuleOutput, error) {
 	m.ctrl.T.Helper()
@@ -202,6 +343,26 @@ func (mr *MockEventBridgeClientMockRecorder) RemoveTargets(ctx, params any, optF
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTargets", reflect.TypeOf((*MockEventBridgeClient)(nil).RemoveTargets), varargs...)
 }
 
+// StartReplay mocks base method.
+func (m *MockEventBridgeClient) StartReplay(ctx context.Context, params *eventbridge.StartReplayInput, optFns ...func(*eventbridge.Options)) (*eventbridge.StartReplayOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartReplay", varargs...)
+	ret0, _ := ret[0].(*eventbridge.StartReplayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartReplay indicates an expected call of StartReplay.
+func (mr *MockEventBridgeClientMockRecorder) StartReplay(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartReplay", reflect.TypeOf((*MockEventBridgeClient)(nil).StartReplay), varargs...)
+}
+
 // TagResource mocks base method.
 func (m *MockEventBridgeClient) TagResource(ctx context.Context, params *eventbridge.TagResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.TagResourceOutput, error) {
 	m.ctrl.T.Helper()
@@ -222,6 +383,110 @@ func (mr *MockEventBridgeClientMockRecorder) TagResource(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockEventBridgeClient)(nil).TagResource), varargs...)
 }
 
+// TestEventPattern mocks base method.
+func (m *MockEventBridgeClient) TestEventPattern(ctx context.Context, params *eventbridge.TestEventPatternInput, optFns ...func(*eventbridge.Options)) (*eventbridge.TestEventPatternOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TestEventPattern", varargs...)
+	ret0, _ := ret[0].(*eventbridge.TestEventPatternOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestEventPattern indicates an expected call of TestEventPattern.
+func (mr *MockEventBridgeClientMockRecorder) TestEventPattern(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestEventPattern", reflect.TypeOf((*MockEventBridgeClient)(nil).TestEventPattern), varargs...)
+}
+
+// UntagResource mocks base method.
+func (m *MockEventBridgeClient) UntagResource(ctx context.Context, params *eventbridge.UntagResourceInput, optFns ...func(*eventbridge.Options)) (*eventbridge.UntagResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UntagResource", varargs...)
+	ret0, _ := ret[0].(*eventbridge.UntagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntagResource indicates an expected call of UntagResource.
+func (mr *MockEventBridgeClientMockRecorder) UntagResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockEventBridgeClient)(nil).UntagResource), varargs...)
+}
+
+// UpdateArchive mocks base method.
+func (m *MockEventBridgeClient) UpdateArchive(ctx context.Context, params *eventbridge.UpdateArchiveInput, optFns ...func(*eventbridge.Options)) (*eventbridge.UpdateArchiveOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateArchive", varargs...)
+	ret0, _ := ret[0].(*eventbridge.UpdateArchiveOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateArchive indicates an expected call of UpdateArchive.
+func (mr *MockEventBridgeClientMockRecorder) UpdateArchive(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateArchive", reflect.TypeOf((*MockEventBridgeClient)(nil).UpdateArchive), varargs...)
+}
+
+// MockStateMachineQualifiersClient is a mock of StateMachineQualifiersClient interface.
+type MockStateMachineQualifiersClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStateMachineQualifiersClientMockRecorder
+	isgomock struct{}
+}
+
+// MockStateMachineQualifiersClientMockRecorder is the mock recorder for MockStateMachineQualifiersClient.
+type MockStateMachineQualifiersClientMockRecorder struct {
+	mock *MockStateMachineQualifiersClient
+}
+
+// NewMockStateMachineQualifiersClient creates a new mock instance.
+func NewMockStateMachineQualifiersClient(ctrl *gomock.Controller) *MockStateMachineQualifiersClient {
+	mock := &MockStateMachineQualifiersClient{ctrl: ctrl}
+	mock.recorder = &MockStateMachineQualifiersClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStateMachineQualifiersClient) EXPECT() *MockStateMachineQualifiersClientMockRecorder {
+	return m.recorder
+}
+
+// ListStateMachineAliases mocks base method.
+func (m *MockStateMachineQualifiersClient) ListStateMachineAliases(ctx context.Context, params *sfn.ListStateMachineAliasesInput, optFns ...func(*sfn.Options)) (*sfn.ListStateMachineAliasesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListStateMachineAliases", varargs...)
+	ret0, _ := ret[0].(*sfn.ListStateMachineAliasesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStateMachineAliases indicates an expected call of ListStateMachineAliases.
+func (mr *MockStateMachineQualifiersClientMockRecorder) ListStateMachineAliases(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStateMachineAliases", reflect.TypeOf((*MockStateMachineQualifiersClient)(nil).ListStateMachineAliases), varargs...)
+}
+
 // MockEventBridgeService is a mock of EventBridgeService interface.
 type MockEventBridgeService struct {
 	ctrl     *gomock.Controller
@@ -246,6 +511,21 @@ func (m *MockEventBridgeService) EXPECT() *MockEventBridgeServiceMockRecorder {
 	return m.recorder
 }
 
+// DeployArchive mocks base method.
+func (m *MockEventBridgeService) DeployArchive(ctx context.Context, archive *eventbridge.CreateArchiveInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeployArchive", ctx, archive)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeployArchive indicates an expected call of DeployArchive.
+func (mr *MockEventBridgeServiceMockRecorder) DeployArchive(ctx, archive any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployArchive", reflect.TypeOf((*MockEventBridgeService)(nil).DeployArchive), ctx, archive)
+}
+
 // DeployRules mocks base method.
 func (m *MockEventBridgeService) DeployRules(ctx context.Context, stateMachineArn string, rules stefunny.EventBridgeRules, keepState bool) error {
 	m.ctrl.T.Helper()
@@ -260,6 +540,36 @@ func (mr *MockEventBridgeServiceMockRecorder) DeployRules(ctx, stateMachineArn,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployRules", reflect.TypeOf((*MockEventBridgeService)(nil).DeployRules), ctx, stateMachineArn, rules, keepState)
 }
 
+// PutEvent mocks base method.
+func (m *MockEventBridgeService) PutEvent(ctx context.Context, eventBusName string, event []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutEvent", ctx, eventBusName, event)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutEvent indicates an expected call of PutEvent.
+func (mr *MockEventBridgeServiceMockRecorder) PutEvent(ctx, eventBusName, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutEvent", reflect.TypeOf((*MockEventBridgeService)(nil).PutEvent), ctx, eventBusName, event)
+}
+
+// ReplayArchive mocks base method.
+func (m *MockEventBridgeService) ReplayArchive(ctx context.Context, params *stefunny.ReplayArchiveInput) (*eventbridge.DescribeReplayOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayArchive", ctx, params)
+	ret0, _ := ret[0].(*eventbridge.DescribeReplayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayArchive indicates an expected call of ReplayArchive.
+func (mr *MockEventBridgeServiceMockRecorder) ReplayArchive(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayArchive", reflect.TypeOf((*MockEventBridgeService)(nil).ReplayArchive), ctx, params)
+}
+
 // SearchRelatedRules mocks base method.
 func (m *MockEventBridgeService) SearchRelatedRules(ctx context.Context, params *stefunny.SearchRelatedRulesInput) (stefunny.EventBridgeRules, error) {
 	m.ctrl.T.Helper()
@@ -274,3 +584,32 @@ func (mr *MockEventBridgeServiceMockRecorder) SearchRelatedRules(ctx, params any
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchRelatedRules", reflect.TypeOf((*MockEventBridgeService)(nil).SearchRelatedRules), ctx, params)
 }
+
+// SetRuleEnabled mocks base method.
+func (m *MockEventBridgeService) SetRuleEnabled(ctx context.Context, rule *stefunny.EventBridgeRule, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRuleEnabled", ctx, rule, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRuleEnabled indicates an expected call of SetRuleEnabled.
+func (mr *MockEventBridgeServiceMockRecorder) SetRuleEnabled(ctx, rule, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRuleEnabled", reflect.TypeOf((*MockEventBridgeService)(nil).SetRuleEnabled), ctx, rule, enabled)
+}
+
+// TestEventPattern mocks base method.
+func (m *MockEventBridgeService) TestEventPattern(ctx context.Context, eventPattern, event string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestEventPattern", ctx, eventPattern, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestEventPattern indicates an expected call of TestEventPattern.
+func (mr *MockEventBridgeServiceMockRecorder) TestEventPattern(ctx, eventPattern, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestEventPattern", reflect.TypeOf((*MockEventBridgeService)(nil).TestEventPattern), ctx, eventPattern, event)
+}
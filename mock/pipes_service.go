@@ -0,0 +1,216 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pipes_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pipes_service.go -destination=./mock/pipes_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	pipes "github.com/aws/aws-sdk-go-v2/service/pipes"
+	stefunny "github.com/mashiike/stefunny"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPipesClient is a mock of PipesClient interface.
+type MockPipesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPipesClientMockRecorder
+	isgomock struct{}
+}
+
+// MockPipesClientMockRecorder is the mock recorder for MockPipesClient.
+type MockPipesClientMockRecorder struct {
+	mock *MockPipesClient
+}
+
+// NewMockPipesClient creates a new mock instance.
+func NewMockPipesClient(ctrl *gomock.Controller) *MockPipesClient {
+	mock := &MockPipesClient{ctrl: ctrl}
+	mock.recorder = &MockPipesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPipesClient) EXPECT() *MockPipesClientMockRecorder {
+	return m.recorder
+}
+
+// CreatePipe mocks base method.
+func (m *MockPipesClient) CreatePipe(ctx context.Context, params *pipes.CreatePipeInput, optFns ...func(*pipes.Options)) (*pipes.CreatePipeOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreatePipe", varargs...)
+	ret0, _ := ret[0].(*pipes.CreatePipeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePipe indicates an expected call of CreatePipe.
+func (mr *MockPipesClientMockRecorder) CreatePipe(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePipe", reflect.TypeOf((*MockPipesClient)(nil).CreatePipe), varargs...)
+}
+
+// DeletePipe mocks base method.
+func (m *MockPipesClient) DeletePipe(ctx context.Context, params *pipes.DeletePipeInput, optFns ...func(*pipes.Options)) (*pipes.DeletePipeOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeletePipe", varargs...)
+	ret0, _ := ret[0].(*pipes.DeletePipeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePipe indicates an expected call of DeletePipe.
+func (mr *MockPipesClientMockRecorder) DeletePipe(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePipe", reflect.TypeOf((*MockPipesClient)(nil).DeletePipe), varargs...)
+}
+
+// DescribePipe mocks base method.
+func (m *MockPipesClient) DescribePipe(ctx context.Context, params *pipes.DescribePipeInput, optFns ...func(*pipes.Options)) (*pipes.DescribePipeOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribePipe", varargs...)
+	ret0, _ := ret[0].(*pipes.DescribePipeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePipe indicates an expected call of DescribePipe.
+func (mr *MockPipesClientMockRecorder) DescribePipe(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePipe", reflect.TypeOf((*MockPipesClient)(nil).DescribePipe), varargs...)
+}
+
+// ListPipes mocks base method.
+func (m *MockPipesClient) ListPipes(arg0 context.Context, arg1 *pipes.ListPipesInput, arg2 ...func(*pipes.Options)) (*pipes.ListPipesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPipes", varargs...)
+	ret0, _ := ret[0].(*pipes.ListPipesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPipes indicates an expected call of ListPipes.
+func (mr *MockPipesClientMockRecorder) ListPipes(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPipes", reflect.TypeOf((*MockPipesClient)(nil).ListPipes), varargs...)
+}
+
+// TagResource mocks base method.
+func (m *MockPipesClient) TagResource(ctx context.Context, params *pipes.TagResourceInput, optFns ...func(*pipes.Options)) (*pipes.TagResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TagResource", varargs...)
+	ret0, _ := ret[0].(*pipes.TagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResource indicates an expected call of TagResource.
+func (mr *MockPipesClientMockRecorder) TagResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockPipesClient)(nil).TagResource), varargs...)
+}
+
+// UpdatePipe mocks base method.
+func (m *MockPipesClient) UpdatePipe(ctx context.Context, params *pipes.UpdatePipeInput, optFns ...func(*pipes.Options)) (*pipes.UpdatePipeOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdatePipe", varargs...)
+	ret0, _ := ret[0].(*pipes.UpdatePipeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePipe indicates an expected call of UpdatePipe.
+func (mr *MockPipesClientMockRecorder) UpdatePipe(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePipe", reflect.TypeOf((*MockPipesClient)(nil).UpdatePipe), varargs...)
+}
+
+// MockPipesService is a mock of PipesService interface.
+type MockPipesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPipesServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPipesServiceMockRecorder is the mock recorder for MockPipesService.
+type MockPipesServiceMockRecorder struct {
+	mock *MockPipesService
+}
+
+// NewMockPipesService creates a new mock instance.
+func NewMockPipesService(ctrl *gomock.Controller) *MockPipesService {
+	mock := &MockPipesService{ctrl: ctrl}
+	mock.recorder = &MockPipesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPipesService) EXPECT() *MockPipesServiceMockRecorder {
+	return m.recorder
+}
+
+// DeployPipes mocks base method.
+func (m *MockPipesService) DeployPipes(ctx context.Context, stateMachineArn string, pipes stefunny.Pipes, keepState bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeployPipes", ctx, stateMachineArn, pipes, keepState)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeployPipes indicates an expected call of DeployPipes.
+func (mr *MockPipesServiceMockRecorder) DeployPipes(ctx, stateMachineArn, pipes, keepState any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployPipes", reflect.TypeOf((*MockPipesService)(nil).DeployPipes), ctx, stateMachineArn, pipes, keepState)
+}
+
+// SearchRelatedPipes mocks base method.
+func (m *MockPipesService) SearchRelatedPipes(ctx context.Context, params *stefunny.SearchRelatedPipesInput) (stefunny.Pipes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchRelatedPipes", ctx, params)
+	ret0, _ := ret[0].(stefunny.Pipes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchRelatedPipes indicates an expected call of SearchRelatedPipes.
+func (mr *MockPipesServiceMockRecorder) SearchRelatedPipes(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchRelatedPipes", reflect.TypeOf((*MockPipesService)(nil).SearchRelatedPipes), ctx, params)
+}
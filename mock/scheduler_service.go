@@ -62,6 +62,26 @@ func (mr *MockSchedulerClientMockRecorder) CreateSchedule(ctx, params any, optFn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockSchedulerClient)(nil).CreateSchedule), varargs...)
 }
 
+// CreateScheduleGroup mocks base method.
+func (m *MockSchedulerClient) CreateScheduleGroup(ctx context.Context, params *scheduler.CreateScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleGroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateScheduleGroup", varargs...)
+	ret0, _ := ret[0].(*scheduler.CreateScheduleGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduleGroup indicates an expected call of CreateScheduleGroup.
+func (mr *MockSchedulerClientMockRecorder) CreateScheduleGroup(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduleGroup", reflect.TypeOf((*MockSchedulerClient)(nil).CreateScheduleGroup), varargs...)
+}
+
 // DeleteSchedule mocks base method.
 func (m *MockSchedulerClient) DeleteSchedule(ctx context.Context, params *scheduler.DeleteScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +102,26 @@ func (mr *MockSchedulerClientMockRecorder) DeleteSchedule(ctx, params any, optFn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSchedule", reflect.TypeOf((*MockSchedulerClient)(nil).DeleteSchedule), varargs...)
 }
 
+// DeleteScheduleGroup mocks base method.
+func (m *MockSchedulerClient) DeleteScheduleGroup(ctx context.Context, params *scheduler.DeleteScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleGroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteScheduleGroup", varargs...)
+	ret0, _ := ret[0].(*scheduler.DeleteScheduleGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScheduleGroup indicates an expected call of DeleteScheduleGroup.
+func (mr *MockSchedulerClientMockRecorder) DeleteScheduleGroup(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduleGroup", reflect.TypeOf((*MockSchedulerClient)(nil).DeleteScheduleGroup), varargs...)
+}
+
 // GetSchedule mocks base method.
 func (m *MockSchedulerClient) GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
 	m.ctrl.T.Helper()
@@ -102,6 +142,26 @@ func (mr *MockSchedulerClientMockRecorder) GetSchedule(ctx, params any, optFns .
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockSchedulerClient)(nil).GetSchedule), varargs...)
 }
 
+// GetScheduleGroup mocks base method.
+func (m *MockSchedulerClient) GetScheduleGroup(ctx context.Context, params *scheduler.GetScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleGroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetScheduleGroup", varargs...)
+	ret0, _ := ret[0].(*scheduler.GetScheduleGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduleGroup indicates an expected call of GetScheduleGroup.
+func (mr *MockSchedulerClientMockRecorder) GetScheduleGroup(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleGroup", reflect.TypeOf((*MockSchedulerClient)(nil).GetScheduleGroup), varargs...)
+}
+
 // ListScheduleGroups mocks base method.
 func (m *MockSchedulerClient) ListScheduleGroups(ctx context.Context, params *scheduler.ListScheduleGroupsInput, optFns ...func(*scheduler.Options)) (*scheduler.ListScheduleGroupsOutput, error) {
 	m.ctrl.T.Helper()
@@ -142,6 +202,46 @@ func (mr *MockSchedulerClientMockRecorder) ListSchedules(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockSchedulerClient)(nil).ListSchedules), varargs...)
 }
 
+// ListTagsForResource mocks base method.
+func (m *MockSchedulerClient) ListTagsForResource(ctx context.Context, params *scheduler.ListTagsForResourceInput, optFns ...func(*scheduler.Options)) (*scheduler.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsForResource", varargs...)
+	ret0, _ := ret[0].(*scheduler.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource.
+func (mr *MockSchedulerClientMockRecorder) ListTagsForResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockSchedulerClient)(nil).ListTagsForResource), varargs...)
+}
+
+// TagResource mocks base method.
+func (m *MockSchedulerClient) TagResource(ctx context.Context, params *scheduler.TagResourceInput, optFns ...func(*scheduler.Options)) (*scheduler.TagResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TagResource", varargs...)
+	ret0, _ := ret[0].(*scheduler.TagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResource indicates an expected call of TagResource.
+func (mr *MockSchedulerClientMockRecorder) TagResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockSchedulerClient)(nil).TagResource), varargs...)
+}
+
 // UpdateSchedule mocks base method.
 func (m *MockSchedulerClient) UpdateSchedule(ctx context.Context, params *scheduler.UpdateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
 	m.ctrl.T.Helper()
@@ -186,6 +286,48 @@ func (m *MockSchedulerService) EXPECT() *MockSchedulerServiceMockRecorder {
 	return m.recorder
 }
 
+// CreateOneOffSchedule mocks base method.
+func (m *MockSchedulerService) CreateOneOffSchedule(ctx context.Context, schedule *stefunny.Schedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOneOffSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOneOffSchedule indicates an expected call of CreateOneOffSchedule.
+func (mr *MockSchedulerServiceMockRecorder) CreateOneOffSchedule(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOneOffSchedule", reflect.TypeOf((*MockSchedulerService)(nil).CreateOneOffSchedule), ctx, schedule)
+}
+
+// DeleteOneOffSchedule mocks base method.
+func (m *MockSchedulerService) DeleteOneOffSchedule(ctx context.Context, stateMachineArn, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOneOffSchedule", ctx, stateMachineArn, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOneOffSchedule indicates an expected call of DeleteOneOffSchedule.
+func (mr *MockSchedulerServiceMockRecorder) DeleteOneOffSchedule(ctx, stateMachineArn, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOneOffSchedule", reflect.TypeOf((*MockSchedulerService)(nil).DeleteOneOffSchedule), ctx, stateMachineArn, name)
+}
+
+// DeployScheduleGroups mocks base method.
+func (m *MockSchedulerService) DeployScheduleGroups(ctx context.Context, stateMachineName string, groups stefunny.ScheduleGroups) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeployScheduleGroups", ctx, stateMachineName, groups)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeployScheduleGroups indicates an expected call of DeployScheduleGroups.
+func (mr *MockSchedulerServiceMockRecorder) DeployScheduleGroups(ctx, stateMachineName, groups any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployScheduleGroups", reflect.TypeOf((*MockSchedulerService)(nil).DeployScheduleGroups), ctx, stateMachineName, groups)
+}
+
 // DeploySchedules mocks base method.
 func (m *MockSchedulerService) DeploySchedules(ctx context.Context, stateMachineArn string, rules stefunny.Schedules, keepState bool) error {
 	m.ctrl.T.Helper()
@@ -200,6 +342,20 @@ func (mr *MockSchedulerServiceMockRecorder) DeploySchedules(ctx, stateMachineArn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeploySchedules", reflect.TypeOf((*MockSchedulerService)(nil).DeploySchedules), ctx, stateMachineArn, rules, keepState)
 }
 
+// PruneScheduleGroups mocks base method.
+func (m *MockSchedulerService) PruneScheduleGroups(ctx context.Context, stateMachineName string, groups stefunny.ScheduleGroups) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneScheduleGroups", ctx, stateMachineName, groups)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneScheduleGroups indicates an expected call of PruneScheduleGroups.
+func (mr *MockSchedulerServiceMockRecorder) PruneScheduleGroups(ctx, stateMachineName, groups any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneScheduleGroups", reflect.TypeOf((*MockSchedulerService)(nil).PruneScheduleGroups), ctx, stateMachineName, groups)
+}
+
 // SearchRelatedSchedules mocks base method.
 func (m *MockSchedulerService) SearchRelatedSchedules(ctx context.Context, params *stefunny.SearchRelatedSchedulesInput) (stefunny.Schedules, error) {
 	m.ctrl.T.Helper()
@@ -214,3 +370,32 @@ func (mr *MockSchedulerServiceMockRecorder) SearchRelatedSchedules(ctx, params a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchRelatedSchedules", reflect.TypeOf((*MockSchedulerService)(nil).SearchRelatedSchedules), ctx, params)
 }
+
+// SearchScheduleGroups mocks base method.
+func (m *MockSchedulerService) SearchScheduleGroups(ctx context.Context, stateMachineName string, groupNames []string) (stefunny.ScheduleGroups, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchScheduleGroups", ctx, stateMachineName, groupNames)
+	ret0, _ := ret[0].(stefunny.ScheduleGroups)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchScheduleGroups indicates an expected call of SearchScheduleGroups.
+func (mr *MockSchedulerServiceMockRecorder) SearchScheduleGroups(ctx, stateMachineName, groupNames any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchScheduleGroups", reflect.TypeOf((*MockSchedulerService)(nil).SearchScheduleGroups), ctx, stateMachineName, groupNames)
+}
+
+// SetScheduleEnabled mocks base method.
+func (m *MockSchedulerService) SetScheduleEnabled(ctx context.Context, schedule *stefunny.Schedule, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScheduleEnabled", ctx, schedule, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetScheduleEnabled indicates an expected call of SetScheduleEnabled.
+func (mr *MockSchedulerServiceMockRecorder) SetScheduleEnabled(ctx, schedule, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScheduleEnabled", reflect.TypeOf((*MockSchedulerService)(nil).SetScheduleEnabled), ctx, schedule, enabled)
+}
@@ -202,6 +202,26 @@ func (mr *MockSFnClientMockRecorder) GetExecutionHistory(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionHistory", reflect.TypeOf((*MockSFnClient)(nil).GetExecutionHistory), varargs...)
 }
 
+// ListExecutions mocks base method.
+func (m *MockSFnClient) ListExecutions(ctx context.Context, params *sfn.ListExecutionsInput, optFns ...func(*sfn.Options)) (*sfn.ListExecutionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListExecutions", varargs...)
+	ret0, _ := ret[0].(*sfn.ListExecutionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExecutions indicates an expected call of ListExecutions.
+func (mr *MockSFnClientMockRecorder) ListExecutions(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExecutions", reflect.TypeOf((*MockSFnClient)(nil).ListExecutions), varargs...)
+}
+
 // ListStateMachineAliases mocks base method.
 func (m *MockSFnClient) ListStateMachineAliases(ctx context.Context, params *sfn.ListStateMachineAliasesInput, optFns ...func(*sfn.Options)) (*sfn.ListStateMachineAliasesOutput, error) {
 	m.ctrl.T.Helper()
@@ -569,3 +589,18 @@ func (mr *MockSFnServiceMockRecorder) StartExecution(ctx, stateMachine, params a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExecution", reflect.TypeOf((*MockSFnService)(nil).StartExecution), ctx, stateMachine, params)
 }
+
+// WaitTriggeredExecution mocks base method.
+func (m *MockSFnService) WaitTriggeredExecution(ctx context.Context, params *stefunny.WaitTriggeredExecutionInput) (*stefunny.StartExecutionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitTriggeredExecution", ctx, params)
+	ret0, _ := ret[0].(*stefunny.StartExecutionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitTriggeredExecution indicates an expected call of WaitTriggeredExecution.
+func (mr *MockSFnServiceMockRecorder) WaitTriggeredExecution(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitTriggeredExecution", reflect.TypeOf((*MockSFnService)(nil).WaitTriggeredExecution), ctx, params)
+}
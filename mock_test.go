@@ -16,6 +16,7 @@ type mocks struct {
 	sfn         *mock.MockSFnService
 	eventBridge *mock.MockEventBridgeService
 	scheduler   *mock.MockSchedulerService
+	pipes       *mock.MockPipesService
 }
 
 func NewMocks(t *testing.T) *mocks {
@@ -27,6 +28,7 @@ func NewMocks(t *testing.T) *mocks {
 		sfn:         mock.NewMockSFnService(ctrl),
 		eventBridge: mock.NewMockEventBridgeService(ctrl),
 		scheduler:   mock.NewMockSchedulerService(ctrl),
+		pipes:       mock.NewMockPipesService(ctrl),
 	}
 	return m
 }
@@ -48,6 +50,7 @@ func newMockApp(t *testing.T, path string, m *mocks) *stefunny.App {
 		stefunny.WithSFnService(m.sfn),
 		stefunny.WithEventBridgeService(m.eventBridge),
 		stefunny.WithSchedulerService(m.scheduler),
+		stefunny.WithPipesService(m.pipes),
 	)
 	require.NoError(t, err)
 	return app
@@ -0,0 +1,294 @@
+package stefunny
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pipes"
+	pipestypes "github.com/aws/aws-sdk-go-v2/service/pipes/types"
+)
+
+type Pipe struct {
+	pipes.CreatePipeInput
+	PipeArn         *string    `yaml:"PipeArn,omitempty" json:"PipeArn,omitempty"`
+	CreationTime    *time.Time `yaml:"CreationTime,omitempty" json:"CreationTime,omitempty"`
+	ConfigFilePath  *string    `yaml:"ConfigFilePath,omitempty" json:"ConfigFilePath,omitempty"`
+	ConfigFileIndex int        `yaml:"ConfigFileIndex,omitempty" json:"ConfigFileIndex,omitempty"`
+}
+
+// sourceURI returns the origin of the pipe for diff header.
+// It is not named Source, because it would shadow CreatePipeInput.Source.
+func (p *Pipe) sourceURI() string {
+	if p == nil {
+		return knownAfterDeployArn
+	}
+	if p.PipeArn != nil {
+		return *p.PipeArn
+	}
+	if p.ConfigFilePath != nil {
+		return fmt.Sprintf("trigger.pipe[%d] in %s", p.ConfigFileIndex, *p.ConfigFilePath)
+	}
+	if p.Name != nil {
+		return *p.Name
+	}
+	return knownAfterDeployArn
+}
+
+func (p *Pipe) SetStateMachineQualifiedArn(stateMachineArn string) {
+	p.Target = aws.String(stateMachineArn)
+}
+
+func (p *Pipe) IsManagedBy() bool {
+	return p.Tags[tagManagedBy] == appName
+}
+
+func (p *Pipe) AppendTags(tags map[string]string) {
+	if p.Tags == nil {
+		p.Tags = make(map[string]string, len(tags))
+	}
+	for key, value := range tags {
+		p.Tags[key] = value
+	}
+}
+
+func (p *Pipe) DeleteTag(key string) {
+	delete(p.Tags, key)
+}
+
+func (p *Pipe) configureJSON() string {
+	if p == nil {
+		return "null"
+	}
+	return MarshalJSONString(p.CreatePipeInput)
+}
+
+func (p *Pipe) String() string {
+	var builder strings.Builder
+	builder.WriteString(colorRestString(p.configureJSON()))
+	return builder.String()
+}
+
+func (p *Pipe) DiffString(newPipe *Pipe, unified bool) string {
+	var builder strings.Builder
+	from := p.sourceURI()
+	to := newPipe.sourceURI()
+	builder.WriteString(
+		JSONDiffString(
+			p.configureJSON(), newPipe.configureJSON(),
+			JSONDiffFromURI(from),
+			JSONDiffToURI(to),
+			JSONDiffUnified(unified),
+		),
+	)
+	return builder.String()
+}
+
+func (p *Pipe) SetEnabled(enabled bool) {
+	if enabled {
+		p.DesiredState = pipestypes.RequestedPipeStateRunning
+	} else {
+		p.DesiredState = pipestypes.RequestedPipeStateStopped
+	}
+}
+
+// UpdatePipeInput converts to pipes.UpdatePipeInput.
+// source, tags and the source parameters returned by ImmutableChanges can not be updated by UpdatePipe API.
+func (p *Pipe) UpdatePipeInput() (*pipes.UpdatePipeInput, error) {
+	input := &pipes.UpdatePipeInput{
+		Name:                 p.Name,
+		RoleArn:              p.RoleArn,
+		Description:          p.Description,
+		DesiredState:         p.DesiredState,
+		Enrichment:           p.Enrichment,
+		EnrichmentParameters: p.EnrichmentParameters,
+		KmsKeyIdentifier:     p.KmsKeyIdentifier,
+		LogConfiguration:     p.LogConfiguration,
+		Target:               p.Target,
+		TargetParameters:     p.TargetParameters,
+	}
+	if p.SourceParameters != nil {
+		var params pipestypes.UpdatePipeSourceParameters
+		if err := convertByJSON(p.SourceParameters, &params); err != nil {
+			return nil, fmt.Errorf("failed to convert source parameters: %w", err)
+		}
+		input.SourceParameters = &params
+	}
+	return input, nil
+}
+
+// ImmutableChanges returns the fields that differ from newPipe and can not be updated by UpdatePipe API,
+// such as source and StartingPosition of source parameters. the pipe must be recreated when it is not empty.
+func (p *Pipe) ImmutableChanges(newPipe *Pipe) ([]string, error) {
+	var changes []string
+	if coalesce(p.Source) != coalesce(newPipe.Source) {
+		changes = append(changes, "Source")
+	}
+	before, err := immutableSourceParameters(p.SourceParameters)
+	if err != nil {
+		return nil, err
+	}
+	after, err := immutableSourceParameters(newPipe.SourceParameters)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(before)+len(after))
+	for key, value := range before {
+		if !reflect.DeepEqual(value, after[key]) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		changes = append(changes, "SourceParameters."+key)
+	}
+	return changes, nil
+}
+
+// immutableSourceParameters returns the flattened source parameters that UpdatePipeSourceParameters does not have.
+func immutableSourceParameters(params *pipestypes.PipeSourceParameters) (map[string]any, error) {
+	result := make(map[string]any)
+	if params == nil {
+		return result, nil
+	}
+	var all map[string]any
+	if err := convertByJSON(params, &all); err != nil {
+		return nil, fmt.Errorf("failed to convert source parameters: %w", err)
+	}
+	var updatable pipestypes.UpdatePipeSourceParameters
+	if err := convertByJSON(params, &updatable); err != nil {
+		return nil, fmt.Errorf("failed to convert source parameters: %w", err)
+	}
+	var updatableKeys map[string]any
+	if err := convertByJSON(updatable, &updatableKeys); err != nil {
+		return nil, fmt.Errorf("failed to convert source parameters: %w", err)
+	}
+	flattenImmutableKeys("", all, updatableKeys, result)
+	return result, nil
+}
+
+func flattenImmutableKeys(prefix string, all map[string]any, updatableKeys map[string]any, result map[string]any) {
+	for key, value := range all {
+		if value == nil {
+			continue
+		}
+		updatable, ok := updatableKeys[key]
+		child, isMap := value.(map[string]any)
+		if isMap {
+			updatableChild, _ := updatable.(map[string]any)
+			flattenImmutableKeys(prefix+key+".", child, updatableChild, result)
+			continue
+		}
+		if ok {
+			continue
+		}
+		result[prefix+key] = value
+	}
+}
+
+type Pipes []*Pipe
+
+func (ps Pipes) SetStateMachineQualifiedArn(stateMachineArn string) {
+	for _, p := range ps {
+		p.SetStateMachineQualifiedArn(stateMachineArn)
+	}
+}
+
+func (ps Pipes) AppendTags(tags map[string]string) {
+	for _, p := range ps {
+		p.AppendTags(tags)
+	}
+}
+
+func (ps Pipes) String() string {
+	var builder strings.Builder
+	for _, p := range ps {
+		builder.WriteString(p.String())
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+func (ps Pipes) SetEnabled(enabled bool) {
+	for _, p := range ps {
+		p.SetEnabled(enabled)
+	}
+}
+
+func (ps Pipes) SyncState(other Pipes) {
+	otherMap := make(map[string]*Pipe, len(other))
+	for _, p := range other {
+		otherMap[coalesce(p.Name)] = p
+	}
+	for _, p := range ps {
+		if o, ok := otherMap[coalesce(p.Name)]; ok {
+			p.DesiredState = o.DesiredState
+		}
+	}
+}
+
+func (ps Pipes) DiffString(newPipes Pipes, unified bool) string {
+	result := sliceDiff(ps, newPipes, func(p *Pipe) string {
+		return coalesce(p.Name)
+	})
+	var builder strings.Builder
+	var zero *Pipe
+	for _, delete := range result.Delete {
+		if !delete.IsManagedBy() {
+			log.Printf("[warn] pipe %s is not managed by %s, suppressed diff", coalesce(delete.Name), appName)
+			continue
+		}
+		builder.WriteString(delete.DiffString(zero, unified))
+		builder.WriteRune('\n')
+	}
+	for _, c := range result.Change {
+		builder.WriteString(c.Before.DiffString(c.After, unified))
+		builder.WriteRune('\n')
+	}
+	for _, add := range result.Add {
+		builder.WriteString(zero.DiffString(add, unified))
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+func (ps Pipes) Names() []string {
+	names := make([]string, 0, len(ps))
+	for _, p := range ps {
+		if name := coalesce(p.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (ps Pipes) FindByName(name string) (*Pipe, bool) {
+	for _, p := range ps {
+		if coalesce(p.Name) == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// sort.Interfaces
+func (ps Pipes) Len() int {
+	return len(ps)
+}
+
+func (ps Pipes) Less(i, j int) bool {
+	return coalesce(ps[i].Name) < coalesce(ps[j].Name)
+}
+
+func (ps Pipes) Swap(i, j int) {
+	ps[i], ps[j] = ps[j], ps[i]
+}
@@ -0,0 +1,302 @@
+package stefunny
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pipes"
+	pipestypes "github.com/aws/aws-sdk-go-v2/service/pipes/types"
+	"github.com/aws/smithy-go"
+)
+
+//go:generate go tool mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+type PipesClient interface {
+	pipes.ListPipesAPIClient
+	DescribePipe(ctx context.Context, params *pipes.DescribePipeInput, optFns ...func(*pipes.Options)) (*pipes.DescribePipeOutput, error)
+	CreatePipe(ctx context.Context, params *pipes.CreatePipeInput, optFns ...func(*pipes.Options)) (*pipes.CreatePipeOutput, error)
+	UpdatePipe(ctx context.Context, params *pipes.UpdatePipeInput, optFns ...func(*pipes.Options)) (*pipes.UpdatePipeOutput, error)
+	DeletePipe(ctx context.Context, params *pipes.DeletePipeInput, optFns ...func(*pipes.Options)) (*pipes.DeletePipeOutput, error)
+	TagResource(ctx context.Context, params *pipes.TagResourceInput, optFns ...func(*pipes.Options)) (*pipes.TagResourceOutput, error)
+}
+
+var (
+	ErrPipeDoesNotExist = errors.New("pipe does not exist")
+)
+
+const (
+	pipeDeleteInterval = time.Second
+	pipeDeleteTimeout  = 5 * time.Minute
+)
+
+type PipesService interface {
+	SearchRelatedPipes(ctx context.Context, params *SearchRelatedPipesInput) (Pipes, error)
+	DeployPipes(ctx context.Context, stateMachineArn string, pipes Pipes, keepState bool) error
+}
+
+var _ PipesService = (*PipesServiceImpl)(nil)
+
+type PipesServiceImpl struct {
+	client          PipesClient
+	cachePipeByName map[string]*pipes.DescribePipeOutput
+}
+
+func NewPipesService(client PipesClient) *PipesServiceImpl {
+	return &PipesServiceImpl{
+		client:          client,
+		cachePipeByName: make(map[string]*pipes.DescribePipeOutput),
+	}
+}
+
+type SearchRelatedPipesInput struct {
+	StateMachineQualifiedArn string
+	PipeNames                []string
+}
+
+func (svc *PipesServiceImpl) SearchRelatedPipes(ctx context.Context, params *SearchRelatedPipesInput) (Pipes, error) {
+	log.Printf("[debug] call SearchRelatedPipes(ctx,%#v)", params)
+	pipeNames, err := svc.searchRelatedPipeNames(ctx, params.StateMachineQualifiedArn)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.PipeNames) > 0 {
+		pipeNames = append(pipeNames, params.PipeNames...)
+		pipeNames = unique(pipeNames)
+	}
+	result := make(Pipes, 0, len(pipeNames))
+	for _, name := range pipeNames {
+		p, err := svc.describePipe(ctx, name)
+		if err != nil {
+			if !errors.Is(err, ErrPipeDoesNotExist) {
+				return nil, err
+			}
+			log.Println("[debug] pipe not found", name)
+			continue
+		}
+		result = append(result, p)
+	}
+	sort.Sort(result)
+	log.Printf("[debug] end SearchRelatedPipes() %d pipes found", len(result))
+	return result, nil
+}
+
+// searchRelatedPipeNames returns names of pipes that target the qualified arn or the unqualified arn of the state machine,
+// the same as rules and schedules. pipes that target other aliases are not related, such as canary.
+func (svc *PipesServiceImpl) searchRelatedPipeNames(ctx context.Context, stateMachineArn string) ([]string, error) {
+	unqualified := removeQualifierFromArn(stateMachineArn)
+	p := pipes.NewListPipesPaginator(svc.client, &pipes.ListPipesInput{
+		TargetPrefix: aws.String(unqualified),
+	})
+	names := make([]string, 0)
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipes: %w", err)
+		}
+		for _, pipe := range output.Pipes {
+			targetArn := coalesce(pipe.Target)
+			log.Printf("[debug] pipe `%s` target Arn is `%s`", coalesce(pipe.Name), targetArn)
+			if targetArn != stateMachineArn && targetArn != unqualified {
+				continue
+			}
+			names = append(names, coalesce(pipe.Name))
+		}
+	}
+	return unique(names), nil
+}
+
+func (svc *PipesServiceImpl) describePipe(ctx context.Context, name string) (*Pipe, error) {
+	output, ok := svc.cachePipeByName[name]
+	if !ok {
+		var err error
+		output, err = svc.client.DescribePipe(ctx, &pipes.DescribePipeInput{
+			Name: aws.String(name),
+		})
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFoundException" {
+				return nil, ErrPipeDoesNotExist
+			}
+			return nil, fmt.Errorf("pipes.DescribePipe `%s`: %w", name, err)
+		}
+		svc.cachePipeByName[name] = output
+	}
+	log.Println("[debug] describe pipe:", MarshalJSONString(output))
+	if output.CurrentState == pipestypes.PipeStateDeleting {
+		return nil, ErrPipeDoesNotExist
+	}
+	p := &Pipe{
+		CreatePipeInput: pipes.CreatePipeInput{
+			Name:                 output.Name,
+			RoleArn:              output.RoleArn,
+			Source:               output.Source,
+			Target:               output.Target,
+			Description:          output.Description,
+			DesiredState:         pipestypes.RequestedPipeState(output.DesiredState),
+			Enrichment:           output.Enrichment,
+			EnrichmentParameters: output.EnrichmentParameters,
+			KmsKeyIdentifier:     output.KmsKeyIdentifier,
+			SourceParameters:     output.SourceParameters,
+			Tags:                 output.Tags,
+			TargetParameters:     output.TargetParameters,
+		},
+		PipeArn:      output.Arn,
+		CreationTime: output.CreationTime,
+	}
+	if output.LogConfiguration != nil {
+		var logConfig pipestypes.PipeLogConfigurationParameters
+		if err := convertByJSON(output.LogConfiguration, &logConfig); err != nil {
+			return nil, fmt.Errorf("failed to convert log configuration of pipe `%s`: %w", name, err)
+		}
+		p.LogConfiguration = &logConfig
+	}
+	return p, nil
+}
+
+func (svc *PipesServiceImpl) DeployPipes(ctx context.Context, stateMachineArn string, newPipes Pipes, keepState bool) error {
+	currentPipes, err := svc.SearchRelatedPipes(ctx, &SearchRelatedPipesInput{
+		StateMachineQualifiedArn: stateMachineArn,
+		PipeNames:                newPipes.Names(),
+	})
+	if err != nil {
+		return err
+	}
+	if keepState {
+		newPipes.SyncState(currentPipes)
+	}
+	newPipes.SetStateMachineQualifiedArn(stateMachineArn)
+	plan := sliceDiff(currentPipes, newPipes, func(p *Pipe) string {
+		return coalesce(p.Name)
+	})
+	for _, p := range plan.Delete {
+		log.Println("[info] deleting pipe:", coalesce(p.PipeArn))
+		if err := svc.deletePipe(ctx, p); err != nil {
+			return fmt.Errorf("delete pipe %s: %w", coalesce(p.Name), err)
+		}
+	}
+	for _, c := range plan.Change {
+		immutableChanges, err := c.Before.ImmutableChanges(c.After)
+		if err != nil {
+			return fmt.Errorf("pipe %s: %w", coalesce(c.After.Name), err)
+		}
+		if len(immutableChanges) > 0 {
+			log.Printf("[info] %s of pipe `%s` can not be updated, recreating pipe: %s", strings.Join(immutableChanges, ", "), coalesce(c.Before.Name), coalesce(c.Before.PipeArn))
+			if !c.Before.IsManagedBy() {
+				return fmt.Errorf("pipe `%s` that %s does not manage can not be recreated", coalesce(c.Before.Name), appName)
+			}
+			if err := svc.deletePipe(ctx, c.Before); err != nil {
+				return fmt.Errorf("delete pipe %s: %w", coalesce(c.Before.Name), err)
+			}
+			if err := svc.waitPipeDeleted(ctx, coalesce(c.Before.Name)); err != nil {
+				return fmt.Errorf("delete pipe %s: %w", coalesce(c.Before.Name), err)
+			}
+			if err := svc.createPipe(ctx, c.After); err != nil {
+				return fmt.Errorf("create pipe %s: %w", coalesce(c.After.Name), err)
+			}
+			continue
+		}
+		log.Println("[info] changing pipe:", coalesce(c.Before.PipeArn))
+		c.After.PipeArn = c.Before.PipeArn
+		if err := svc.updatePipe(ctx, c.After); err != nil {
+			return fmt.Errorf("update pipe %s: %w", coalesce(c.After.Name), err)
+		}
+	}
+	for _, p := range plan.Add {
+		log.Println("[info] creating pipe:", coalesce(p.Name))
+		if err := svc.createPipe(ctx, p); err != nil {
+			return fmt.Errorf("create pipe %s: %w", coalesce(p.Name), err)
+		}
+	}
+	return nil
+}
+
+func (svc *PipesServiceImpl) createPipe(ctx context.Context, p *Pipe) error {
+	log.Println("[debug] deploy create pipe")
+	p.AppendTags(map[string]string{
+		tagManagedBy: appName,
+	})
+	output, err := svc.client.CreatePipe(ctx, &p.CreatePipeInput)
+	if err != nil {
+		return fmt.Errorf("create pipe: %w", err)
+	}
+	p.PipeArn = output.Arn
+	return nil
+}
+
+func (svc *PipesServiceImpl) updatePipe(ctx context.Context, p *Pipe) error {
+	log.Println("[debug] deploy update pipe")
+	input, err := p.UpdatePipeInput()
+	if err != nil {
+		return err
+	}
+	output, err := svc.client.UpdatePipe(ctx, input)
+	if err != nil {
+		return fmt.Errorf("update pipe: %w", err)
+	}
+	log.Println("[debug] deploy update tag")
+	p.AppendTags(map[string]string{
+		tagManagedBy: appName,
+	})
+	_, err = svc.client.TagResource(ctx, &pipes.TagResourceInput{
+		ResourceArn: output.Arn,
+		Tags:        p.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("tag resource: %w", err)
+	}
+	return nil
+}
+
+func (svc *PipesServiceImpl) deletePipe(ctx context.Context, p *Pipe) error {
+	if !p.IsManagedBy() {
+		log.Printf("[warn] pipe `%s` that %s does not manage. skip delete this pipe", coalesce(p.Name), appName)
+		return nil
+	}
+	log.Println("[debug] deploy delete pipe:", coalesce(p.Name))
+	_, err := svc.client.DeletePipe(ctx, &pipes.DeletePipeInput{
+		Name: p.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("delete pipe: %w", err)
+	}
+	return nil
+}
+
+// waitPipeDeleted waits until the pipe is deleted, DeletePipe returns while the pipe is DELETING.
+// the pipe of the same name can not be created until then.
+func (svc *PipesServiceImpl) waitPipeDeleted(ctx context.Context, name string) error {
+	delete(svc.cachePipeByName, name)
+	waitCtx, cancel := context.WithTimeout(ctx, pipeDeleteTimeout)
+	defer cancel()
+	ticker := time.NewTicker(pipeDeleteInterval)
+	defer ticker.Stop()
+	for {
+		output, err := svc.client.DescribePipe(waitCtx, &pipes.DescribePipeInput{
+			Name: aws.String(name),
+		})
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFoundException" {
+				return nil
+			}
+			if waitCtx.Err() == nil || ctx.Err() != nil {
+				return fmt.Errorf("pipes.DescribePipe `%s`: %w", name, err)
+			}
+		} else {
+			log.Printf("[info] waiting for pipe `%s` to be deleted, current state: %s", name, output.CurrentState)
+		}
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("pipe `%s` is not deleted within %s", name, pipeDeleteTimeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package stefunny_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pipes"
+	pipestypes "github.com/aws/aws-sdk-go-v2/service/pipes/types"
+	"github.com/aws/smithy-go"
+	"github.com/mashiike/stefunny"
+	"github.com/mashiike/stefunny/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPipesService__SearchRelatedPipes(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockPipesClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().ListPipes(gomock.Any(), &pipes.ListPipesInput{
+		TargetPrefix: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped"),
+	}, gomock.Any()).Return(
+		&pipes.ListPipesOutput{
+			Pipes: []pipestypes.Pipe{
+				{
+					Name:   aws.String("Qualified"),
+					Target: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped:current"),
+				},
+				{
+					Name:   aws.String("Unqualified"),
+					Target: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped"),
+				},
+				{
+					Name:   aws.String("OtherStateMachine"),
+					Target: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:PipedOther"),
+				},
+				{
+					Name:   aws.String("OtherAlias"),
+					Target: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped:canary"),
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+		Name: aws.String("Qualified"),
+	}).Return(
+		&pipes.DescribePipeOutput{
+			Name:         aws.String("Qualified"),
+			Arn:          aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Qualified"),
+			Source:       aws.String("arn:aws:sqs:us-east-1:000000000000:hello-queue"),
+			Target:       aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped:current"),
+			RoleArn:      aws.String("arn:aws:iam::000000000000:role/service-role/Pipes-Hello-role"),
+			DesiredState: pipestypes.RequestedPipeStateDescribeResponseStopped,
+			CurrentState: pipestypes.PipeStateStopped,
+			Tags: map[string]string{
+				"ManagedBy": "stefunny",
+			},
+			LogConfiguration: &pipestypes.PipeLogConfiguration{
+				Level: pipestypes.LogLevelError,
+				CloudwatchLogsLogDestination: &pipestypes.CloudwatchLogsLogDestination{
+					LogGroupArn: aws.String("arn:aws:logs:us-east-1:000000000000:log-group:/pipes/hello"),
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+		Name: aws.String("Unqualified"),
+	}).Return(
+		&pipes.DescribePipeOutput{
+			Name:         aws.String("Unqualified"),
+			Arn:          aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Unqualified"),
+			Source:       aws.String("arn:aws:sqs:us-east-1:000000000000:world-queue"),
+			Target:       aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped"),
+			RoleArn:      aws.String("arn:aws:iam::000000000000:role/service-role/Pipes-Hello-role"),
+			DesiredState: pipestypes.RequestedPipeStateDescribeResponseRunning,
+			CurrentState: pipestypes.PipeStateRunning,
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+		Name: aws.String("NotFound"),
+	}).Return(
+		nil,
+		&smithy.GenericAPIError{
+			Code:    "NotFoundException",
+			Message: "Pipe NotFound does not exist.",
+		},
+	).Times(1)
+
+	svc := stefunny.NewPipesService(m)
+	actual, err := svc.SearchRelatedPipes(context.Background(), &stefunny.SearchRelatedPipesInput{
+		StateMachineQualifiedArn: "arn:aws:states:us-east-1:000000000000:stateMachine:Piped:current",
+		PipeNames:                []string{"NotFound"},
+	})
+	require.NoError(t, err)
+	expected := stefunny.Pipes{
+		{
+			CreatePipeInput: pipes.CreatePipeInput{
+				Name:         aws.String("Qualified"),
+				Source:       aws.String("arn:aws:sqs:us-east-1:000000000000:hello-queue"),
+				Target:       aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped:current"),
+				RoleArn:      aws.String("arn:aws:iam::000000000000:role/service-role/Pipes-Hello-role"),
+				DesiredState: pipestypes.RequestedPipeStateStopped,
+				Tags: map[string]string{
+					"ManagedBy": "stefunny",
+				},
+				LogConfiguration: &pipestypes.PipeLogConfigurationParameters{
+					Level: pipestypes.LogLevelError,
+					CloudwatchLogsLogDestination: &pipestypes.CloudwatchLogsLogDestinationParameters{
+						LogGroupArn: aws.String("arn:aws:logs:us-east-1:000000000000:log-group:/pipes/hello"),
+					},
+				},
+			},
+			PipeArn: aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Qualified"),
+		},
+		{
+			CreatePipeInput: pipes.CreatePipeInput{
+				Name:         aws.String("Unqualified"),
+				Source:       aws.String("arn:aws:sqs:us-east-1:000000000000:world-queue"),
+				Target:       aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Piped"),
+				RoleArn:      aws.String("arn:aws:iam::000000000000:role/service-role/Pipes-Hello-role"),
+				DesiredState: pipestypes.RequestedPipeStateRunning,
+			},
+			PipeArn: aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Unqualified"),
+		},
+	}
+	require.EqualValues(t, expected, actual)
+}
+
+func TestPipesService__DeployPipes(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockPipesClient(ctrl)
+	defer ctrl.Finish()
+
+	stateMachineArn := "arn:aws:states:us-east-1:000000000000:stateMachine:Piped:current"
+	m.EXPECT().ListPipes(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&pipes.ListPipesOutput{
+			Pipes: []pipestypes.Pipe{
+				{Name: aws.String("Update"), Target: aws.String(stateMachineArn)},
+				{Name: aws.String("Recreate"), Target: aws.String(stateMachineArn)},
+				{Name: aws.String("Delete"), Target: aws.String(stateMachineArn)},
+				{Name: aws.String("Unmanaged"), Target: aws.String(stateMachineArn)},
+			},
+		},
+		nil,
+	).Times(1)
+	describe := func(name string, source string, tags map[string]string) {
+		m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+			Name: aws.String(name),
+		}).Return(
+			&pipes.DescribePipeOutput{
+				Name:         aws.String(name),
+				Arn:          aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/" + name),
+				Source:       aws.String(source),
+				Target:       aws.String(stateMachineArn),
+				RoleArn:      aws.String("arn:aws:iam::000000000000:role/service-role/Pipes-Hello-role"),
+				DesiredState: pipestypes.RequestedPipeStateDescribeResponseStopped,
+				CurrentState: pipestypes.PipeStateStopped,
+				Tags:         tags,
+			},
+			nil,
+		).Times(1)
+	}
+	managed := map[string]string{"ManagedBy": "stefunny"}
+	describe("Update", "arn:aws:sqs:us-east-1:000000000000:update-queue", managed)
+	describe("Recreate", "arn:aws:sqs:us-east-1:000000000000:old-queue", managed)
+	describe("Delete", "arn:aws:sqs:us-east-1:000000000000:delete-queue", managed)
+	describe("Unmanaged", "arn:aws:sqs:us-east-1:000000000000:unmanaged-queue", nil)
+	m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+		Name: aws.String("Create"),
+	}).Return(nil, &smithy.GenericAPIError{Code: "NotFoundException"}).Times(1)
+
+	m.EXPECT().DeletePipe(gomock.Any(), &pipes.DeletePipeInput{
+		Name: aws.String("Delete"),
+	}).Return(&pipes.DeletePipeOutput{}, nil).Times(1)
+	m.EXPECT().UpdatePipe(gomock.Any(), gomock.Cond(func(input *pipes.UpdatePipeInput) bool {
+		return aws.ToString(input.Name) == "Update" &&
+			aws.ToString(input.Target) == stateMachineArn &&
+			input.DesiredState == pipestypes.RequestedPipeStateStopped
+	})).Return(&pipes.UpdatePipeOutput{
+		Arn: aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Update"),
+	}, nil).Times(1)
+	m.EXPECT().TagResource(gomock.Any(), &pipes.TagResourceInput{
+		ResourceArn: aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Update"),
+		Tags:        map[string]string{"ManagedBy": "stefunny"},
+	}).Return(&pipes.TagResourceOutput{}, nil).Times(1)
+	m.EXPECT().DeletePipe(gomock.Any(), &pipes.DeletePipeInput{
+		Name: aws.String("Recreate"),
+	}).Return(&pipes.DeletePipeOutput{}, nil).Times(1)
+	// DeletePipe returns while the pipe is deleting, the pipe is created again after it is deleted.
+	gomock.InOrder(
+		m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+			Name: aws.String("Recreate"),
+		}).Return(&pipes.DescribePipeOutput{
+			Name:         aws.String("Recreate"),
+			CurrentState: pipestypes.PipeStateDeleting,
+		}, nil).Times(1),
+		m.EXPECT().DescribePipe(gomock.Any(), &pipes.DescribePipeInput{
+			Name: aws.String("Recreate"),
+		}).Return(nil, &smithy.GenericAPIError{Code: "NotFoundException"}).Times(1),
+		m.EXPECT().CreatePipe(gomock.Any(), gomock.Cond(func(input *pipes.CreatePipeInput) bool {
+			return aws.ToString(input.Name) == "Recreate" &&
+				aws.ToString(input.Source) == "arn:aws:sqs:us-east-1:000000000000:new-queue"
+		})).Return(&pipes.CreatePipeOutput{
+			Arn: aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Recreate"),
+		}, nil).Times(1),
+	)
+	m.EXPECT().CreatePipe(gomock.Any(), gomock.Cond(func(input *pipes.CreatePipeInput) bool {
+		return aws.ToString(input.Name) == "Create" &&
+			aws.ToString(input.Target) == stateMachineArn &&
+			input.Tags["ManagedBy"] == "stefunny"
+	})).Return(&pipes.CreatePipeOutput{
+		Arn: aws.String("arn:aws:pipes:us-east-1:000000000000:pipe/Create"),
+	}, nil).Times(1)
+
+	newPipe := func(name string, source string) *stefunny.Pipe {
+		return &stefunny.Pipe{
+			CreatePipeInput: pipes.CreatePipeInput{
+				Name:         aws.String(name),
+				Source:       aws.String(source),
+				RoleArn:      aws.String("arn:aws:iam::000000000000:role/service-role/Pipes-Hello-role"),
+				DesiredState: pipestypes.RequestedPipeStateRunning,
+				Tags:         map[string]string{"ManagedBy": "stefunny"},
+			},
+		}
+	}
+	svc := stefunny.NewPipesService(m)
+	err := svc.DeployPipes(context.Background(), stateMachineArn, stefunny.Pipes{
+		newPipe("Create", "arn:aws:sqs:us-east-1:000000000000:create-queue"),
+		newPipe("Recreate", "arn:aws:sqs:us-east-1:000000000000:new-queue"),
+		newPipe("Update", "arn:aws:sqs:us-east-1:000000000000:update-queue"),
+	}, true)
+	require.NoError(t, err)
+}
+
+func TestPipeImmutableChanges(t *testing.T) {
+	kinesis := func(startingPosition pipestypes.KinesisStreamStartPosition, batchSize int32) *stefunny.Pipe {
+		return &stefunny.Pipe{
+			CreatePipeInput: pipes.CreatePipeInput{
+				Name:   aws.String("Kinesis"),
+				Source: aws.String("arn:aws:kinesis:us-east-1:000000000000:stream/hello"),
+				SourceParameters: &pipestypes.PipeSourceParameters{
+					KinesisStreamParameters: &pipestypes.PipeSourceKinesisStreamParameters{
+						StartingPosition: startingPosition,
+						BatchSize:        aws.Int32(batchSize),
+					},
+				},
+			},
+		}
+	}
+	cases := []struct {
+		name     string
+		before   *stefunny.Pipe
+		after    *stefunny.Pipe
+		expected []string
+	}{
+		{
+			name:   "updatable",
+			before: kinesis(pipestypes.KinesisStreamStartPositionLatest, 10),
+			after:  kinesis(pipestypes.KinesisStreamStartPositionLatest, 100),
+		},
+		{
+			name:     "starting position",
+			before:   kinesis(pipestypes.KinesisStreamStartPositionLatest, 10),
+			after:    kinesis(pipestypes.KinesisStreamStartPositionTrimHorizon, 100),
+			expected: []string{"SourceParameters.KinesisStreamParameters.StartingPosition"},
+		},
+		{
+			name:   "source",
+			before: kinesis(pipestypes.KinesisStreamStartPositionLatest, 10),
+			after: &stefunny.Pipe{
+				CreatePipeInput: pipes.CreatePipeInput{
+					Name:   aws.String("Kinesis"),
+					Source: aws.String("arn:aws:sqs:us-east-1:000000000000:hello"),
+					SourceParameters: &pipestypes.PipeSourceParameters{
+						SqsQueueParameters: &pipestypes.PipeSourceSqsQueueParameters{
+							BatchSize: aws.Int32(10),
+						},
+					},
+				},
+			},
+			expected: []string{"Source", "SourceParameters.KinesisStreamParameters.StartingPosition"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.before.ImmutableChanges(c.after)
+			require.NoError(t, err)
+			require.EqualValues(t, c.expected, actual)
+		})
+	}
+}
@@ -7,8 +7,9 @@ import (
 )
 
 type PullOption struct {
-	Templateize bool   `name:"templateize" default:"true" negatable:"" help:"templateize output"`
-	Qualifier   string `name:"qualifier" help:"qualifier for the version"`
+	Templateize    bool   `name:"templateize" default:"true" negatable:"" help:"templateize output"`
+	TemplateizeAWS bool   `name:"templateize-aws-identity" help:"templateize ARNs, account ids and regions of the caller with aws_account_id, aws_region and arn"`
+	Qualifier      string `name:"qualifier" help:"qualifier for the version"`
 }
 
 func (app *App) Pull(ctx context.Context, opt PullOption) error {
@@ -23,7 +24,15 @@ func (app *App) Pull(ctx context.Context, opt PullOption) error {
 	cfg.Envs = app.cfg.Envs
 	cfg.MustEnvs = app.cfg.MustEnvs
 	cfg.TFState = app.cfg.TFState
+	cfg.CFn = app.cfg.CFn
+	if opt.TemplateizeAWS {
+		cfg.AWSIdentity = app.cfg.AWSIdentity
+	}
+	cfg.VarValues = app.cfg.VarValues
+	// the secure values read by ssm and secretsmanager are written back as the template functions
+	cfg.SecretValues = app.cfg.SecretValues
 	renderer := NewRenderer(cfg)
+	renderer.SetCloudFormationClient(app.cfnClient)
 	if err := renderer.CreateDefinitionFile(ctx, defPath, opt.Templateize); err != nil {
 		return fmt.Errorf("failed create state machine definition file: %w", err)
 	}
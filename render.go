@@ -2,27 +2,31 @@ package stefunny
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/goccy/go-yaml"
 )
 
 type RenderOption struct {
-	Writer  io.Writer `kong:"-" json:"-"`
-	Targets []string  `arg:"" help:"target to render (config, definition, def)" enum:"config,definition,def" json:"targets,omitempty"`
-	Format  string    `name:"format" help:"output format(json, jsonnet, yaml)" default:"" enum:",json,jsonnet,yaml" json:"format,omitempty"`
+	Writer         io.Writer `kong:"-" json:"-"`
+	Targets        []string  `arg:"" help:"target to render (config, definition, def)" enum:"config,definition,def" json:"targets,omitempty"`
+	Format         string    `name:"format" help:"output format(json, jsonnet, yaml, toml)" default:"" enum:",json,jsonnet,yaml,toml" json:"format,omitempty"`
+	UseStefunnyLib bool      `name:"use-stefunny-lib" help:"render the definition in jsonnet with the helpers of stefunny.libsonnet" json:"use_stefunny_lib,omitempty"`
 }
 
 func (app *App) Render(ctx context.Context, opt RenderOption) error {
 	out := bufio.NewWriter(opt.Writer)
 	defer out.Flush()
 	renderer := NewRenderer(app.cfg)
+	renderer.SetUseStefunnyLib(opt.UseStefunnyLib)
 
 	for _, target := range opt.Targets {
 		switch target {
@@ -55,6 +59,9 @@ func (app *App) Render(ctx context.Context, opt RenderOption) error {
 type Renderer struct {
 	cfg                    *Config
 	cachedTFstateResources *OrderdMap[string, string]
+	cachedCFnResources     map[string]*OrderdMap[string, string]
+	cfnClient              CloudFormationClient
+	useStefunnyLib         bool
 }
 
 func NewRenderer(cfg *Config) *Renderer {
@@ -63,6 +70,15 @@ func NewRenderer(cfg *Config) *Renderer {
 	}
 }
 
+func (r *Renderer) SetCloudFormationClient(client CloudFormationClient) {
+	r.cfnClient = client
+}
+
+// SetUseStefunnyLib sets whether the definition in jsonnet uses the helpers of stefunny.libsonnet, such as chain([...]).
+func (r *Renderer) SetUseStefunnyLib(enabled bool) {
+	r.useStefunnyLib = enabled
+}
+
 func (r *Renderer) CreateConfigFile(ctx context.Context, path string, template bool) error {
 	fmt, err := r.detectFormat(path)
 	if err != nil {
@@ -119,6 +135,18 @@ func (r *Renderer) RenderStateMachine(ctx context.Context, w io.Writer, format s
 			return fmt.Errorf("failed to templateize: %w", err)
 		}
 	}
+	if r.useStefunnyLib && strings.EqualFold(format, "jsonnet") {
+		buf, err := marshalJSON(v)
+		if err != nil {
+			return fmt.Errorf("failed to render: %w", err)
+		}
+		bs, err := JSON2StefunnyJsonnet(r.cfg.ConfigDir, buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("failed to render: %w", err)
+		}
+		_, err = io.WriteString(w, r.cfg.MaskSecrets(string(bs)))
+		return err
+	}
 	if err := r.render(w, format, v); err != nil {
 		return fmt.Errorf("failed to render: %w", err)
 	}
@@ -134,12 +162,27 @@ func (r *Renderer) detectFormat(path string) (string, error) {
 		return "jsonnet", nil
 	case yamlExt, ymlExt:
 		return "yaml", nil
+	case tomlExt:
+		return "toml", nil
 	default:
 		return "", fmt.Errorf("unknown file extension: %s", ext)
 	}
 }
 
+// render writes v in the format, secure values of the template functions are masked.
 func (r *Renderer) render(w io.Writer, format string, v any) error {
+	if r.cfg.SecretValues == nil || r.cfg.SecretValues.Len() == 0 {
+		return r.renderFormat(w, format, v)
+	}
+	var buf bytes.Buffer
+	if err := r.renderFormat(&buf, format, v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, r.cfg.MaskSecrets(buf.String()))
+	return err
+}
+
+func (r *Renderer) renderFormat(w io.Writer, format string, v any) error {
 	switch f := strings.ToLower(format); f {
 	case "json", "jsonnet":
 		buf, err := marshalJSON(v)
@@ -162,6 +205,17 @@ func (r *Renderer) render(w io.Writer, format string, v any) error {
 			return err
 		}
 		return enc.Close()
+	case "toml":
+		bs, err := buildJSON(v)
+		if err != nil {
+			return err
+		}
+		bs, err = JSON2TOML(bs)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
 	default:
 		return fmt.Errorf("unknown format: %s", format)
 	}
@@ -176,6 +230,9 @@ func (r *Renderer) templateize(ctx context.Context, v any) (any, error) {
 	if err := json.Unmarshal(bs, &data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal: %w", err)
 	}
+	if r.cfg.SecretValues != nil && r.cfg.SecretValues.Len() > 0 {
+		data = r.templateizeSecrets(data, r.cfg.SecretValues)
+	}
 	if r.cfg.TemplateFiles.Len() > 0 {
 		data, err = templatizeTemplateFiles(data, r.cfg.TemplateFiles)
 		if err != nil {
@@ -194,6 +251,15 @@ func (r *Renderer) templateize(ctx context.Context, v any) (any, error) {
 			return nil, fmt.Errorf("failed to templateize for tfstate `%s`: %w", tfstateCfg.Location, err)
 		}
 	}
+	for _, cfnCfg := range r.cfg.CFn {
+		data, err = r.templateizeCFn(ctx, data, cfnCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to templateize for cfn `%s`: %w", cfnCfg.Stack, err)
+		}
+	}
+	if r.cfg.VarValues.Len() > 0 {
+		data = r.templateizeVars(data, r.cfg.VarValues)
+	}
 	if r.cfg.MustEnvs.Len() > 0 {
 		data, err = r.templateizeMustEnvs(data, r.cfg.MustEnvs)
 		if err != nil {
@@ -206,6 +272,9 @@ func (r *Renderer) templateize(ctx context.Context, v any) (any, error) {
 			return nil, fmt.Errorf("faield to templateize for env: %w", err)
 		}
 	}
+	if r.cfg.AWSIdentity != nil {
+		data = r.templateizeAWSIdentity(data, r.cfg.AWSIdentity)
+	}
 	return data, nil
 }
 
@@ -238,6 +307,120 @@ func (r *Renderer) templateizeTFState(ctx context.Context, data any, base string
 	return data, nil
 }
 
+func (r *Renderer) templateizeCFn(ctx context.Context, data any, cfg *CFnConfig) (any, error) {
+	resources, ok := r.cachedCFnResources[cfg.Stack]
+	if !ok {
+		if r.cfnClient == nil {
+			awsCfg, err := r.cfg.LoadAWSConfig(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load aws config: %w", err)
+			}
+			r.cfnClient = r.cfg.NewCloudFormationClientFromConfig(awsCfg)
+		}
+		var err error
+		resources, err = ListResourcesFromCFnStack(ctx, r.cfnClient, cfg.Stack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources from stack `%s`: %w", cfg.Stack, err)
+		}
+		if r.cachedCFnResources == nil {
+			r.cachedCFnResources = make(map[string]*OrderdMap[string, string])
+		}
+		r.cachedCFnResources[cfg.Stack] = resources
+	}
+	keys := resources.Keys()
+	for i := len(keys) - 1; i >= 0; i-- {
+		key := keys[i]
+		value, ok := resources.Get(key)
+		if !ok {
+			continue
+		}
+		data = walkStringReplaceAll(data, value, fmt.Sprintf("{{ %s%s }}", cfg.FuncPrefix, key))
+	}
+	return data, nil
+}
+
+// templateizeSecrets replaces the secure values with the ssm and secretsmanager template functions that read them,
+// not to write the decrypted values to files. The values shorter than minSecretLength are replaced only if they are the whole string.
+func (r *Renderer) templateizeSecrets(data any, secrets *OrderdMap[string, string]) any {
+	type secret struct {
+		value string
+		call  string
+	}
+	list := make([]secret, 0, secrets.Len())
+	for _, key := range secrets.Keys() {
+		value, ok := secrets.Get(key)
+		if !ok || value == "" {
+			continue
+		}
+		// the key is the function and the arguments, such as `secretsmanager id key`
+		fields := strings.Fields(key)
+		args := make([]string, len(fields)-1)
+		for i, arg := range fields[1:] {
+			args[i] = "`" + arg + "`"
+		}
+		list = append(list, secret{value: value, call: fmt.Sprintf("{{ %s %s }}", fields[0], strings.Join(args, " "))})
+	}
+	// longer values first, not to leave a part of the secret that contains another one.
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i].value) > len(list[j].value)
+	})
+	for _, s := range list {
+		if len(s.value) >= minSecretLength {
+			data = walkStringReplaceAll(data, s.value, s.call)
+			continue
+		}
+		data = walkString(data, func(str string) string {
+			if str == s.value {
+				return s.call
+			}
+			return str
+		})
+	}
+	return data
+}
+
+// templateizeVars replaces the values of vars with the var template function, except for the vars section itself.
+func (r *Renderer) templateizeVars(data any, vars *OrderdMap[string, string]) any {
+	m, isConfig := data.(map[string]any)
+	var section any
+	if isConfig {
+		section = m["vars"]
+		delete(m, "vars")
+	}
+	keys := vars.Keys()
+	for i := len(keys) - 1; i >= 0; i-- {
+		key := keys[i]
+		value, ok := vars.Get(key)
+		if !ok || value == "" {
+			continue
+		}
+		data = walkStringReplaceAll(data, value, fmt.Sprintf("{{ var `%s` }}", key))
+	}
+	if section != nil {
+		m["vars"] = section
+	}
+	return data
+}
+
+// templateizeAWSIdentity replaces ARNs, account ids and regions with the template functions, except for the vars section and aws_region.
+func (r *Renderer) templateizeAWSIdentity(data any, id *AWSIdentity) any {
+	m, isConfig := data.(map[string]any)
+	skipped := make(map[string]any)
+	if isConfig {
+		for _, key := range []string{"vars", "aws_region"} {
+			if v, ok := m[key]; ok {
+				skipped[key] = v
+				delete(m, key)
+			}
+		}
+	}
+	data = walkString(data, id.Templateize)
+	for key, v := range skipped {
+		m[key] = v
+	}
+	return data
+}
+
 func (r *Renderer) templateizeMustEnvs(data any, envs *OrderdMap[string, string]) (any, error) {
 	keys := envs.Keys()
 	for i := len(keys) - 1; i >= 0; i-- {
@@ -319,17 +502,23 @@ func templatizeTemplateFiles(data any, files *OrderdMap[string, string]) (any, e
 }
 
 func walkStringReplaceAll(v any, from, to string) any {
+	return walkString(v, func(s string) string {
+		return strings.ReplaceAll(s, from, to)
+	})
+}
+
+func walkString(v any, fn func(string) string) any {
 	switch x := v.(type) {
 	case string:
-		return strings.ReplaceAll(x, from, to)
+		return fn(x)
 	case map[string]interface{}:
 		for k, vv := range x {
-			x[k] = walkStringReplaceAll(vv, from, to)
+			x[k] = walkString(vv, fn)
 		}
 		return x
 	case []interface{}:
 		for i, vv := range x {
-			x[i] = walkStringReplaceAll(vv, from, to)
+			x[i] = walkString(vv, fn)
 		}
 		return x
 	default:
@@ -8,10 +8,15 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
 	"github.com/mashiike/stefunny"
+	"github.com/mashiike/stefunny/mock"
 	"github.com/motemen/go-testutil/dataloc"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestAppRender(t *testing.T) {
@@ -46,6 +51,12 @@ func TestAppRender(t *testing.T) {
 			target:   []string{"config", "definition"},
 			format:   "yaml",
 		},
+		{
+			casename: "toml",
+			path:     "testdata/stefunny.yaml",
+			target:   []string{"config", "definition"},
+			format:   "toml",
+		},
 		{
 			casename: "jsonnet_to_json",
 			path:     "testdata/jsonnet_def.yaml",
@@ -124,6 +135,11 @@ func TestRendererTemplateize(t *testing.T) {
 			path:     "testdata/file_func.yaml",
 			format:   "jsonnet",
 		},
+		{
+			casename: "vars",
+			path:     "testdata/vars.yaml",
+			format:   "yaml",
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.casename, func(t *testing.T) {
@@ -181,3 +197,78 @@ tfstate:
 	require.NoError(t, err)
 	require.Contains(t, buf.String(), "{{ tfstate `aws_iam_role.test.arn` }}")
 }
+
+func TestRendererTemplateizeCFn(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	describeStacksOutput := &cloudformation.DescribeStacksOutput{
+		Stacks: []cfntypes.Stack{
+			{
+				StackName: aws.String("hello-infra"),
+				Outputs: []cfntypes.Output{
+					{
+						OutputKey:   aws.String("LogGroupArn"),
+						OutputValue: aws.String("arn:aws:logs:us-east-1:012345678901:log-group:/steps/hello"),
+					},
+					{
+						OutputKey:   aws.String("RoleArn"),
+						OutputValue: aws.String("arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role"),
+						ExportName:  aws.String("hello-role-arn"),
+					},
+				},
+			},
+		},
+	}
+	client := mock.NewMockCloudFormationClient(ctrl)
+	client.EXPECT().DescribeStacks(gomock.Any(), &cloudformation.DescribeStacksInput{
+		StackName: aws.String("hello-infra"),
+	}, gomock.Any()).Return(describeStacksOutput, nil).Times(1)
+	client.EXPECT().ListExports(gomock.Any(), gomock.Any(), gomock.Any()).Return(&cloudformation.ListExportsOutput{
+		Exports: []cfntypes.Export{
+			{
+				Name:  aws.String("hello-role-arn"),
+				Value: aws.String("arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role"),
+			},
+		},
+	}, nil).Times(1)
+
+	l := stefunny.NewConfigLoader(nil, nil)
+	l.SetCloudFormationClient(client)
+	ctx := context.Background()
+	cfg, err := l.Load(ctx, "testdata/cfn.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:iam::012345678901:role/service-role/StepFunctions-Hello-role", *cfg.StateMachine.Value.RoleArn)
+	require.Equal(t, "arn:aws:logs:us-east-1:012345678901:log-group:/steps/hello", *cfg.StateMachine.Value.Tags[0].Value)
+
+	renderClient := mock.NewMockCloudFormationClient(ctrl)
+	renderClient.EXPECT().DescribeStacks(gomock.Any(), &cloudformation.DescribeStacksInput{
+		StackName: aws.String("hello-infra"),
+	}, gomock.Any()).Return(describeStacksOutput, nil).Times(1)
+	r := stefunny.NewRenderer(cfg)
+	r.SetCloudFormationClient(renderClient)
+	var buf bytes.Buffer
+	err = r.RenderConfig(ctx, &buf, "yaml", true)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "role_arn: \"{{ cfn_export `hello-role-arn` }}\"")
+	require.Contains(t, buf.String(), "log_group_arn: \"{{ cfn_output `hello-infra` `LogGroupArn` }}\"")
+}
+
+func TestRendererTemplateizeSecrets(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	LoggerSetup(t, "debug")
+	l := stefunny.NewConfigLoader(nil, nil)
+	ctx := context.Background()
+	cfg, err := l.Load(ctx, "testdata/stefunny.yaml")
+	require.NoError(t, err)
+	cfg.SecretValues.Set("ssm /secure/world", "World")
+	cfg.SecretValues.Set("secretsmanager hello key", "Hel")
+
+	r := stefunny.NewRenderer(cfg)
+	var buf bytes.Buffer
+	require.NoError(t, r.RenderStateMachine(ctx, &buf, "json", true))
+	require.Contains(t, buf.String(), `"Result": "{{ ssm `+"`/secure/world`"+` }}"`)
+	require.NotContains(t, buf.String(), `"World"`)
+	require.NotContains(t, buf.String(), "secretsmanager", "short values are replaced only if they are the whole string")
+}
@@ -5,7 +5,9 @@ import (
 	"log"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
 )
@@ -14,6 +16,7 @@ type Schedule struct {
 	scheduler.CreateScheduleInput
 	ScheduleArn     *string    `min:"1" type:"string"`
 	CreationDate    *time.Time `type:"timestamp"`
+	Qualifier       *string
 	ConfigFilePath  *string
 	ConfigFileIndex int
 }
@@ -34,11 +37,77 @@ func (s *Schedule) Source() string {
 	return knownAfterDeployArn
 }
 
+// SetStateMachineQualifiedArn sets the target arn, if the schedule has own qualifier, it takes precedence.
 func (s *Schedule) SetStateMachineQualifiedArn(stateMachineArn string) {
 	if s.Target == nil {
 		s.Target = &schedulertypes.Target{}
 	}
-	s.Target.Arn = &stateMachineArn
+	s.Target.Arn = aws.String(qualifyTriggerTargetArn(stateMachineArn, s.Qualifier))
+}
+
+// scheduleManagedByMarker is the ownership marker in description, because schedules can not be tagged.
+var scheduleManagedByMarker = fmt.Sprintf("[%s=%s]", tagManagedBy, appName)
+
+func (s *Schedule) IsManagedBy() bool {
+	return strings.Contains(coalesce(s.Description), scheduleManagedByMarker)
+}
+
+// maxScheduleDescriptionLength is the limit of the description of EventBridge Scheduler, including the marker.
+const maxScheduleDescriptionLength = 512
+
+// validateScheduleDescription returns an error if the description exceeds the limit after the marker is appended.
+func validateScheduleDescription(desc string) error {
+	if strings.Contains(desc, scheduleManagedByMarker) {
+		return nil
+	}
+	limit := maxScheduleDescriptionLength - len(scheduleManagedByMarker) - 1
+	if n := utf8.RuneCountInString(desc); n > limit {
+		return fmt.Errorf("description must be at most %d characters to append `%s`, but %d characters", limit, scheduleManagedByMarker, n)
+	}
+	return nil
+}
+
+// AppendManagedByMarker appends the ownership marker to the description.
+func (s *Schedule) AppendManagedByMarker() {
+	if s.IsManagedBy() {
+		return
+	}
+	if desc := coalesce(s.Description); desc != "" {
+		s.Description = aws.String(desc + " " + scheduleManagedByMarker)
+		return
+	}
+	s.Description = aws.String(scheduleManagedByMarker)
+}
+
+// scheduleOneOffMarker marks the one-off schedule created by `execute --at`.
+const scheduleOneOffMarker = "[OneOff]"
+
+// IsOneOff returns true if the schedule is the one-off execution created by `execute --at`.
+func (s *Schedule) IsOneOff() bool {
+	return s.IsManagedBy() && strings.Contains(coalesce(s.Description), scheduleOneOffMarker)
+}
+
+// RemoveManagedByMarker removes the ownership marker from the description.
+func (s *Schedule) RemoveManagedByMarker() {
+	desc := strings.TrimSpace(strings.ReplaceAll(coalesce(s.Description), scheduleManagedByMarker, ""))
+	if desc == "" {
+		s.Description = nil
+		return
+	}
+	s.Description = aws.String(desc)
+}
+
+// scheduleKey identifies the schedule by the group and the name, the same name can be used in other groups.
+func scheduleKey(groupName *string, name string) string {
+	group := coalesce(groupName)
+	if group == "" {
+		group = defaultScheduleGroupName
+	}
+	return group + "/" + name
+}
+
+func (s *Schedule) key() string {
+	return scheduleKey(s.GroupName, coalesce(s.Name))
 }
 
 func (s *Schedule) configureJSON() string {
@@ -50,40 +119,28 @@ func (s *Schedule) configureJSON() string {
 	})
 }
 
-func (s *Schedule) HasItPassed() bool {
-	if s.EndDate != nil {
-		log.Printf("[debug] check if schedule `%s` has passed, end_date=%s", coalesce(s.Name), s.EndDate.String())
-		if time.Now().After(*s.EndDate) {
-			return true
-		}
+// NextRuns returns at most n upcoming fire times after from.
+func (s *Schedule) NextRuns(from time.Time, n int) ([]ScheduledRun, error) {
+	input := &NextScheduledRunsInput{
+		ScheduleExpression:         coalesce(s.ScheduleExpression),
+		ScheduleExpressionTimezone: coalesce(s.ScheduleExpressionTimezone),
+		StartDate:                  s.StartDate,
+		EndDate:                    s.EndDate,
 	}
-	// ScheduleExpressionが at(yyyy-mm-ddThh:mm:ss) の場合は、時刻をパースして現在時刻と比較する
-	expression := coalesce(s.ScheduleExpression)
-	if strings.HasPrefix(expression, "at(") {
-		at := expression[3 : len(expression)-1]
-		tz := coalesce(s.ScheduleExpressionTimezone)
-		var loc *time.Location
-		if tz == "" {
-			loc = time.UTC
-		} else {
-			var err error
-			loc, err = time.LoadLocation(tz)
-			if err != nil {
-				log.Printf("[warn] failed to load location `%s` as : %s", tz, err)
-				return false
-			}
-		}
-		t, err := time.Parse("2006-01-02T15:04:05", at)
-		if err != nil {
-			log.Printf("[warn] failed to parse schedule expression `%s` as : %s", expression, err)
-			return false
-		}
-		log.Printf("[debug] check if schedule `%s` has passed, at=%s tz=%s", coalesce(s.Name), t.String(), loc.String())
-		t = t.In(loc)
-		now := time.Now().In(loc)
-		return now.After(t)
+	if s.FlexibleTimeWindow != nil && s.FlexibleTimeWindow.Mode == schedulertypes.FlexibleTimeWindowModeFlexible {
+		input.FlexibleWindow = time.Duration(coalesce(s.FlexibleTimeWindow.MaximumWindowInMinutes)) * time.Minute
+	}
+	return NextScheduledRuns(input, from, n)
+}
+
+func (s *Schedule) HasItPassed() bool {
+	runs, err := s.NextRuns(time.Now(), 1)
+	if err != nil {
+		log.Printf("[warn] failed to evaluate schedule `%s`: %s", coalesce(s.Name), err)
+		return false
 	}
-	return false
+	log.Printf("[debug] check if schedule `%s` has passed, next runs=%v", coalesce(s.Name), runs)
+	return len(runs) == 0
 }
 
 func (s *Schedule) String() string {
@@ -103,9 +160,35 @@ func (s *Schedule) DiffString(newSchedule *Schedule, unified bool) string {
 		JSONDiffToURI(to),
 		JSONDiffUnified(unified),
 	))
+	if s != nil && newSchedule != nil {
+		now := time.Now()
+		before, errBefore := s.NextRuns(now, diffNextRuns)
+		after, errAfter := newSchedule.NextRuns(now, diffNextRuns)
+		if errBefore == nil && errAfter == nil {
+			builder.WriteString(nextRunsDiffString(before, after))
+		}
+	}
 	return builder.String()
 }
 
+// UpdateScheduleInput converts to scheduler.UpdateScheduleInput.
+func (s *Schedule) UpdateScheduleInput() *scheduler.UpdateScheduleInput {
+	return &scheduler.UpdateScheduleInput{
+		Name:                       s.Name,
+		FlexibleTimeWindow:         s.FlexibleTimeWindow,
+		ScheduleExpression:         s.ScheduleExpression,
+		ScheduleExpressionTimezone: s.ScheduleExpressionTimezone,
+		State:                      s.State,
+		Target:                     s.Target,
+		ActionAfterCompletion:      s.ActionAfterCompletion,
+		Description:                s.Description,
+		EndDate:                    s.EndDate,
+		GroupName:                  s.GroupName,
+		StartDate:                  s.StartDate,
+		KmsKeyArn:                  s.KmsKeyArn,
+	}
+}
+
 func (s *Schedule) SetEnabled(enabled bool) {
 	if enabled {
 		s.State = schedulertypes.ScheduleStateEnabled
@@ -140,7 +223,7 @@ func (s Schedules) SetEnabled(enabled bool) {
 func (s Schedules) SyncState(other Schedules) {
 	for _, schedule := range s {
 		for _, otherSchedule := range other {
-			if coalesce(schedule.Name) == coalesce(otherSchedule.Name) {
+			if schedule.key() == otherSchedule.key() {
 				schedule.State = otherSchedule.State
 			}
 		}
@@ -148,12 +231,14 @@ func (s Schedules) SyncState(other Schedules) {
 }
 
 func (s Schedules) DiffString(newSchedules Schedules, unified bool) string {
-	result := sliceDiff(s, newSchedules, func(schedule *Schedule) string {
-		return coalesce(schedule.Name)
-	})
+	result := sliceDiff(s, newSchedules, (*Schedule).key)
 	var builder strings.Builder
 	var zero *Schedule
 	for _, schedule := range result.Delete {
+		if !schedule.IsManagedBy() {
+			log.Printf("[warn] schedule %s is not managed by %s, suppressed diff", coalesce(schedule.Name), appName)
+			continue
+		}
 		builder.WriteString(schedule.DiffString(zero, unified))
 		builder.WriteRune('\n')
 	}
@@ -179,6 +264,19 @@ func (s Schedules) FilterPassed() (result, passed Schedules) {
 	return result, passed
 }
 
+// FilterOneOff splits the one-off executions created by `execute --at` from the schedules.
+func (s Schedules) FilterOneOff() (result, oneOff Schedules) {
+	result = make(Schedules, 0, len(s))
+	for _, schedule := range s {
+		if schedule.IsOneOff() {
+			oneOff = append(oneOff, schedule)
+		} else {
+			result = append(result, schedule)
+		}
+	}
+	return result, oneOff
+}
+
 func (s Schedules) Names() []string {
 	names := make([]string, 0, len(s))
 	for _, schedule := range s {
@@ -189,6 +287,17 @@ func (s Schedules) Names() []string {
 	return names
 }
 
+// keys returns `group/name` of the schedules, for ScheduleNames of SearchRelatedSchedulesInput.
+func (s Schedules) keys() []string {
+	keys := make([]string, 0, len(s))
+	for _, schedule := range s {
+		if coalesce(schedule.Name) != "" {
+			keys = append(keys, schedule.key())
+		}
+	}
+	return keys
+}
+
 func (s Schedules) FindByName(name string) (*Schedule, bool) {
 	for _, schedule := range s {
 		if coalesce(schedule.Name) == name {
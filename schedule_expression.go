@@ -0,0 +1,485 @@
+package stefunny
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleExpression is a parsed schedule expression, at(), rate() or cron().
+// it understands both EventBridge rule syntax and EventBridge Scheduler syntax.
+type ScheduleExpression struct {
+	expression string
+	loc        *time.Location
+	at         *time.Time
+	rate       time.Duration
+	cron       *cronExpression
+}
+
+// ParseScheduleExpression parses schedule expression, timezone is used for at() and cron(), empty means UTC.
+func ParseScheduleExpression(expression string, timezone string) (*ScheduleExpression, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load location `%s`: %w", timezone, err)
+		}
+	}
+	e := &ScheduleExpression{
+		expression: expression,
+		loc:        loc,
+	}
+	open := strings.IndexByte(expression, '(')
+	if open < 0 || !strings.HasSuffix(expression, ")") {
+		return nil, fmt.Errorf("invalid schedule expression `%s`", expression)
+	}
+	kind, body := expression[:open], strings.TrimSpace(expression[open+1:len(expression)-1])
+	switch kind {
+	case "at":
+		t, err := time.ParseInLocation("2006-01-02T15:04:05", body, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid at expression `%s`: %w", expression, err)
+		}
+		e.at = &t
+	case "rate":
+		rate, err := parseRateExpression(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate expression `%s`: %w", expression, err)
+		}
+		e.rate = rate
+	case "cron":
+		c, err := parseCronExpression(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression `%s`: %w", expression, err)
+		}
+		e.cron = c
+	default:
+		return nil, fmt.Errorf("invalid schedule expression `%s`", expression)
+	}
+	return e, nil
+}
+
+func (e *ScheduleExpression) String() string {
+	return e.expression
+}
+
+// Next returns the first fire time after t, rate() is counted from anchor.
+// false is returned when the expression never fires after t.
+func (e *ScheduleExpression) Next(t time.Time, anchor time.Time) (time.Time, bool) {
+	switch {
+	case e.at != nil:
+		if e.at.After(t) {
+			return *e.at, true
+		}
+		return time.Time{}, false
+	case e.rate > 0:
+		if t.Before(anchor) {
+			return anchor, true
+		}
+		n := t.Sub(anchor)/e.rate + 1
+		return anchor.Add(n * e.rate), true
+	case e.cron != nil:
+		return e.cron.next(t, e.loc)
+	}
+	return time.Time{}, false
+}
+
+func parseRateExpression(body string) (time.Duration, error) {
+	fields := strings.Fields(body)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("rate must be `value unit`")
+	}
+	value, err := strconv.Atoi(fields[0])
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("rate value must be positive integer")
+	}
+	var unit time.Duration
+	switch fields[1] {
+	case "minute", "minutes":
+		unit = time.Minute
+	case "hour", "hours":
+		unit = time.Hour
+	case "day", "days":
+		unit = 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("unknown rate unit `%s`", fields[1])
+	}
+	return time.Duration(value) * unit, nil
+}
+
+// diffNextRuns is the number of upcoming fire times shown in diff.
+const diffNextRuns = 5
+
+const (
+	cronMinYear = 1970
+	cronMaxYear = 2199
+)
+
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	cronWeekdayNames = map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}
+)
+
+// cronExpression is cron(minutes hours day-of-month month day-of-week year), day-of-week is 1(SUN)-7(SAT).
+type cronExpression struct {
+	minutes []bool
+	hours   []bool
+	months  []bool
+	years   []bool
+	dom     *cronDayOfMonth
+	dow     *cronDayOfWeek
+}
+
+type cronDayOfMonth struct {
+	days           []bool
+	last           bool
+	lastWeekday    bool
+	nearestWeekday []int
+}
+
+type cronDayOfWeek struct {
+	days []bool
+	last []int
+	nth  [][2]int
+}
+
+func parseCronExpression(body string) (*cronExpression, error) {
+	fields := strings.Fields(body)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("cron must have 6 fields, minutes hours day-of-month month day-of-week year")
+	}
+	var c cronExpression
+	var err error
+	if c.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minutes: %w", err)
+	}
+	if c.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hours: %w", err)
+	}
+	if c.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if c.years, err = parseCronField(fields[5], cronMinYear, cronMaxYear, nil); err != nil {
+		return nil, fmt.Errorf("year: %w", err)
+	}
+	if fields[2] == "?" && fields[4] == "?" {
+		return nil, fmt.Errorf("day-of-month and day-of-week can not be both `?`")
+	}
+	if fields[2] != "?" && fields[4] != "?" {
+		return nil, fmt.Errorf("one of day-of-month or day-of-week must be `?`")
+	}
+	if fields[2] != "?" {
+		if c.dom, err = parseCronDayOfMonth(fields[2]); err != nil {
+			return nil, fmt.Errorf("day-of-month: %w", err)
+		}
+	}
+	if fields[4] != "?" {
+		if c.dow, err = parseCronDayOfWeek(fields[4]); err != nil {
+			return nil, fmt.Errorf("day-of-week: %w", err)
+		}
+	}
+	return &c, nil
+}
+
+func parseCronDayOfMonth(field string) (*cronDayOfMonth, error) {
+	dom := &cronDayOfMonth{days: make([]bool, 32)}
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case part == "L":
+			dom.last = true
+		case part == "LW":
+			dom.lastWeekday = true
+		case strings.HasSuffix(part, "W"):
+			day, err := strconv.Atoi(strings.TrimSuffix(part, "W"))
+			if err != nil || day < 1 || day > 31 {
+				return nil, fmt.Errorf("invalid value `%s`", part)
+			}
+			dom.nearestWeekday = append(dom.nearestWeekday, day)
+		default:
+			days, err := parseCronField(part, 1, 31, nil)
+			if err != nil {
+				return nil, err
+			}
+			mergeCronSet(dom.days, days)
+		}
+	}
+	return dom, nil
+}
+
+func parseCronDayOfWeek(field string) (*cronDayOfWeek, error) {
+	dow := &cronDayOfWeek{days: make([]bool, 8)}
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case part == "L":
+			dow.days[7] = true
+		case strings.Contains(part, "#"):
+			wd, n, _ := strings.Cut(part, "#")
+			weekday, err := parseCronValue(wd, 1, 7, cronWeekdayNames)
+			if err != nil {
+				return nil, err
+			}
+			nth, err := strconv.Atoi(n)
+			if err != nil || nth < 1 || nth > 5 {
+				return nil, fmt.Errorf("invalid value `%s`", part)
+			}
+			dow.nth = append(dow.nth, [2]int{weekday, nth})
+		case len(part) > 1 && strings.HasSuffix(part, "L"):
+			weekday, err := parseCronValue(strings.TrimSuffix(part, "L"), 1, 7, cronWeekdayNames)
+			if err != nil {
+				return nil, err
+			}
+			dow.last = append(dow.last, weekday)
+		default:
+			days, err := parseCronField(part, 1, 7, cronWeekdayNames)
+			if err != nil {
+				return nil, err
+			}
+			mergeCronSet(dow.days, days)
+		}
+	}
+	return dow, nil
+}
+
+// parseCronField parses `*`, `n`, `n-m`, `*/s`, `n/s`, `n-m/s` and the comma separated list of them.
+func parseCronField(field string, min, max int, names map[string]int) ([]bool, error) {
+	set := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step `%s`", part)
+			}
+		}
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(a, min, max, names); err != nil {
+				return nil, err
+			}
+			if hi, err = parseCronValue(b, min, max, names); err != nil {
+				return nil, err
+			}
+			if lo > hi {
+				return nil, fmt.Errorf("invalid range `%s`", part)
+			}
+		default:
+			var err error
+			if lo, err = parseCronValue(rangePart, min, max, names); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if hasStep {
+				hi = max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func parseCronValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value `%s`", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value `%d` out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+func mergeCronSet(dst, src []bool) {
+	for i, ok := range src {
+		if ok {
+			dst[i] = true
+		}
+	}
+}
+
+func (c *cronExpression) next(t time.Time, loc *time.Location) (time.Time, bool) {
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	if day.Year() < cronMinYear {
+		day = time.Date(cronMinYear, time.January, 1, 0, 0, 0, 0, loc)
+	}
+	for ; day.Year() <= cronMaxYear; day = day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+		if !c.years[y] {
+			day = time.Date(y+1, time.January, 0, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.months[m] {
+			day = time.Date(y, m+1, 0, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(y, m, d, loc) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if !c.hours[h] {
+				continue
+			}
+			for mi := 0; mi < 60; mi++ {
+				if !c.minutes[mi] {
+					continue
+				}
+				candidate := time.Date(y, m, d, h, mi, 0, 0, loc)
+				if !candidate.Before(t) {
+					return candidate, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (c *cronExpression) matchDay(y int, m time.Month, d int, loc *time.Location) bool {
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, loc).Day()
+	if c.dom != nil {
+		if c.dom.days[d] || (c.dom.last && d == last) {
+			return true
+		}
+		if c.dom.lastWeekday && d == nearestWeekday(y, m, last, last, loc) {
+			return true
+		}
+		for _, n := range c.dom.nearestWeekday {
+			if n <= last && d == nearestWeekday(y, m, n, last, loc) {
+				return true
+			}
+		}
+		return false
+	}
+	weekday := int(time.Date(y, m, d, 0, 0, 0, 0, loc).Weekday()) + 1
+	if c.dow.days[weekday] {
+		return true
+	}
+	for _, w := range c.dow.last {
+		if w == weekday && d+7 > last {
+			return true
+		}
+	}
+	for _, nth := range c.dow.nth {
+		if nth[0] == weekday && (d-1)/7+1 == nth[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestWeekday returns the weekday nearest to the day n in the same month.
+func nearestWeekday(y int, m time.Month, n int, last int, loc *time.Location) int {
+	switch time.Date(y, m, n, 0, 0, 0, 0, loc).Weekday() {
+	case time.Saturday:
+		if n == 1 {
+			return n + 2
+		}
+		return n - 1
+	case time.Sunday:
+		if n == last {
+			return n - 2
+		}
+		return n + 1
+	}
+	return n
+}
+
+// ScheduledRun is an upcoming fire time, the target is invoked within the flexible time window.
+type ScheduledRun struct {
+	Time           time.Time
+	FlexibleWindow time.Duration
+}
+
+func (r ScheduledRun) String() string {
+	if r.FlexibleWindow > 0 {
+		return fmt.Sprintf("%s (flexible window %s)", r.Time.Format(time.RFC3339), r.FlexibleWindow)
+	}
+	return r.Time.Format(time.RFC3339)
+}
+
+type NextScheduledRunsInput struct {
+	ScheduleExpression         string
+	ScheduleExpressionTimezone string
+	StartDate                  *time.Time
+	EndDate                    *time.Time
+	FlexibleWindow             time.Duration
+}
+
+// NextScheduledRuns returns at most n fire times after from.
+// rate() is counted from StartDate if specified, otherwise from `from`, because the creation time is not known.
+func NextScheduledRuns(input *NextScheduledRunsInput, from time.Time, n int) ([]ScheduledRun, error) {
+	expr, err := ParseScheduleExpression(input.ScheduleExpression, input.ScheduleExpressionTimezone)
+	if err != nil {
+		return nil, err
+	}
+	anchor := from
+	if input.StartDate != nil {
+		anchor = *input.StartDate
+		if input.StartDate.After(from) {
+			from = input.StartDate.Add(-time.Nanosecond)
+		}
+	}
+	runs := make([]ScheduledRun, 0, n)
+	for len(runs) < n {
+		next, ok := expr.Next(from, anchor)
+		if !ok {
+			break
+		}
+		if input.EndDate != nil && next.After(*input.EndDate) {
+			break
+		}
+		runs = append(runs, ScheduledRun{
+			Time:           next,
+			FlexibleWindow: input.FlexibleWindow,
+		})
+		from = next
+	}
+	return runs, nil
+}
+
+// nextRunsDiffString returns upcoming fire times of before and after, empty if both are same.
+func nextRunsDiffString(before, after []ScheduledRun) string {
+	if len(before) == len(after) {
+		same := true
+		for i := range before {
+			if before[i].String() != after[i].String() {
+				same = false
+				break
+			}
+		}
+		if same {
+			return ""
+		}
+	}
+	var builder strings.Builder
+	builder.WriteString("Next runs:\n")
+	for i := 0; i < len(before) || i < len(after); i++ {
+		from, to := "-", "-"
+		if i < len(before) {
+			from = before[i].String()
+		}
+		if i < len(after) {
+			to = after[i].String()
+		}
+		fmt.Fprintf(&builder, "  %s -> %s\n", from, to)
+	}
+	return builder.String()
+}
@@ -0,0 +1,168 @@
+package stefunny_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextScheduledRuns(t *testing.T) {
+	from := time.Date(2024, 2, 27, 10, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		input    stefunny.NextScheduledRunsInput
+		n        int
+		expected []string
+		isErr    bool
+	}{
+		{
+			name:     "at",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "at(2024-02-29T00:01:00)"},
+			n:        3,
+			expected: []string{"2024-02-29T00:01:00Z"},
+		},
+		{
+			name: "at with timezone",
+			input: stefunny.NextScheduledRunsInput{
+				ScheduleExpression:         "at(2024-02-29T00:01:00)",
+				ScheduleExpressionTimezone: "Asia/Tokyo",
+			},
+			n:        1,
+			expected: []string{"2024-02-29T00:01:00+09:00"},
+		},
+		{
+			name:     "at passed",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "at(2024-01-01T00:00:00)"},
+			n:        1,
+			expected: []string{},
+		},
+		{
+			name:     "rate",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "rate(1 hour)"},
+			n:        2,
+			expected: []string{"2024-02-27T11:30:00Z", "2024-02-27T12:30:00Z"},
+		},
+		{
+			name: "rate from start date",
+			input: stefunny.NextScheduledRunsInput{
+				ScheduleExpression: "rate(2 days)",
+				StartDate:          aws.Time(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			n:        2,
+			expected: []string{"2024-02-29T00:00:00Z", "2024-03-02T00:00:00Z"},
+		},
+		{
+			name:     "cron",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0/15 10 * * ? *)"},
+			n:        3,
+			expected: []string{"2024-02-27T10:45:00Z", "2024-02-28T10:00:00Z", "2024-02-28T10:15:00Z"},
+		},
+		{
+			name: "cron with timezone",
+			input: stefunny.NextScheduledRunsInput{
+				ScheduleExpression:         "cron(0 9 ? * MON-FRI *)",
+				ScheduleExpressionTimezone: "Asia/Tokyo",
+			},
+			n:        3,
+			expected: []string{"2024-02-28T09:00:00+09:00", "2024-02-29T09:00:00+09:00", "2024-03-01T09:00:00+09:00"},
+		},
+		{
+			name:     "cron last day of month",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0 0 L * ? *)"},
+			n:        2,
+			expected: []string{"2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z"},
+		},
+		{
+			name:     "cron nearest weekday",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0 0 16W * ? *)"},
+			n:        2,
+			expected: []string{"2024-03-15T00:00:00Z", "2024-04-16T00:00:00Z"},
+		},
+		{
+			name:     "cron nth weekday",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0 0 ? * 2#1 *)"},
+			n:        2,
+			expected: []string{"2024-03-04T00:00:00Z", "2024-04-01T00:00:00Z"},
+		},
+		{
+			name:     "cron last weekday of month",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0 0 ? * 6L *)"},
+			n:        1,
+			expected: []string{"2024-03-29T00:00:00Z"},
+		},
+		{
+			name:     "cron year",
+			input:    stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0 0 1 JAN ? 2025-2026)"},
+			n:        3,
+			expected: []string{"2025-01-01T00:00:00Z", "2026-01-01T00:00:00Z"},
+		},
+		{
+			name: "end date",
+			input: stefunny.NextScheduledRunsInput{
+				ScheduleExpression: "cron(0 0 * * ? *)",
+				EndDate:            aws.Time(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)),
+			},
+			n:        5,
+			expected: []string{"2024-02-28T00:00:00Z", "2024-02-29T00:00:00Z"},
+		},
+		{
+			name: "flexible window",
+			input: stefunny.NextScheduledRunsInput{
+				ScheduleExpression: "at(2024-02-29T00:01:00)",
+				FlexibleWindow:     15 * time.Minute,
+			},
+			n:        1,
+			expected: []string{"2024-02-29T00:01:00Z (flexible window 15m0s)"},
+		},
+		{
+			name:  "cron both question marks",
+			input: stefunny.NextScheduledRunsInput{ScheduleExpression: "cron(0 0 ? * ? *)"},
+			isErr: true,
+		},
+		{
+			name:  "invalid rate unit",
+			input: stefunny.NextScheduledRunsInput{ScheduleExpression: "rate(1 week)"},
+			isErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			runs, err := stefunny.NextScheduledRuns(&c.input, from, c.n)
+			if c.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			actual := make([]string, 0, len(runs))
+			for _, run := range runs {
+				actual = append(actual, run.String())
+			}
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestSchedule__HasItPassed(t *testing.T) {
+	newSchedule := func(expression string, endDate *time.Time) *stefunny.Schedule {
+		return &stefunny.Schedule{
+			CreateScheduleInput: scheduler.CreateScheduleInput{
+				Name:               aws.String("Hello"),
+				ScheduleExpression: aws.String(expression),
+				EndDate:            endDate,
+				FlexibleTimeWindow: &schedulertypes.FlexibleTimeWindow{
+					Mode: schedulertypes.FlexibleTimeWindowModeOff,
+				},
+			},
+		}
+	}
+	require.True(t, newSchedule("at(1900-01-01T00:00:00)", nil).HasItPassed())
+	require.False(t, newSchedule("at(2199-01-01T00:00:00)", nil).HasItPassed())
+	require.False(t, newSchedule("rate(1 hour)", nil).HasItPassed())
+	require.True(t, newSchedule("rate(1 hour)", aws.Time(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))).HasItPassed())
+	require.True(t, newSchedule("cron(0 0 1 1 ? 2000)", nil).HasItPassed())
+}
@@ -0,0 +1,97 @@
+package stefunny
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+const defaultScheduleGroupName = "default"
+
+type ScheduleGroup struct {
+	Name string
+	Arn  *string
+	Tags map[string]string
+}
+
+// IsManagedBy returns true if the group is created by stefunny for the state machine.
+func (g *ScheduleGroup) IsManagedBy(stateMachineName string) bool {
+	return g.Tags[tagManagedBy] == appName && g.Tags[tagManagedStateMachine] == stateMachineName
+}
+
+func (g *ScheduleGroup) schedulerTags() []schedulertypes.Tag {
+	keys := make([]string, 0, len(g.Tags))
+	for key := range g.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tags := make([]schedulertypes.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, schedulertypes.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(g.Tags[key]),
+		})
+	}
+	return tags
+}
+
+// hasSameTags returns true if the group has all tags of other.
+func (g *ScheduleGroup) hasSameTags(other *ScheduleGroup) bool {
+	for key, value := range other.Tags {
+		if v, ok := g.Tags[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *ScheduleGroup) String() string {
+	if g.Arn != nil {
+		return fmt.Sprintf("%s (%s)", g.Name, *g.Arn)
+	}
+	return g.Name
+}
+
+type ScheduleGroups []*ScheduleGroup
+
+func (groups ScheduleGroups) Names() []string {
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func (groups ScheduleGroups) FindByName(name string) (*ScheduleGroup, bool) {
+	for _, g := range groups {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+func (groups ScheduleGroups) String() string {
+	var builder strings.Builder
+	for _, g := range groups {
+		builder.WriteString(g.String())
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+// sort.Interfaces
+func (groups ScheduleGroups) Len() int {
+	return len(groups)
+}
+
+func (groups ScheduleGroups) Less(i, j int) bool {
+	return groups[i].Name < groups[j].Name
+}
+
+func (groups ScheduleGroups) Swap(i, j int) {
+	groups[i], groups[j] = groups[j], groups[i]
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
@@ -21,115 +24,149 @@ type SchedulerClient interface {
 	GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error)
 	ListSchedules(ctx context.Context, params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error)
 	ListScheduleGroups(ctx context.Context, params *scheduler.ListScheduleGroupsInput, optFns ...func(*scheduler.Options)) (*scheduler.ListScheduleGroupsOutput, error)
+	GetScheduleGroup(ctx context.Context, params *scheduler.GetScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleGroupOutput, error)
 	UpdateSchedule(ctx context.Context, params *scheduler.UpdateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error)
+	CreateScheduleGroup(ctx context.Context, params *scheduler.CreateScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleGroupOutput, error)
+	DeleteScheduleGroup(ctx context.Context, params *scheduler.DeleteScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleGroupOutput, error)
+	ListTagsForResource(ctx context.Context, params *scheduler.ListTagsForResourceInput, optFns ...func(*scheduler.Options)) (*scheduler.ListTagsForResourceOutput, error)
+	TagResource(ctx context.Context, params *scheduler.TagResourceInput, optFns ...func(*scheduler.Options)) (*scheduler.TagResourceOutput, error)
 }
 
 type SchedulerService interface {
 	SearchRelatedSchedules(ctx context.Context, params *SearchRelatedSchedulesInput) (Schedules, error)
 	DeploySchedules(ctx context.Context, stateMachineArn string, rules Schedules, keepState bool) error
+	SetScheduleEnabled(ctx context.Context, schedule *Schedule, enabled bool) error
+	SearchScheduleGroups(ctx context.Context, stateMachineName string, groupNames []string) (ScheduleGroups, error)
+	DeployScheduleGroups(ctx context.Context, stateMachineName string, groups ScheduleGroups) error
+	PruneScheduleGroups(ctx context.Context, stateMachineName string, groups ScheduleGroups) error
+	CreateOneOffSchedule(ctx context.Context, schedule *Schedule) error
+	DeleteOneOffSchedule(ctx context.Context, stateMachineArn string, name string) error
 }
 
 var _ SchedulerService = (*SchedulerServiceImpl)(nil)
 
 type SchedulerServiceImpl struct {
-	client                      SchedulerClient
-	cacheNamesByStateMachineArn map[string][]string
-	cacheScheduleByName         map[string]*scheduler.GetScheduleOutput
-	cacheGroups                 []schedulertypes.ScheduleGroupSummary
+	client               SchedulerClient
+	cacheKeysByTargetArn map[string][]string
+	cacheScheduleByKey   map[string]*scheduler.GetScheduleOutput
+	cacheGroups          []schedulertypes.ScheduleGroupSummary
+	cacheGroupTagsByName map[string]map[string]string
 }
 
 func NewSchedulerService(client SchedulerClient) *SchedulerServiceImpl {
 	return &SchedulerServiceImpl{
-		client:                      client,
-		cacheNamesByStateMachineArn: make(map[string][]string),
-		cacheScheduleByName:         make(map[string]*scheduler.GetScheduleOutput),
+		client:               client,
+		cacheScheduleByKey:   make(map[string]*scheduler.GetScheduleOutput),
+		cacheGroupTagsByName: make(map[string]map[string]string),
 	}
 }
 
 type SearchRelatedSchedulesInput struct {
 	StateMachineQualifiedArn string
-	ScheduleNames            []string
+	// ScheduleNames are the names of schedules, or `group/name` to get the schedule of the group.
+	// the name without the group is searched in all groups.
+	ScheduleNames []string
 }
 
 func (svc *SchedulerServiceImpl) SearchRelatedSchedules(ctx context.Context, params *SearchRelatedSchedulesInput) (Schedules, error) {
 	log.Printf("[debug] call SearchRelatedSchedules(%#v)", params)
 	stateMachineArn := params.StateMachineQualifiedArn
-	scheduleNames, err := svc.searchRelatedScheduleNames(ctx, stateMachineArn)
+	scheduleKeys, otherKeys, err := svc.searchRelatedScheduleKeys(ctx, stateMachineArn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search related schedule names: %w", err)
 	}
 	if len(params.ScheduleNames) > 0 {
-		scheduleNames = append(scheduleNames, params.ScheduleNames...)
-		scheduleNames = unique(scheduleNames)
+		scheduleKeys = append(scheduleKeys, params.ScheduleNames...)
+		scheduleKeys = unique(scheduleKeys)
 	}
-	schedules := make(Schedules, 0, len(scheduleNames))
-	for _, name := range scheduleNames {
-		schedule, err := svc.getSchedule(ctx, name)
+	schedules := make(Schedules, 0, len(scheduleKeys))
+	seen := make(map[string]struct{}, len(scheduleKeys))
+	for _, key := range scheduleKeys {
+		schedule, err := svc.getSchedule(ctx, key)
 		if err != nil {
 			if !errors.Is(err, ErrScheduleNotFound) {
-				return nil, fmt.Errorf("failed to get schedule `%s`: %w", name, err)
+				return nil, fmt.Errorf("failed to get schedule `%s`: %w", key, err)
 			}
 			continue
 		}
+		// the name without the group may be the same schedule as the key of the search.
+		if _, ok := seen[schedule.key()]; ok {
+			continue
+		}
+		seen[schedule.key()] = struct{}{}
+		schedules = append(schedules, schedule)
+	}
+	for _, key := range otherKeys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		schedule, err := svc.getSchedule(ctx, key)
+		if err != nil {
+			if !errors.Is(err, ErrScheduleNotFound) {
+				return nil, fmt.Errorf("failed to get schedule `%s`: %w", key, err)
+			}
+			continue
+		}
+		// the schedule of other qualifiers is related only if stefunny manages it, such as the schedule of `qualifier`.
+		if !schedule.IsManagedBy() {
+			log.Printf("[debug] schedule `%s` targets other qualifier, but not managed by %s", key, appName)
+			continue
+		}
+		seen[schedule.key()] = struct{}{}
 		schedules = append(schedules, schedule)
 	}
 	return schedules, nil
 }
 
-func (svc *SchedulerServiceImpl) searchRelatedScheduleNames(ctx context.Context, stateMachineArn string) ([]string, error) {
-	log.Printf("[debug] call searchRelatedScheduleNames(%s)", stateMachineArn)
+// searchRelatedScheduleKeys returns `group/name` of the schedules that target the qualified arn or the unqualified arn of the state machine,
+// and the schedules that target other aliases and versions of it.
+func (svc *SchedulerServiceImpl) searchRelatedScheduleKeys(ctx context.Context, stateMachineArn string) ([]string, []string, error) {
+	log.Printf("[debug] call searchRelatedScheduleKeys(%s)", stateMachineArn)
 	unqualified := removeQualifierFromArn(stateMachineArn)
 	log.Printf("[debug] state machine arn is `%s`", stateMachineArn)
 	log.Printf("[debug] unqualified state machine arn is `%s`", unqualified)
-	names, ok := svc.cacheNamesByStateMachineArn[stateMachineArn]
-	if ok {
-		if unqualified != stateMachineArn {
-			unqualifiedNames, ok := svc.cacheNamesByStateMachineArn[unqualified]
-			if !ok {
-				log.Println("[warn] unqualified state machine Arn is not found in cache")
-			}
-			names = append(names, unqualifiedNames...)
-			names = unique(names)
-		}
-		return names, nil
-	}
-	unqualifiedNames := make([]string, 0, 100)
-	err := svc.forEachGroups(ctx, func(ctx context.Context, group schedulertypes.ScheduleGroupSummary) error {
-		p := scheduler.NewListSchedulesPaginator(svc.client, &scheduler.ListSchedulesInput{
-			GroupName:  group.Name,
-			MaxResults: aws.Int32(100),
-		})
-		for p.HasMorePages() {
-			page, err := p.NextPage(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to list schedules: %w", err)
-			}
-			for _, schedule := range page.Schedules {
-				targetArn := coalesce(schedule.Target.Arn)
-				log.Printf("[debug] schedule `%s` target Arn is `%s`", coalesce(schedule.Name), targetArn)
-				if targetArn == stateMachineArn {
-					names = append(names, coalesce(schedule.Name))
+	if svc.cacheKeysByTargetArn == nil {
+		keysByTargetArn := make(map[string][]string)
+		err := svc.forEachGroups(ctx, func(ctx context.Context, group schedulertypes.ScheduleGroupSummary) error {
+			p := scheduler.NewListSchedulesPaginator(svc.client, &scheduler.ListSchedulesInput{
+				GroupName:  group.Name,
+				MaxResults: aws.Int32(100),
+			})
+			for p.HasMorePages() {
+				page, err := p.NextPage(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to list schedules: %w", err)
 				}
-				if targetArn == unqualified {
-					unqualifiedNames = append(unqualifiedNames, coalesce(schedule.Name))
+				for _, schedule := range page.Schedules {
+					if schedule.Target == nil {
+						continue
+					}
+					targetArn := coalesce(schedule.Target.Arn)
+					log.Printf("[debug] schedule `%s` target Arn is `%s`", coalesce(schedule.Name), targetArn)
+					keysByTargetArn[targetArn] = append(keysByTargetArn[targetArn], scheduleKey(group.Name, coalesce(schedule.Name)))
 				}
 			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		svc.cacheKeysByTargetArn = keysByTargetArn
 	}
-	names = unique(names)
-	svc.cacheNamesByStateMachineArn[stateMachineArn] = names
-	if unqualified == stateMachineArn {
-		return names, nil
+	keys := make([]string, 0)
+	keys = append(keys, svc.cacheKeysByTargetArn[stateMachineArn]...)
+	if unqualified != stateMachineArn {
+		keys = append(keys, svc.cacheKeysByTargetArn[unqualified]...)
 	}
-	svc.cacheNamesByStateMachineArn[unqualified] = unqualifiedNames
-	result := make([]string, 0, len(names)+len(unqualifiedNames))
-	result = append(result, names...)
-	result = append(result, unqualifiedNames...)
-	return unique(result), nil
+	otherKeys := make([]string, 0)
+	for targetArn, targetKeys := range svc.cacheKeysByTargetArn {
+		if targetArn == stateMachineArn || targetArn == unqualified || !isStateMachineTarget(targetArn, stateMachineArn) {
+			continue
+		}
+		otherKeys = append(otherKeys, targetKeys...)
+	}
+	sort.Strings(otherKeys)
+	return unique(keys), unique(otherKeys), nil
 }
 
 func (svc *SchedulerServiceImpl) forEachGroups(ctx context.Context, fn func(ctx context.Context, group schedulertypes.ScheduleGroupSummary) error) error {
@@ -161,40 +198,52 @@ func (svc *SchedulerServiceImpl) forEachGroups(ctx context.Context, fn func(ctx
 	return nil
 }
 
+// getSchedule gets the schedule of `group/name`, or the schedule of the name found first in all groups.
 func (svc *SchedulerServiceImpl) getSchedule(ctx context.Context, name string) (*Schedule, error) {
 	log.Printf("[debug] call getSchedule(%s)", name)
-	schedule, ok := svc.cacheScheduleByName[name]
-	if !ok {
-		var found bool
+	var schedule *scheduler.GetScheduleOutput
+	var found bool
+	get := func(ctx context.Context, groupName string, name string) error {
+		key := scheduleKey(&groupName, name)
+		if cached, ok := svc.cacheScheduleByKey[key]; ok {
+			schedule, found = cached, true
+			return nil
+		}
+		output, err := svc.client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+			Name:      aws.String(name),
+			GroupName: aws.String(groupName),
+		})
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ResourceNotFoundException" {
+				return nil
+			}
+			return fmt.Errorf("scheduler.GetSchedule `%s`: %w", key, err)
+		}
+		svc.cacheScheduleByKey[key] = output
+		schedule, found = output, true
+		return nil
+	}
+	if groupName, scheduleName, ok := strings.Cut(name, "/"); ok {
+		if err := get(ctx, groupName, scheduleName); err != nil {
+			return nil, err
+		}
+	} else {
 		err := svc.forEachGroups(ctx, func(ctx context.Context, group schedulertypes.ScheduleGroupSummary) error {
-			var err error
 			if found {
 				return nil
 			}
 			if group.State != schedulertypes.ScheduleGroupStateActive {
 				return nil
 			}
-			schedule, err = svc.client.GetSchedule(ctx, &scheduler.GetScheduleInput{
-				Name:      aws.String(name),
-				GroupName: group.Name,
-			})
-			if err != nil {
-				var apiErr smithy.APIError
-				if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ResourceNotFoundException" {
-					return nil
-				}
-				return fmt.Errorf("scheduler.GetSchedule `%s`: %w", name, err)
-			}
-			found = true
-			return nil
+			return get(ctx, coalesce(group.Name), name)
 		})
 		if err != nil {
 			return nil, err
 		}
-		if !found {
-			return nil, ErrScheduleNotFound
-		}
-		svc.cacheScheduleByName[name] = schedule
+	}
+	if !found {
+		return nil, ErrScheduleNotFound
 	}
 	result := &Schedule{
 		CreateScheduleInput: scheduler.CreateScheduleInput{
@@ -224,48 +273,42 @@ func (svc *SchedulerServiceImpl) DeploySchedules(ctx context.Context, stateMachi
 	}
 	currentSchedules, err := svc.SearchRelatedSchedules(ctx, &SearchRelatedSchedulesInput{
 		StateMachineQualifiedArn: stateMachineArn,
-		ScheduleNames:            newSchedules.Names(),
+		ScheduleNames:            newSchedules.keys(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to search related schedules: %w", err)
 	}
+	currentSchedules, _ = currentSchedules.FilterOneOff()
 	if keepState {
 		schedules.SyncState(currentSchedules)
 	}
 	newSchedules.SetStateMachineQualifiedArn(stateMachineArn)
-	plan := sliceDiff(currentSchedules, newSchedules, func(schedule *Schedule) string {
-		return coalesce(schedule.Name)
-	})
+	plan := sliceDiff(currentSchedules, newSchedules, (*Schedule).key)
 	for _, schedule := range plan.Delete {
+		if !schedule.IsManagedBy() {
+			log.Printf("[warn] schedule `%s` that %s does not manage targets the state machine. skip delete this schedule", coalesce(schedule.Name), appName)
+			continue
+		}
 		log.Println("[info] delete schedule", coalesce(schedule.ScheduleArn))
 		_, err := svc.client.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
-			Name: schedule.Name,
+			Name:      schedule.Name,
+			GroupName: schedule.GroupName,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to delete schedule `%s`: %w", coalesce(schedule.Name), err)
 		}
+		delete(svc.cacheScheduleByKey, schedule.key())
 	}
 	for _, schedule := range plan.Change {
 		log.Println("[info] update schedule", coalesce(schedule.Before.ScheduleArn))
-		if _, err := svc.client.UpdateSchedule(ctx, &scheduler.UpdateScheduleInput{
-			Name:                       schedule.After.Name,
-			FlexibleTimeWindow:         schedule.After.FlexibleTimeWindow,
-			ScheduleExpression:         schedule.After.ScheduleExpression,
-			ScheduleExpressionTimezone: schedule.After.ScheduleExpressionTimezone,
-			State:                      schedule.After.State,
-			Target:                     schedule.After.Target,
-			ActionAfterCompletion:      schedule.After.ActionAfterCompletion,
-			Description:                schedule.After.Description,
-			EndDate:                    schedule.After.EndDate,
-			GroupName:                  schedule.After.GroupName,
-			StartDate:                  schedule.After.StartDate,
-			KmsKeyArn:                  schedule.After.KmsKeyArn,
-		}); err != nil {
+		schedule.After.AppendManagedByMarker()
+		if _, err := svc.client.UpdateSchedule(ctx, schedule.After.UpdateScheduleInput()); err != nil {
 			return fmt.Errorf("failed to update schedule `%s`: %w", coalesce(schedule.Before.Name), err)
 		}
 	}
 	for _, schedule := range plan.Add {
 		log.Println("[info] create schedule", coalesce(schedule.Name))
+		schedule.AppendManagedByMarker()
 		output, err := svc.client.CreateSchedule(ctx, &schedule.CreateScheduleInput)
 		if err != nil {
 			return fmt.Errorf("failed to create schedule `%s`: %w", coalesce(schedule.Name), err)
@@ -274,3 +317,219 @@ func (svc *SchedulerServiceImpl) DeploySchedules(ctx context.Context, stateMachi
 	}
 	return nil
 }
+
+// SetScheduleEnabled changes only the state of the schedule, other configuration is kept.
+func (svc *SchedulerServiceImpl) SetScheduleEnabled(ctx context.Context, schedule *Schedule, enabled bool) error {
+	schedule.SetEnabled(enabled)
+	if _, err := svc.client.UpdateSchedule(ctx, schedule.UpdateScheduleInput()); err != nil {
+		return fmt.Errorf("failed to update schedule `%s`: %w", coalesce(schedule.Name), err)
+	}
+	delete(svc.cacheScheduleByKey, schedule.key())
+	return nil
+}
+
+// SearchScheduleGroups returns schedule groups of the names and the groups owned by the state machine.
+// the groups of the names are got directly, and the owned groups are found by the tags of all groups.
+func (svc *SchedulerServiceImpl) SearchScheduleGroups(ctx context.Context, stateMachineName string, groupNames []string) (ScheduleGroups, error) {
+	log.Printf("[debug] call SearchScheduleGroups(%s, %v)", stateMachineName, groupNames)
+	groups, err := svc.getScheduleGroups(ctx, groupNames)
+	if err != nil {
+		return nil, err
+	}
+	err = svc.forEachGroups(ctx, func(ctx context.Context, summary schedulertypes.ScheduleGroupSummary) error {
+		name := coalesce(summary.Name)
+		if name == defaultScheduleGroupName || summary.State != schedulertypes.ScheduleGroupStateActive || slices.Contains(groupNames, name) {
+			return nil
+		}
+		tags, err := svc.listScheduleGroupTags(ctx, name, coalesce(summary.Arn))
+		if err != nil {
+			return err
+		}
+		group := &ScheduleGroup{
+			Name: name,
+			Arn:  summary.Arn,
+			Tags: tags,
+		}
+		if group.IsManagedBy(stateMachineName) {
+			groups = append(groups, group)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(groups)
+	return groups, nil
+}
+
+// getScheduleGroups returns the active schedule groups of the names, the group that does not exist is not returned.
+func (svc *SchedulerServiceImpl) getScheduleGroups(ctx context.Context, groupNames []string) (ScheduleGroups, error) {
+	groups := make(ScheduleGroups, 0, len(groupNames))
+	for _, name := range groupNames {
+		if name == defaultScheduleGroupName {
+			continue
+		}
+		output, err := svc.client.GetScheduleGroup(ctx, &scheduler.GetScheduleGroupInput{
+			Name: aws.String(name),
+		})
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ResourceNotFoundException" {
+				continue
+			}
+			return nil, fmt.Errorf("scheduler.GetScheduleGroup `%s`: %w", name, err)
+		}
+		if output.State != schedulertypes.ScheduleGroupStateActive {
+			continue
+		}
+		tags, err := svc.listScheduleGroupTags(ctx, name, coalesce(output.Arn))
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, &ScheduleGroup{
+			Name: name,
+			Arn:  output.Arn,
+			Tags: tags,
+		})
+	}
+	return groups, nil
+}
+
+func (svc *SchedulerServiceImpl) listScheduleGroupTags(ctx context.Context, name string, arn string) (map[string]string, error) {
+	if tags, ok := svc.cacheGroupTagsByName[name]; ok {
+		return tags, nil
+	}
+	output, err := svc.client.ListTagsForResource(ctx, &scheduler.ListTagsForResourceInput{
+		ResourceArn: aws.String(arn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of schedule group `%s`: %w", name, err)
+	}
+	tags := make(map[string]string, len(output.Tags))
+	for _, tag := range output.Tags {
+		tags[coalesce(tag.Key)] = coalesce(tag.Value)
+	}
+	svc.cacheGroupTagsByName[name] = tags
+	return tags, nil
+}
+
+// DeployScheduleGroups creates the schedule groups, or updates tags of existing groups.
+func (svc *SchedulerServiceImpl) DeployScheduleGroups(ctx context.Context, stateMachineName string, groups ScheduleGroups) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	current, err := svc.getScheduleGroups(ctx, groups.Names())
+	if err != nil {
+		return fmt.Errorf("failed to get schedule groups: %w", err)
+	}
+	created := false
+	for _, group := range groups {
+		if cur, ok := current.FindByName(group.Name); ok {
+			group.Arn = cur.Arn
+			if cur.hasSameTags(group) {
+				continue
+			}
+			if !cur.IsManagedBy(stateMachineName) {
+				log.Printf("[warn] schedule group `%s` that %s does not manage is taken over", group.Name, appName)
+			}
+			log.Println("[info] update tags of schedule group", group.Name)
+			if _, err := svc.client.TagResource(ctx, &scheduler.TagResourceInput{
+				ResourceArn: cur.Arn,
+				Tags:        group.schedulerTags(),
+			}); err != nil {
+				return fmt.Errorf("failed to tag schedule group `%s`: %w", group.Name, err)
+			}
+			delete(svc.cacheGroupTagsByName, group.Name)
+			continue
+		}
+		log.Println("[info] create schedule group", group.Name)
+		output, err := svc.client.CreateScheduleGroup(ctx, &scheduler.CreateScheduleGroupInput{
+			Name: aws.String(group.Name),
+			Tags: group.schedulerTags(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create schedule group `%s`: %w", group.Name, err)
+		}
+		group.Arn = output.ScheduleGroupArn
+		created = true
+	}
+	if created {
+		svc.cacheGroups = nil
+	}
+	return nil
+}
+
+// PruneScheduleGroups deletes the groups owned by the state machine that are not in groups.
+// the group that still has schedules is not deleted, because deleting a group deletes its schedules.
+func (svc *SchedulerServiceImpl) PruneScheduleGroups(ctx context.Context, stateMachineName string, groups ScheduleGroups) error {
+	current, err := svc.SearchScheduleGroups(ctx, stateMachineName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to search schedule groups: %w", err)
+	}
+	deleted := false
+	for _, group := range current {
+		if _, ok := groups.FindByName(group.Name); ok {
+			continue
+		}
+		if !group.IsManagedBy(stateMachineName) {
+			continue
+		}
+		output, err := svc.client.ListSchedules(ctx, &scheduler.ListSchedulesInput{
+			GroupName:  aws.String(group.Name),
+			MaxResults: aws.Int32(1),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list schedules of group `%s`: %w", group.Name, err)
+		}
+		if len(output.Schedules) > 0 {
+			log.Printf("[warn] schedule group `%s` is not empty. skip delete this schedule group", group.Name)
+			continue
+		}
+		log.Println("[info] delete schedule group", group.Name)
+		if _, err := svc.client.DeleteScheduleGroup(ctx, &scheduler.DeleteScheduleGroupInput{
+			Name: aws.String(group.Name),
+		}); err != nil {
+			return fmt.Errorf("failed to delete schedule group `%s`: %w", group.Name, err)
+		}
+		delete(svc.cacheGroupTagsByName, group.Name)
+		deleted = true
+	}
+	if deleted {
+		svc.cacheGroups = nil
+	}
+	return nil
+}
+
+// CreateOneOffSchedule creates the one-off execution schedule of `execute --at`.
+func (svc *SchedulerServiceImpl) CreateOneOffSchedule(ctx context.Context, schedule *Schedule) error {
+	log.Println("[info] create one-off schedule", coalesce(schedule.Name))
+	output, err := svc.client.CreateSchedule(ctx, &schedule.CreateScheduleInput)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule `%s`: %w", coalesce(schedule.Name), err)
+	}
+	schedule.ScheduleArn = output.ScheduleArn
+	return nil
+}
+
+// DeleteOneOffSchedule deletes the one-off execution schedule of `execute --at` that targets the state machine.
+func (svc *SchedulerServiceImpl) DeleteOneOffSchedule(ctx context.Context, stateMachineArn string, name string) error {
+	schedule, err := svc.getSchedule(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule `%s`: %w", name, err)
+	}
+	if !schedule.IsOneOff() {
+		return fmt.Errorf("schedule `%s` is not a one-off execution scheduled by %s", name, appName)
+	}
+	if schedule.Target == nil || removeQualifierFromArn(coalesce(schedule.Target.Arn)) != removeQualifierFromArn(stateMachineArn) {
+		return fmt.Errorf("schedule `%s` does not target the state machine", name)
+	}
+	log.Println("[info] delete one-off schedule", coalesce(schedule.ScheduleArn))
+	if _, err := svc.client.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
+		Name:      schedule.Name,
+		GroupName: schedule.GroupName,
+	}); err != nil {
+		return fmt.Errorf("failed to delete schedule `%s`: %w", name, err)
+	}
+	delete(svc.cacheScheduleByKey, schedule.key())
+	return nil
+}
@@ -171,6 +171,12 @@ func TestSchedulerService__DeploySchedules(t *testing.T) {
 						Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled"),
 					},
 				},
+				{
+					Name: aws.String("Unmanaged"),
+					Target: &schedulertypes.TargetSummary{
+						Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current"),
+					},
+				},
 			},
 		},
 		nil,
@@ -196,6 +202,7 @@ func TestSchedulerService__DeploySchedules(t *testing.T) {
 	}).Return(
 		&scheduler.GetScheduleOutput{
 			Name:               aws.String("Unqualified"),
+			Description:        aws.String("daily job [ManagedBy=stefunny]"),
 			ScheduleExpression: aws.String("rate(1 day)"),
 			State:              schedulertypes.ScheduleStateEnabled,
 			Target: &schedulertypes.Target{
@@ -205,6 +212,22 @@ func TestSchedulerService__DeploySchedules(t *testing.T) {
 		},
 		nil,
 	).Times(1)
+	m.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+		Name:      aws.String("Unmanaged"),
+		GroupName: aws.String("default"),
+	}).Return(
+		&scheduler.GetScheduleOutput{
+			Name:               aws.String("Unmanaged"),
+			Description:        aws.String("created by hand"),
+			ScheduleExpression: aws.String("rate(1 day)"),
+			State:              schedulertypes.ScheduleStateEnabled,
+			Target: &schedulertypes.Target{
+				Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current"),
+			},
+			Arn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule:Unmanaged"),
+		},
+		nil,
+	).Times(1)
 	m.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
 		Name:      aws.String("Monthly"),
 		GroupName: aws.String("default"),
@@ -224,6 +247,7 @@ func TestSchedulerService__DeploySchedules(t *testing.T) {
 		func(ctx context.Context, input *scheduler.UpdateScheduleInput, opts ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
 			assert.EqualValues(t, &scheduler.UpdateScheduleInput{
 				Name:               aws.String("Scheduled"),
+				Description:        aws.String("[ManagedBy=stefunny]"),
 				ScheduleExpression: aws.String("rate(1 hour)"),
 				State:              schedulertypes.ScheduleStateDisabled,
 				Target: &schedulertypes.Target{
@@ -236,6 +260,7 @@ func TestSchedulerService__DeploySchedules(t *testing.T) {
 		func(ctx context.Context, input *scheduler.CreateScheduleInput, opts ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
 			assert.EqualValues(t, &scheduler.CreateScheduleInput{
 				Name:               aws.String("Monthly"),
+				Description:        aws.String("[ManagedBy=stefunny]"),
 				ScheduleExpression: aws.String("cron(0 0 1 * ? *)"),
 				State:              schedulertypes.ScheduleStateEnabled,
 				Target: &schedulertypes.Target{
@@ -286,3 +311,367 @@ func TestSchedulerService__DeploySchedules(t *testing.T) {
 	}, true)
 	require.NoError(t, err)
 }
+
+func TestSchedulerService__DeployScheduleGroups(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSchedulerClient(ctrl)
+	defer ctrl.Finish()
+
+	// the groups in config are got directly, all groups are not listed.
+	m.EXPECT().GetScheduleGroup(gomock.Any(), &scheduler.GetScheduleGroupInput{
+		Name: aws.String("created"),
+	}).Return(nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException"}).Times(1)
+	m.EXPECT().GetScheduleGroup(gomock.Any(), &scheduler.GetScheduleGroupInput{
+		Name: aws.String("retagged"),
+	}).Return(
+		&scheduler.GetScheduleGroupOutput{
+			Name:  aws.String("retagged"),
+			Arn:   aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/retagged"),
+			State: schedulertypes.ScheduleGroupStateActive,
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().ListTagsForResource(gomock.Any(), &scheduler.ListTagsForResourceInput{
+		ResourceArn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/retagged"),
+	}).Return(
+		&scheduler.ListTagsForResourceOutput{
+			Tags: []schedulertypes.Tag{
+				{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+				{Key: aws.String("ManagedStateMachine"), Value: aws.String("Scheduled")},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().TagResource(gomock.Any(), &scheduler.TagResourceInput{
+		ResourceArn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/retagged"),
+		Tags: []schedulertypes.Tag{
+			{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+			{Key: aws.String("ManagedStateMachine"), Value: aws.String("Scheduled")},
+			{Key: aws.String("Team"), Value: aws.String("data")},
+		},
+	}).Return(&scheduler.TagResourceOutput{}, nil).Times(1)
+	m.EXPECT().CreateScheduleGroup(gomock.Any(), &scheduler.CreateScheduleGroupInput{
+		Name: aws.String("created"),
+		Tags: []schedulertypes.Tag{
+			{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+			{Key: aws.String("ManagedStateMachine"), Value: aws.String("Scheduled")},
+		},
+	}).Return(
+		&scheduler.CreateScheduleGroupOutput{
+			ScheduleGroupArn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/created"),
+		},
+		nil,
+	).Times(1)
+
+	svc := stefunny.NewSchedulerService(m)
+	groups := stefunny.ScheduleGroups{
+		{
+			Name: "created",
+			Tags: map[string]string{
+				"ManagedBy":           "stefunny",
+				"ManagedStateMachine": "Scheduled",
+			},
+		},
+		{
+			Name: "retagged",
+			Tags: map[string]string{
+				"ManagedBy":           "stefunny",
+				"ManagedStateMachine": "Scheduled",
+				"Team":                "data",
+			},
+		},
+	}
+	err := svc.DeployScheduleGroups(context.Background(), "Scheduled", groups)
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:scheduler:us-east-1:000000000000:schedule-group/created", aws.ToString(groups[0].Arn))
+	require.Equal(t, "arn:aws:scheduler:us-east-1:000000000000:schedule-group/retagged", aws.ToString(groups[1].Arn))
+}
+
+func TestSchedulerService__SearchScheduleGroups(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSchedulerClient(ctrl)
+	defer ctrl.Finish()
+
+	groupArn := func(name string) *string {
+		return aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/" + name)
+	}
+	m.EXPECT().GetScheduleGroup(gomock.Any(), &scheduler.GetScheduleGroupInput{
+		Name: aws.String("configured"),
+	}).Return(
+		&scheduler.GetScheduleGroupOutput{
+			Name:  aws.String("configured"),
+			Arn:   groupArn("configured"),
+			State: schedulertypes.ScheduleGroupStateActive,
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().ListScheduleGroups(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&scheduler.ListScheduleGroupsOutput{
+			ScheduleGroups: []schedulertypes.ScheduleGroupSummary{
+				{Name: aws.String("default"), State: schedulertypes.ScheduleGroupStateActive},
+				{Name: aws.String("configured"), Arn: groupArn("configured"), State: schedulertypes.ScheduleGroupStateActive},
+				{Name: aws.String("owned"), Arn: groupArn("owned"), State: schedulertypes.ScheduleGroupStateActive},
+				{Name: aws.String("other"), Arn: groupArn("other"), State: schedulertypes.ScheduleGroupStateActive},
+			},
+		},
+		nil,
+	).Times(1)
+	tagsByName := map[string][]schedulertypes.Tag{
+		"configured": {},
+		"owned": {
+			{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+			{Key: aws.String("ManagedStateMachine"), Value: aws.String("Scheduled")},
+		},
+		"other": {
+			{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+			{Key: aws.String("ManagedStateMachine"), Value: aws.String("Other")},
+		},
+	}
+	for name, tags := range tagsByName {
+		m.EXPECT().ListTagsForResource(gomock.Any(), &scheduler.ListTagsForResourceInput{
+			ResourceArn: groupArn(name),
+		}).Return(&scheduler.ListTagsForResourceOutput{Tags: tags}, nil).Times(1)
+	}
+
+	svc := stefunny.NewSchedulerService(m)
+	groups, err := svc.SearchScheduleGroups(context.Background(), "Scheduled", []string{"configured"})
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"configured", "owned"}, groups.Names())
+}
+
+func TestSchedulerService__PruneScheduleGroups(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSchedulerClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().ListScheduleGroups(gomock.Any(), &scheduler.ListScheduleGroupsInput{
+		MaxResults: aws.Int32(100),
+	}, gomock.Any()).Return(
+		&scheduler.ListScheduleGroupsOutput{
+			ScheduleGroups: []schedulertypes.ScheduleGroupSummary{
+				{
+					Name:  aws.String("default"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+				{
+					Name:  aws.String("empty"),
+					Arn:   aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/empty"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+				{
+					Name:  aws.String("not-empty"),
+					Arn:   aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/not-empty"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+				{
+					Name:  aws.String("other"),
+					Arn:   aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/other"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	managedTags := &scheduler.ListTagsForResourceOutput{
+		Tags: []schedulertypes.Tag{
+			{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+			{Key: aws.String("ManagedStateMachine"), Value: aws.String("Scheduled")},
+		},
+	}
+	m.EXPECT().ListTagsForResource(gomock.Any(), &scheduler.ListTagsForResourceInput{
+		ResourceArn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/empty"),
+	}).Return(managedTags, nil).Times(1)
+	m.EXPECT().ListTagsForResource(gomock.Any(), &scheduler.ListTagsForResourceInput{
+		ResourceArn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/not-empty"),
+	}).Return(managedTags, nil).Times(1)
+	m.EXPECT().ListTagsForResource(gomock.Any(), &scheduler.ListTagsForResourceInput{
+		ResourceArn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule-group/other"),
+	}).Return(
+		&scheduler.ListTagsForResourceOutput{
+			Tags: []schedulertypes.Tag{
+				{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+				{Key: aws.String("ManagedStateMachine"), Value: aws.String("Other")},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().ListSchedules(gomock.Any(), &scheduler.ListSchedulesInput{
+		GroupName:  aws.String("empty"),
+		MaxResults: aws.Int32(1),
+	}).Return(&scheduler.ListSchedulesOutput{}, nil).Times(1)
+	m.EXPECT().ListSchedules(gomock.Any(), &scheduler.ListSchedulesInput{
+		GroupName:  aws.String("not-empty"),
+		MaxResults: aws.Int32(1),
+	}).Return(
+		&scheduler.ListSchedulesOutput{
+			Schedules: []schedulertypes.ScheduleSummary{
+				{Name: aws.String("Hoge")},
+			},
+		},
+		nil,
+	).Times(1)
+	m.EXPECT().DeleteScheduleGroup(gomock.Any(), &scheduler.DeleteScheduleGroupInput{
+		Name: aws.String("empty"),
+	}).Return(&scheduler.DeleteScheduleGroupOutput{}, nil).Times(1)
+
+	svc := stefunny.NewSchedulerService(m)
+	err := svc.PruneScheduleGroups(context.Background(), "Scheduled", stefunny.ScheduleGroups{})
+	require.NoError(t, err)
+}
+
+func TestSchedulerService__DeploySchedulesInGroup(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSchedulerClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().ListScheduleGroups(gomock.Any(), &scheduler.ListScheduleGroupsInput{
+		MaxResults: aws.Int32(100),
+	}, gomock.Any()).Return(
+		&scheduler.ListScheduleGroupsOutput{
+			ScheduleGroups: []schedulertypes.ScheduleGroupSummary{
+				{
+					Name:  aws.String("default"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+				{
+					Name:  aws.String("jobs"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	nightly := func(group string) schedulertypes.ScheduleSummary {
+		return schedulertypes.ScheduleSummary{
+			Name:      aws.String("Nightly"),
+			GroupName: aws.String(group),
+			Target: &schedulertypes.TargetSummary{
+				Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current"),
+			},
+		}
+	}
+	for _, group := range []string{"default", "jobs"} {
+		m.EXPECT().ListSchedules(gomock.Any(), &scheduler.ListSchedulesInput{
+			MaxResults: aws.Int32(100),
+			GroupName:  aws.String(group),
+		}, gomock.Any()).Return(
+			&scheduler.ListSchedulesOutput{
+				Schedules: []schedulertypes.ScheduleSummary{nightly(group)},
+			},
+			nil,
+		).Times(1)
+		m.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("Nightly"),
+			GroupName: aws.String(group),
+		}).Return(
+			&scheduler.GetScheduleOutput{
+				Name:               aws.String("Nightly"),
+				GroupName:          aws.String(group),
+				Description:        aws.String("[ManagedBy=stefunny]"),
+				ScheduleExpression: aws.String("cron(0 0 * * ? *)"),
+				State:              schedulertypes.ScheduleStateEnabled,
+				Target: &schedulertypes.Target{
+					Arn: aws.String("arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current"),
+				},
+				Arn: aws.String("arn:aws:scheduler:us-east-1:000000000000:schedule/" + group + "/Nightly"),
+			},
+			nil,
+		).Times(1)
+	}
+	// the schedule of the same name in the other group is kept.
+	m.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *scheduler.UpdateScheduleInput, opts ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
+			assert.Equal(t, "Nightly", aws.ToString(input.Name))
+			assert.Equal(t, "default", aws.ToString(input.GroupName))
+			return &scheduler.UpdateScheduleOutput{}, nil
+		}).Times(1)
+	m.EXPECT().DeleteSchedule(gomock.Any(), &scheduler.DeleteScheduleInput{
+		Name:      aws.String("Nightly"),
+		GroupName: aws.String("jobs"),
+	}).Return(
+		&scheduler.DeleteScheduleOutput{},
+		nil,
+	).Times(1)
+	svc := stefunny.NewSchedulerService(m)
+	err := svc.DeploySchedules(context.Background(), "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled:current", stefunny.Schedules{
+		{
+			CreateScheduleInput: scheduler.CreateScheduleInput{
+				Name:               aws.String("Nightly"),
+				GroupName:          aws.String("default"),
+				ScheduleExpression: aws.String("cron(0 0 * * ? *)"),
+				State:              schedulertypes.ScheduleStateEnabled,
+				Target:             &schedulertypes.Target{},
+			},
+		},
+	}, true)
+	require.NoError(t, err)
+}
+
+func TestSchedulerService__SearchRelatedSchedulesOfOtherQualifiers(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSchedulerClient(ctrl)
+	defer ctrl.Finish()
+
+	const stateMachineArn = "arn:aws:states:us-east-1:000000000000:stateMachine:Scheduled"
+	m.EXPECT().ListScheduleGroups(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&scheduler.ListScheduleGroupsOutput{
+			ScheduleGroups: []schedulertypes.ScheduleGroupSummary{
+				{
+					Name:  aws.String("default"),
+					State: schedulertypes.ScheduleGroupStateActive,
+				},
+			},
+		},
+		nil,
+	).Times(1)
+	summary := func(name string, targetArn string) schedulertypes.ScheduleSummary {
+		return schedulertypes.ScheduleSummary{
+			Name:   aws.String(name),
+			Target: &schedulertypes.TargetSummary{Arn: aws.String(targetArn)},
+		}
+	}
+	m.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&scheduler.ListSchedulesOutput{
+			Schedules: []schedulertypes.ScheduleSummary{
+				// the schedule of the qualifier removed from the config, and the schedule created by hand.
+				summary("Canary", stateMachineArn+":canary"),
+				summary("Handmade", stateMachineArn+":canary"),
+				summary("Other", stateMachineArn+"Other:canary"),
+			},
+		},
+		nil,
+	).Times(1)
+	for name, description := range map[string]string{
+		"Canary":   "[ManagedBy=stefunny]",
+		"Handmade": "created by hand",
+	} {
+		m.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String(name),
+			GroupName: aws.String("default"),
+		}).Return(
+			&scheduler.GetScheduleOutput{
+				Name:               aws.String(name),
+				GroupName:          aws.String("default"),
+				Description:        aws.String(description),
+				ScheduleExpression: aws.String("rate(1 day)"),
+				State:              schedulertypes.ScheduleStateEnabled,
+				Target: &schedulertypes.Target{
+					Arn: aws.String(stateMachineArn + ":canary"),
+				},
+			},
+			nil,
+		).Times(1)
+	}
+	svc := stefunny.NewSchedulerService(m)
+	schedules, err := svc.SearchRelatedSchedules(context.Background(), &stefunny.SearchRelatedSchedulesInput{
+		StateMachineQualifiedArn: stateMachineArn + ":current",
+	})
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.Equal(t, "Canary", aws.ToString(schedules[0].Name))
+}
@@ -0,0 +1,356 @@
+package stefunny
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a subset of JSON Schema, enough to describe the config file.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty"`
+}
+
+// schemaAnnotation is what reflection can not tell about a config key.
+type schemaAnnotation struct {
+	Description     string
+	Required        []string
+	Deprecated      bool
+	Enum            []string
+	CaseInsensitive bool // enum values are accepted in any case
+}
+
+// configSchemaAnnotations is keyed by the dotted path of the config key, array items share the path of the array.
+var configSchemaAnnotations = map[string]schemaAnnotation{
+	"": {
+		Description: "stefunny config file",
+		Required:    []string{"state_machine"},
+	},
+	"required_version": {
+		Description: "Version constraint of stefunny, e.g. >=v0.6.0",
+	},
+	"aws_region": {
+		Description: "AWS region, takes precedence over AWS_REGION of the environment",
+	},
+	"state_machine": {
+		Description: "Parameters of the Step Functions CreateStateMachine API in snake_case",
+		Required:    []string{"name", "role_arn"},
+	},
+	"state_machine.definition": {
+		Description: "Path to the state machine definition file (json, jsonnet, yaml or toml), relative to the config file",
+	},
+	"state_machine.type": {
+		Description:     "Type of the state machine, STANDARD if omitted",
+		CaseInsensitive: true,
+	},
+	"state_machine.logging_configuration.level": {
+		Description:     "Log level of the state machine, OFF if omitted",
+		CaseInsensitive: true,
+	},
+	"state_machine.logging": {
+		Description: "Logging configuration (deprecated, use logging_configuration instead)",
+		Deprecated:  true,
+	},
+	"state_machine.logging.level": {
+		Enum:            []string{"ALL", "ERROR", "FATAL", "OFF"},
+		CaseInsensitive: true,
+	},
+	"state_machine.tracing": {
+		Description: "Tracing configuration (deprecated, use tracing_configuration instead)",
+		Deprecated:  true,
+	},
+	"trigger": {
+		Description: "Triggers that start the state machine",
+	},
+	"trigger.schedule": {
+		Description: "EventBridge Scheduler schedules, parameters of the CreateSchedule API in snake_case. The target is set by stefunny",
+		Required:    []string{"schedule_expression"},
+	},
+	"trigger.schedule.qualifier": {
+		Description: "Version or alias of the state machine that the schedule invokes, the deployed alias if omitted",
+	},
+	"trigger.event": {
+		Description: "EventBridge rules, parameters of the PutRule API in snake_case. The target is set by stefunny",
+	},
+	"trigger.event.qualifier": {
+		Description: "Version or alias of the state machine that the rule invokes, the deployed alias if omitted",
+	},
+	"trigger.event.archive": {
+		Description: "Archive of the events matched by the rule, parameters of the CreateArchive API in snake_case",
+	},
+	"trigger.pipe": {
+		Description: "EventBridge Pipes, parameters of the CreatePipe API in snake_case. The target is set by stefunny",
+		Required:    []string{"source", "role_arn"},
+	},
+	"trigger.schedule_group": {
+		Description: "EventBridge Scheduler schedule groups that stefunny creates and deletes",
+		Required:    []string{"name"},
+	},
+	"schedule": {
+		Description: "Schedule rules (deprecated, use trigger.schedule instead)",
+		Deprecated:  true,
+	},
+	"tags": {
+		Description: "Tags of the state machine (deprecated, use state_machine.tags instead)",
+		Deprecated:  true,
+	},
+	"endpoints": {
+		Description: "Custom endpoints of AWS services",
+	},
+	"vars": {
+		Description: "Variables referenced by the var template function and std.extVar of Jsonnet, the type of the default is kept by --var and --var-file",
+	},
+	"jsonnet": {
+		Description: "Settings of the Jsonnet VM for Jsonnet definitions, --jpath, --ext-str and --ext-code take precedence",
+	},
+	"jsonnet.jpath": {
+		Description: "Library paths of Jsonnet relative to the config, the later has the higher priority",
+	},
+	"jsonnet.ext_str": {
+		Description: "External string variables referenced by std.extVar",
+	},
+	"jsonnet.ext_code": {
+		Description: "External code variables referenced by std.extVar, the values are Jsonnet code",
+	},
+	"tfstate": {
+		Description: "Terraform states referenced by the tfstate template functions",
+	},
+	"tfstate.func_prefix": {
+		Description: "Prefix of the template functions, e.g. prefix_tfstate",
+	},
+	"tfstate.location": {
+		Description: "URL of terraform.tfstate, e.g. s3://bucket/terraform.tfstate",
+	},
+	"cfn": {
+		Description: "CloudFormation stacks referenced by the cfn_output and cfn_export template functions",
+		Required:    []string{"stack"},
+	},
+	"cfn.func_prefix": {
+		Description: "Prefix of the template functions, e.g. prefix_cfn_output",
+	},
+	"cfn.stack": {
+		Description: "Name of the CloudFormation stack, its outputs are templateized by init and pull",
+	},
+}
+
+// keysToSnakeCaseValue is implemented by KeysToSnakeCase, the schema walks its value with snake_case keys.
+type keysToSnakeCaseValue interface {
+	valueType() reflect.Type
+}
+
+func (k KeysToSnakeCase[T]) valueType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// ConfigJSONSchema returns the JSON Schema of the config file.
+func ConfigJSONSchema() *JSONSchema {
+	g := &schemaGenerator{
+		visiting: map[reflect.Type]bool{},
+	}
+	s := g.schema(reflect.TypeFor[Config](), "", false)
+	s.Schema = jsonSchemaDraft
+	s.Title = "stefunny"
+	return s
+}
+
+// WriteConfigJSONSchema writes the JSON Schema of the config file.
+func WriteConfigJSONSchema(w io.Writer) error {
+	bs, err := json.MarshalIndent(ConfigJSONSchema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, string(bs)); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	return nil
+}
+
+type schemaGenerator struct {
+	visiting map[reflect.Type]bool
+}
+
+var (
+	timeType               = reflect.TypeFor[time.Time]()
+	keysToSnakeCaseIfaceTy = reflect.TypeFor[keysToSnakeCaseValue]()
+)
+
+// schema returns the schema of t. snake is true under KeysToSnakeCase, where the keys are json names of AWS SDK types in snake_case.
+func (g *schemaGenerator) schema(t reflect.Type, path string, snake bool) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	a := configSchemaAnnotations[path]
+	s := g.schemaOfType(t, path, snake)
+	if a.Description != "" {
+		s.Description = a.Description
+	}
+	s.Deprecated = a.Deprecated
+	if len(a.Required) > 0 {
+		s.Required = a.Required
+	}
+	if len(a.Enum) > 0 {
+		s.Enum = a.Enum
+	}
+	if a.CaseInsensitive && len(s.Enum) > 0 {
+		enum := make([]string, 0, len(s.Enum)*2)
+		for _, v := range s.Enum {
+			enum = append(enum, v, strings.ToLower(v))
+		}
+		s.Enum = enum
+	}
+	return s
+}
+
+func (g *schemaGenerator) schemaOfType(t reflect.Type, path string, snake bool) *JSONSchema {
+	if t == timeType {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string", Enum: enumValues(t)}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: g.schema(t.Elem(), path, snake)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schema(t.Elem(), path+".*", snake)}
+	case reflect.Struct:
+		if g.visiting[t] {
+			return &JSONSchema{Type: "object"}
+		}
+		g.visiting[t] = true
+		defer delete(g.visiting, t)
+		s := &JSONSchema{
+			Type:       "object",
+			Properties: map[string]*JSONSchema{},
+		}
+		g.addProperties(s, t, path, snake)
+		s.AdditionalProperties = false
+		return s
+	default:
+		// interfaces, such as smithy documents and union types, accept any value.
+		return &JSONSchema{}
+	}
+}
+
+func (g *schemaGenerator) addProperties(s *JSONSchema, t reflect.Type, path string, snake bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, inline := schemaFieldName(f, snake)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Implements(keysToSnakeCaseIfaceTy) {
+				v := reflect.Zero(ft).Interface().(keysToSnakeCaseValue)
+				g.addProperties(s, v.valueType(), path, true)
+				continue
+			}
+			g.addProperties(s, ft, path, snake)
+			continue
+		}
+		childPath := name
+		if path != "" {
+			childPath = path + "." + name
+		}
+		s.Properties[name] = g.schema(f.Type, childPath, snake)
+		if snake {
+			// EnableECSManagedTags is rendered as enable_e_c_s_managed_tags, enable_ecs_managed_tags is also accepted.
+			if alt := acronymToSnake(f.Name); alt != name && strings.EqualFold(SnakeToCamel(alt), f.Name) {
+				s.Properties[alt] = s.Properties[name]
+			}
+		}
+	}
+}
+
+var (
+	acronymBoundary    = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	lowerUpperBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// acronymToSnake converts CamelCase to snake_case keeping acronyms together, e.g. EnableECSManagedTags to enable_ecs_managed_tags.
+func acronymToSnake(s string) string {
+	s = acronymBoundary.ReplaceAllString(s, "${1}_${2}")
+	s = lowerUpperBoundary.ReplaceAllString(s, "${1}_${2}")
+	return strings.ToLower(s)
+}
+
+// schemaFieldName returns the key of the field in the config file, and whether the field is inlined into the parent.
+func schemaFieldName(f reflect.StructField, snake bool) (string, bool) {
+	if name, ok := f.Tag.Lookup("schema"); ok {
+		return name, false
+	}
+	if snake {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return "-", false
+		}
+		if name == "" {
+			if f.Anonymous {
+				return "", true
+			}
+			name = f.Name
+		}
+		return CamelToSnake(name), false
+	}
+	tag, ok := f.Tag.Lookup("yaml")
+	if !ok {
+		// AWS SDK types outside of KeysToSnakeCase, such as state_machine.tracing.
+		return schemaFieldName(f, true)
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if strings.Contains(opts, "inline") {
+		return "", true
+	}
+	if name == "" || name == "-" {
+		return "-", false
+	}
+	return name, false
+}
+
+// enumValues returns the values of AWS SDK enum types, which have the Values method.
+func enumValues(t reflect.Type) []string {
+	m, ok := t.MethodByName("Values")
+	if !ok || m.Type.NumIn() != 1 || m.Type.NumOut() != 1 || m.Type.Out(0).Kind() != reflect.Slice {
+		return nil
+	}
+	out := m.Func.Call([]reflect.Value{reflect.Zero(t)})[0]
+	values := make([]string, 0, out.Len())
+	for i := 0; i < out.Len(); i++ {
+		values = append(values, out.Index(i).String())
+	}
+	sort.Strings(values)
+	return values
+}
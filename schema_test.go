@@ -0,0 +1,87 @@
+package stefunny_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mashiike/stefunny"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigJSONSchema(t *testing.T) {
+	s := stefunny.ConfigJSONSchema()
+	require.Equal(t, []string{"state_machine"}, s.Required)
+	sm := s.Properties["state_machine"]
+	require.Contains(t, sm.Properties, "role_arn")
+	require.Contains(t, sm.Properties["type"].Enum, "STANDARD")
+	require.Contains(t, sm.Properties["logging_configuration"].Properties["level"].Enum, "ERROR")
+	schedule := s.Properties["trigger"].Properties["schedule"].Items
+	require.Contains(t, schedule.Properties, "schedule_expression_timezone")
+	require.Contains(t, schedule.Properties, "qualifier")
+	require.Equal(t, false, schedule.AdditionalProperties)
+	require.True(t, s.Properties["schedule"].Deprecated)
+
+	g := goldie.New(
+		t,
+		goldie.WithFixtureDir("testdata/schema"),
+		goldie.WithNameSuffix(".golden.json"),
+	)
+	g.AssertJson(t, "config", s)
+}
+
+func TestConfigJSONSchema__LoadedConfigs(t *testing.T) {
+	s := stefunny.ConfigJSONSchema()
+	paths, err := filepath.Glob("testdata/config/*.golden.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			bs, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var v any
+			require.NoError(t, json.Unmarshal(bs, &v))
+			require.NoError(t, checkJSONSchemaKeys(s, v, ""))
+		})
+	}
+}
+
+// checkJSONSchemaKeys checks only keys and enums, which are what the schema is for.
+func checkJSONSchemaKeys(s *stefunny.JSONSchema, v any, path string) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			child, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties == false {
+					return fmt.Errorf("%s.%s is not in schema", path, key)
+				}
+				child, ok = s.AdditionalProperties.(*stefunny.JSONSchema)
+				if !ok {
+					continue
+				}
+			}
+			if err := checkJSONSchemaKeys(child, value, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items == nil {
+			return fmt.Errorf("%s is not array in schema", path)
+		}
+		for i, item := range v {
+			if err := checkJSONSchemaKeys(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+			return fmt.Errorf("%s = %q is not in %v", path, v, s.Enum)
+		}
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 var (
 	ErrStateMachineDoesNotExist = errors.New("state machine does not exist")
 	ErrRollbackTargetNotFound   = errors.New("rollback target not found")
+	ErrExecutionNotTriggered    = errors.New("execution is not triggered")
 )
 
 //go:generate go tool mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
@@ -42,6 +43,7 @@ type SFnClient interface {
 	StartSyncExecution(ctx context.Context, params *sfn.StartSyncExecutionInput, optFns ...func(*sfn.Options)) (*sfn.StartSyncExecutionOutput, error)
 	DescribeExecution(ctx context.Context, params *sfn.DescribeExecutionInput, optFns ...func(*sfn.Options)) (*sfn.DescribeExecutionOutput, error)
 	StopExecution(ctx context.Context, params *sfn.StopExecutionInput, optFns ...func(*sfn.Options)) (*sfn.StopExecutionOutput, error)
+	ListExecutions(ctx context.Context, params *sfn.ListExecutionsInput, optFns ...func(*sfn.Options)) (*sfn.ListExecutionsOutput, error)
 	GetExecutionHistory(ctx context.Context, params *sfn.GetExecutionHistoryInput, optFns ...func(*sfn.Options)) (*sfn.GetExecutionHistoryOutput, error)
 	TagResource(ctx context.Context, params *sfn.TagResourceInput, optFns ...func(*sfn.Options)) (*sfn.TagResourceOutput, error)
 }
@@ -56,6 +58,7 @@ type SFnService interface {
 	PurgeStateMachineVersions(ctx context.Context, stateMachine *StateMachine, keepVersions int) error
 	StartExecution(ctx context.Context, stateMachine *StateMachine, params *StartExecutionInput) (*StartExecutionOutput, error)
 	GetExecutionHistory(ctx context.Context, executionArn string) ([]HistoryEvent, error)
+	WaitTriggeredExecution(ctx context.Context, params *WaitTriggeredExecutionInput) (*StartExecutionOutput, error)
 	SetAliasName(aliasName string)
 }
 
@@ -782,6 +785,85 @@ func (svc *SFnServiceImpl) startExecution(ctx context.Context, stateMachine *Sta
 	}, nil
 }
 
+type WaitTriggeredExecutionInput struct {
+	StateMachineArn string
+	After           time.Time
+	Timeout         time.Duration
+}
+
+// WaitTriggeredExecution finds the execution started after the time, such as by a trigger, and waits until it ends.
+// it returns ErrExecutionNotTriggered if no execution is started within the timeout.
+func (svc *SFnServiceImpl) WaitTriggeredExecution(ctx context.Context, params *WaitTriggeredExecutionInput) (*StartExecutionOutput, error) {
+	findCtx, cancel := context.WithTimeout(ctx, params.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	var found *sfntypes.ExecutionListItem
+	for found == nil {
+		var err error
+		found, err = svc.findExecutionStartedAfter(findCtx, params.StateMachineArn, params.After)
+		if err != nil {
+			if findCtx.Err() != nil && ctx.Err() == nil {
+				return nil, ErrExecutionNotTriggered
+			}
+			return nil, fmt.Errorf("failed to list executions: %w", err)
+		}
+		if found != nil {
+			break
+		}
+		log.Printf("[debug] no execution started after %s", params.After.Format(time.RFC3339))
+		select {
+		case <-findCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, ErrExecutionNotTriggered
+		case <-ticker.C:
+		}
+	}
+	executionArn := coalesce(found.ExecutionArn)
+	log.Printf("[notice] execution arn=%s", executionArn)
+	log.Printf("[notice] state at=%s", coalesce(found.StartDate).In(time.Local))
+	output := &StartExecutionOutput{
+		ExecutionArn: executionArn,
+		StartDate:    coalesce(found.StartDate),
+	}
+	waitOutput, err := svc.waitExecution(ctx, executionArn)
+	if err != nil {
+		return output, err
+	}
+	output.Success = &waitOutput.Success
+	output.Failed = &waitOutput.Failed
+	output.StopDate = &waitOutput.StopDate
+	output.Output = &waitOutput.Output
+	output.Datail = waitOutput.Datail
+	return output, nil
+}
+
+// findExecutionStartedAfter returns the first execution started after the time, nil if no execution is started.
+// executions are listed in reverse chronological order, so the pages are read until the execution started before the time.
+func (svc *SFnServiceImpl) findExecutionStartedAfter(ctx context.Context, stateMachineArn string, after time.Time) (*sfntypes.ExecutionListItem, error) {
+	var found *sfntypes.ExecutionListItem
+	p := sfn.NewListExecutionsPaginator(svc.client, &sfn.ListExecutionsInput{
+		StateMachineArn: aws.String(stateMachineArn),
+		MaxResults:      100,
+	})
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range output.Executions {
+			execution := output.Executions[i]
+			if coalesce(execution.StartDate).Before(after) {
+				return found, nil
+			}
+			found = &execution
+		}
+	}
+	return found, nil
+}
+
 type waitExecutionOutput struct {
 	Success   bool
 	Failed    bool
@@ -2013,3 +2013,130 @@ func TestSFnService_ListStateMachineVersions_Success(t *testing.T) {
 		},
 	}, versions)
 }
+
+func TestSFnService__WaitTriggeredExecution(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSFnClient(ctrl)
+	defer ctrl.Finish()
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.EXPECT().ListExecutions(gomock.Any(), &sfn.ListExecutionsInput{
+		StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:Hello"),
+		MaxResults:      100,
+	}, gomock.Any()).Return(&sfn.ListExecutionsOutput{
+		Executions: []sfntypes.ExecutionListItem{
+			{
+				ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:second"),
+				StartDate:    aws.Time(after.Add(2 * time.Second)),
+			},
+			{
+				ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:first"),
+				StartDate:    aws.Time(after.Add(time.Second)),
+			},
+			{
+				ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:before"),
+				StartDate:    aws.Time(after.Add(-time.Second)),
+			},
+		},
+	}, nil).Times(1)
+	m.EXPECT().DescribeExecution(gomock.Any(), &sfn.DescribeExecutionInput{
+		ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:first"),
+	}).Return(&sfn.DescribeExecutionOutput{
+		Status:    sfntypes.ExecutionStatusSucceeded,
+		StartDate: aws.Time(after.Add(time.Second)),
+		StopDate:  aws.Time(after.Add(3 * time.Second)),
+		Output:    aws.String(`{}`),
+	}, nil).Times(1)
+	m.EXPECT().GetExecutionHistory(gomock.Any(), gomock.Any()).Return(&sfn.GetExecutionHistoryOutput{}, nil).Times(1)
+
+	svc := stefunny.NewSFnService(m)
+	output, err := svc.WaitTriggeredExecution(context.Background(), &stefunny.WaitTriggeredExecutionInput{
+		StateMachineArn: "arn:aws:states:us-east-1:123456789012:stateMachine:Hello",
+		After:           after,
+		Timeout:         time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:states:us-east-1:123456789012:execution:Hello:first", output.ExecutionArn)
+	require.True(t, *output.Success)
+}
+
+func TestSFnService__WaitTriggeredExecution__Paginated(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSFnClient(ctrl)
+	defer ctrl.Finish()
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gomock.InOrder(
+		m.EXPECT().ListExecutions(gomock.Any(), &sfn.ListExecutionsInput{
+			StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:Hello"),
+			MaxResults:      100,
+		}, gomock.Any()).Return(&sfn.ListExecutionsOutput{
+			Executions: []sfntypes.ExecutionListItem{
+				{
+					ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:third"),
+					StartDate:    aws.Time(after.Add(3 * time.Second)),
+				},
+				{
+					ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:second"),
+					StartDate:    aws.Time(after.Add(2 * time.Second)),
+				},
+			},
+			NextToken: aws.String("next"),
+		}, nil).Times(1),
+		m.EXPECT().ListExecutions(gomock.Any(), &sfn.ListExecutionsInput{
+			StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:Hello"),
+			MaxResults:      100,
+			NextToken:       aws.String("next"),
+		}, gomock.Any()).Return(&sfn.ListExecutionsOutput{
+			Executions: []sfntypes.ExecutionListItem{
+				{
+					ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:first"),
+					StartDate:    aws.Time(after.Add(time.Second)),
+				},
+				{
+					ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:before"),
+					StartDate:    aws.Time(after.Add(-time.Second)),
+				},
+			},
+			NextToken: aws.String("more"),
+		}, nil).Times(1),
+	)
+	m.EXPECT().DescribeExecution(gomock.Any(), &sfn.DescribeExecutionInput{
+		ExecutionArn: aws.String("arn:aws:states:us-east-1:123456789012:execution:Hello:first"),
+	}).Return(&sfn.DescribeExecutionOutput{
+		Status:    sfntypes.ExecutionStatusSucceeded,
+		StartDate: aws.Time(after.Add(time.Second)),
+		StopDate:  aws.Time(after.Add(3 * time.Second)),
+		Output:    aws.String(`{}`),
+	}, nil).Times(1)
+	m.EXPECT().GetExecutionHistory(gomock.Any(), gomock.Any()).Return(&sfn.GetExecutionHistoryOutput{}, nil).Times(1)
+
+	svc := stefunny.NewSFnService(m)
+	output, err := svc.WaitTriggeredExecution(context.Background(), &stefunny.WaitTriggeredExecutionInput{
+		StateMachineArn: "arn:aws:states:us-east-1:123456789012:stateMachine:Hello",
+		After:           after,
+		Timeout:         time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:states:us-east-1:123456789012:execution:Hello:first", output.ExecutionArn)
+	require.True(t, *output.Success)
+}
+
+func TestSFnService__WaitTriggeredExecution__NotTriggered(t *testing.T) {
+	LoggerSetup(t, "debug")
+	ctrl := gomock.NewController(t)
+	m := mock.NewMockSFnClient(ctrl)
+	defer ctrl.Finish()
+
+	m.EXPECT().ListExecutions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sfn.ListExecutionsOutput{}, nil).MinTimes(1)
+
+	svc := stefunny.NewSFnService(m)
+	_, err := svc.WaitTriggeredExecution(context.Background(), &stefunny.WaitTriggeredExecutionInput{
+		StateMachineArn: "arn:aws:states:us-east-1:123456789012:stateMachine:Hello",
+		After:           time.Now(),
+		Timeout:         100 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, stefunny.ErrExecutionNotTriggered)
+}
@@ -12,8 +12,9 @@ import (
 )
 
 type StatusOption struct {
-	Format string `name:"format" help:"output format(text,json)" default:"text" enum:"text,json" json:"format,omitempty"`
-	Latest bool   `name:"latest" help:"show latest state machine" default:"false" json:"latest,omitempty"`
+	Format   string `name:"format" help:"output format(text,json)" default:"text" enum:"text,json" json:"format,omitempty"`
+	Latest   bool   `name:"latest" help:"show latest state machine" default:"false" json:"latest,omitempty"`
+	NextRuns int    `name:"next-runs" help:"show upcoming fire times of schedules and scheduled rules" default:"0" json:"next_runs,omitempty"`
 }
 
 func (app *App) Status(ctx context.Context, opt StatusOption) error {
@@ -28,18 +29,24 @@ func (app *App) Status(ctx context.Context, opt StatusOption) error {
 	if strings.HasPrefix(stateMachineStatus.Arn, knownAfterDeployArn) {
 		return nil
 	}
-	rulesStatus, err := app.newRuleStatus(ctx, stateMachineStatus.Arn)
+	rulesStatus, err := app.newRuleStatus(ctx, stateMachineStatus.Arn, opt.NextRuns)
 	if err != nil {
 		return fmt.Errorf("failed to get rule status: %w", err)
 	}
-	scheduleStatus, err := app.newScheduleStatus(ctx, stateMachineStatus.Arn)
+	scheduleStatus, scheduledExecutionStatus, err := app.newScheduleStatus(ctx, stateMachineStatus.Arn, opt.NextRuns)
 	if err != nil {
 		return fmt.Errorf("failed to get schedule status: %w", err)
 	}
+	pipesStatus, err := app.newPipeStatus(ctx, stateMachineStatus.Arn)
+	if err != nil {
+		return fmt.Errorf("failed to get pipe status: %w", err)
+	}
 	status := &StatusOutput{
 		StateMachine:         stateMachineStatus,
 		EventBridge:          rulesStatus,
 		EventBridgeScheduler: scheduleStatus,
+		ScheduledExecutions:  scheduledExecutionStatus,
+		EventBridgePipes:     pipesStatus,
 	}
 	switch opt.Format {
 	case "json":
@@ -57,6 +64,7 @@ func (app *App) Status(ctx context.Context, opt StatusOption) error {
 
 const (
 	notDeploayedStatus  = "NOT DEPLOYED"
+	latestQualifier     = "$LATEST"
 	knownAfterDeployArn = "[known after deploy]"
 )
 
@@ -103,12 +111,13 @@ func (app *App) newStateMachineStatus(ctx context.Context, qualifier string) (*S
 	}, nil
 }
 
-func (app *App) newRuleStatus(ctx context.Context, stateMachineArn string) ([]*RulesStatus, error) {
+func (app *App) newRuleStatus(ctx context.Context, stateMachineArn string, nextRuns int) ([]*RulesStatus, error) {
 	cfgRules := app.cfg.NewEventBridgeRules()
 	stateMachineQualifiedArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
 	rules, err := app.eventbridgeSvc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
 		StateMachineQualifiedArn: stateMachineQualifiedArn,
 		RuleNames:                cfgRules.Names(),
+		Qualifiers:               cfgRules.Qualifiers(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search related rules: %w", err)
@@ -128,9 +137,15 @@ func (app *App) newRuleStatus(ctx context.Context, stateMachineArn string) ([]*R
 		}
 		targetQuarifier := strings.TrimPrefix(coalesce(rule.Target.Arn), stateMachineArn)
 		if targetQuarifier == "" {
-			targetQuarifier = "$LATEST"
+			targetQuarifier = latestQualifier
 		}
 		status.Target = strings.TrimLeft(targetQuarifier, ":")
+		if cfgRule, ok := cfgRules.FindByName(coalesce(rule.Name)); ok {
+			if expected := app.configTriggerQualifier(cfgRule.Qualifier); expected != status.Target {
+				status.ConfigTarget = expected
+			}
+		}
+		status.NextRuns = nextRunsStrings(rule, nextRuns)
 		rulesStatus = append(rulesStatus, status)
 	}
 	for _, cfgRule := range cfgRules {
@@ -143,36 +158,34 @@ func (app *App) newRuleStatus(ctx context.Context, stateMachineArn string) ([]*R
 			Status:             "NOT DEPLOYED",
 			EventPattern:       cfgRule.EventPattern,
 			ScheduleExpression: cfgRule.ScheduleExpression,
+			NextRuns:           nextRunsStrings(cfgRule, nextRuns),
 		}
 		rulesStatus = append(rulesStatus, status)
 	}
 	return rulesStatus, nil
 }
 
-func (app *App) newScheduleStatus(ctx context.Context, stateMachineArn string) ([]*ScheduleStatus, error) {
+// newScheduleStatus returns the status of schedules and one-off executions scheduled by `execute --at`.
+func (app *App) newScheduleStatus(ctx context.Context, stateMachineArn string, nextRuns int) ([]*ScheduleStatus, []*ScheduleStatus, error) {
 	cfgSchedules := app.cfg.NewSchedules()
 	stateMachineQualifiedArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
 	schedules, err := app.schedulerSvc.SearchRelatedSchedules(ctx, &SearchRelatedSchedulesInput{
 		StateMachineQualifiedArn: stateMachineQualifiedArn,
-		ScheduleNames:            cfgSchedules.Names(),
+		ScheduleNames:            cfgSchedules.keys(),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list schedules: %w", err)
+		return nil, nil, fmt.Errorf("failed to list schedules: %w", err)
 	}
+	schedules, oneOffs := schedules.FilterOneOff()
 	schedulesStatus := make([]*ScheduleStatus, 0, len(schedules))
 	for _, schedule := range schedules {
-		status := &ScheduleStatus{
-			ScheduleName:               coalesce(schedule.Name),
-			ScheduleArn:                coalesce(schedule.ScheduleArn),
-			Status:                     string(schedule.State),
-			ScheduleExpression:         coalesce(schedule.ScheduleExpression),
-			ScheduleExpressionTimezone: coalesce(schedule.ScheduleExpressionTimezone),
-		}
-		targetQuarifier := strings.TrimPrefix(coalesce(schedule.Target.Arn), stateMachineArn)
-		if targetQuarifier == "" {
-			targetQuarifier = "$LATEST"
+		status := newDeployedScheduleStatus(schedule, stateMachineArn)
+		if cfgSchedule, ok := cfgSchedules.FindByName(coalesce(schedule.Name)); ok {
+			if expected := app.configTriggerQualifier(cfgSchedule.Qualifier); expected != status.Target {
+				status.ConfigTarget = expected
+			}
 		}
-		status.Target = strings.TrimLeft(targetQuarifier, ":")
+		status.NextRuns = nextRunsStrings(schedule, nextRuns)
 		schedulesStatus = append(schedulesStatus, status)
 	}
 	for _, cfgSchedule := range cfgSchedules {
@@ -185,16 +198,109 @@ func (app *App) newScheduleStatus(ctx context.Context, stateMachineArn string) (
 			Status:                     "NOT DEPLOYED",
 			ScheduleExpression:         coalesce(cfgSchedule.ScheduleExpression),
 			ScheduleExpressionTimezone: coalesce(cfgSchedule.ScheduleExpressionTimezone),
+			NextRuns:                   nextRunsStrings(cfgSchedule, nextRuns),
 		}
 		schedulesStatus = append(schedulesStatus, status)
 	}
-	return schedulesStatus, nil
+	scheduledExecutionsStatus := make([]*ScheduleStatus, 0, len(oneOffs))
+	for _, schedule := range oneOffs {
+		status := newDeployedScheduleStatus(schedule, stateMachineArn)
+		status.NextRuns = nextRunsStrings(schedule, 1)
+		scheduledExecutionsStatus = append(scheduledExecutionsStatus, status)
+	}
+	return schedulesStatus, scheduledExecutionsStatus, nil
+}
+
+func newDeployedScheduleStatus(schedule *Schedule, stateMachineArn string) *ScheduleStatus {
+	status := &ScheduleStatus{
+		ScheduleName:               coalesce(schedule.Name),
+		ScheduleArn:                coalesce(schedule.ScheduleArn),
+		Status:                     string(schedule.State),
+		ScheduleExpression:         coalesce(schedule.ScheduleExpression),
+		ScheduleExpressionTimezone: coalesce(schedule.ScheduleExpressionTimezone),
+	}
+	targetQuarifier := strings.TrimPrefix(coalesce(schedule.Target.Arn), stateMachineArn)
+	if targetQuarifier == "" {
+		targetQuarifier = latestQualifier
+	}
+	status.Target = strings.TrimLeft(targetQuarifier, ":")
+	return status
+}
+
+type nextRunner interface {
+	NextRuns(from time.Time, n int) ([]ScheduledRun, error)
+}
+
+func nextRunsStrings(r nextRunner, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	runs, err := r.NextRuns(time.Now(), n)
+	if err != nil {
+		log.Printf("[warn] failed to evaluate schedule expression: %s", err)
+		return nil
+	}
+	result := make([]string, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, run.String())
+	}
+	return result
+}
+
+// configTriggerQualifier returns the qualifier that the trigger should target according to the config.
+func (app *App) configTriggerQualifier(qualifier *string) string {
+	if q := coalesce(qualifier); q != "" {
+		return q
+	}
+	return app.StateMachineAliasName()
+}
+
+func (app *App) newPipeStatus(ctx context.Context, stateMachineArn string) ([]*PipeStatus, error) {
+	cfgPipes := app.cfg.NewPipes()
+	stateMachineQualifiedArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
+	pipes, err := app.pipesSvc.SearchRelatedPipes(ctx, &SearchRelatedPipesInput{
+		StateMachineQualifiedArn: stateMachineQualifiedArn,
+		PipeNames:                cfgPipes.Names(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search related pipes: %w", err)
+	}
+	pipesStatus := make([]*PipeStatus, 0, len(pipes))
+	for _, p := range pipes {
+		status := &PipeStatus{
+			PipeName: coalesce(p.Name),
+			PipeArn:  coalesce(p.PipeArn),
+			Status:   string(p.DesiredState),
+			Source:   coalesce(p.Source),
+		}
+		targetQuarifier := strings.TrimPrefix(coalesce(p.Target), stateMachineArn)
+		if targetQuarifier == "" {
+			targetQuarifier = latestQualifier
+		}
+		status.Target = strings.TrimLeft(targetQuarifier, ":")
+		pipesStatus = append(pipesStatus, status)
+	}
+	for _, cfgPipe := range cfgPipes {
+		_, ok := pipes.FindByName(coalesce(cfgPipe.Name))
+		if ok {
+			continue
+		}
+		status := &PipeStatus{
+			PipeName: coalesce(cfgPipe.Name),
+			Status:   "NOT DEPLOYED",
+			Source:   coalesce(cfgPipe.Source),
+		}
+		pipesStatus = append(pipesStatus, status)
+	}
+	return pipesStatus, nil
 }
 
 type StatusOutput struct {
 	StateMachine         *StateMachineStatus `json:"state_machine"`
 	EventBridge          []*RulesStatus      `json:"event_bridge,omitempty"`
 	EventBridgeScheduler []*ScheduleStatus   `json:"event_bridge_scheduler,omitempty"`
+	ScheduledExecutions  []*ScheduleStatus   `json:"scheduled_executions,omitempty"`
+	EventBridgePipes     []*PipeStatus       `json:"event_bridge_pipes,omitempty"`
 }
 
 func (s *StatusOutput) String() string {
@@ -216,6 +322,20 @@ func (s *StatusOutput) String() string {
 			fmt.Fprintln(&builder)
 		}
 	}
+	if len(s.ScheduledExecutions) > 0 {
+		fmt.Fprintln(&builder, "[Scheduled Executions]")
+		for _, schedule := range s.ScheduledExecutions {
+			fmt.Fprintln(&builder, schedule)
+			fmt.Fprintln(&builder)
+		}
+	}
+	if len(s.EventBridgePipes) > 0 {
+		fmt.Fprintln(&builder, "[EventBridge Pipes]")
+		for _, p := range s.EventBridgePipes {
+			fmt.Fprintln(&builder, p)
+			fmt.Fprintln(&builder)
+		}
+	}
 	return builder.String()
 }
 
@@ -238,12 +358,14 @@ func (s *StateMachineStatus) String() string {
 }
 
 type RulesStatus struct {
-	RuleArn            string  `json:"rule_arn,omitempty"`
-	RuleName           string  `json:"rule_name"`
-	Status             string  `json:"status"`
-	ScheduleExpression *string `json:"schedule_expression,omitempty"`
-	EventPattern       *string `json:"event_pattern,omitempty"`
-	Target             string  `json:"target,omitempty"`
+	RuleArn            string   `json:"rule_arn,omitempty"`
+	RuleName           string   `json:"rule_name"`
+	Status             string   `json:"status"`
+	ScheduleExpression *string  `json:"schedule_expression,omitempty"`
+	EventPattern       *string  `json:"event_pattern,omitempty"`
+	Target             string   `json:"target,omitempty"`
+	ConfigTarget       string   `json:"config_target,omitempty"`
+	NextRuns           []string `json:"next_runs,omitempty"`
 }
 
 func (r *RulesStatus) String() string {
@@ -264,18 +386,21 @@ func (r *RulesStatus) String() string {
 		fmt.Fprintf(&builder, "  EventPattern: %s\n", str)
 	}
 	if r.Target != "" {
-		fmt.Fprintf(&builder, "  Target: %s\n", r.Target)
+		fmt.Fprintf(&builder, "  Target: %s\n", targetWithConfig(r.Target, r.ConfigTarget))
 	}
+	writeNextRuns(&builder, r.NextRuns)
 	return builder.String()
 }
 
 type ScheduleStatus struct {
-	ScheduleName               string `json:"schedule_name"`
-	ScheduleArn                string `json:"schedule_arn,omitempty"`
-	Status                     string `json:"status"`
-	ScheduleExpression         string `json:"schedule_expression"`
-	ScheduleExpressionTimezone string `json:"schedule_expression_timezone"`
-	Target                     string `json:"target,omitempty"`
+	ScheduleName               string   `json:"schedule_name"`
+	ScheduleArn                string   `json:"schedule_arn,omitempty"`
+	Status                     string   `json:"status"`
+	ScheduleExpression         string   `json:"schedule_expression"`
+	ScheduleExpressionTimezone string   `json:"schedule_expression_timezone"`
+	Target                     string   `json:"target,omitempty"`
+	ConfigTarget               string   `json:"config_target,omitempty"`
+	NextRuns                   []string `json:"next_runs,omitempty"`
 }
 
 func (s *ScheduleStatus) String() string {
@@ -290,7 +415,47 @@ func (s *ScheduleStatus) String() string {
 		fmt.Fprintf(&builder, "  ScheduleExpressionTimezone: %s\n", s.ScheduleExpressionTimezone)
 	}
 	if s.Target != "" {
-		fmt.Fprintf(&builder, "  Target: %s\n", s.Target)
+		fmt.Fprintf(&builder, "  Target: %s\n", targetWithConfig(s.Target, s.ConfigTarget))
+	}
+	writeNextRuns(&builder, s.NextRuns)
+	return builder.String()
+}
+
+func writeNextRuns(builder *strings.Builder, nextRuns []string) {
+	if len(nextRuns) == 0 {
+		return
+	}
+	builder.WriteString("  NextRuns:\n")
+	for _, run := range nextRuns {
+		fmt.Fprintf(builder, "    - %s\n", run)
+	}
+}
+
+func targetWithConfig(target, configTarget string) string {
+	if configTarget == "" || configTarget == target {
+		return target
+	}
+	return fmt.Sprintf("%s (config: %s, mismatch)", target, configTarget)
+}
+
+type PipeStatus struct {
+	PipeName string `json:"pipe_name"`
+	PipeArn  string `json:"pipe_arn,omitempty"`
+	Status   string `json:"status"`
+	Source   string `json:"source"`
+	Target   string `json:"target,omitempty"`
+}
+
+func (p *PipeStatus) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "- Name: %s\n", p.PipeName)
+	fmt.Fprintf(&builder, "  Status: %s\n", p.Status)
+	if p.PipeArn != "" {
+		fmt.Fprintf(&builder, "  PipeArn: %s\n", p.PipeArn)
+	}
+	fmt.Fprintf(&builder, "  Source: %s\n", p.Source)
+	if p.Target != "" {
+		fmt.Fprintf(&builder, "  Target: %s\n", p.Target)
 	}
 	return builder.String()
 }
@@ -0,0 +1,130 @@
+// stefunny.libsonnet is the helper library for Amazon States Language, embedded in stefunny.
+//
+//   local stefunny = import 'stefunny.libsonnet';
+//
+//   {
+//     Comment: 'Hello',
+//   } + stefunny.chain([
+//     { Hello: stefunny.lambdaInvoke('hello') },
+//     { World: stefunny.pass('World') },
+//   ])
+{
+  // lambdaRetry retries the transient errors of Lambda, the same as the default of the console.
+  lambdaRetry:: [
+    {
+      ErrorEquals: [
+        'Lambda.ServiceException',
+        'Lambda.AWSLambdaException',
+        'Lambda.SdkClientException',
+        'Lambda.TooManyRequestsException',
+      ],
+      IntervalSeconds: 1,
+      MaxAttempts: 3,
+      BackoffRate: 2,
+    },
+  ],
+
+  // terminalTypes are the types of states that chain does not add Next or End to.
+  terminalTypes:: ['Succeed', 'Fail', 'Choice'],
+
+  pass(result=null):: {
+    Type: 'Pass',
+    [if result != null then 'Result']: result,
+  },
+
+  wait(seconds):: {
+    Type: 'Wait',
+    Seconds: seconds,
+  },
+
+  succeed():: {
+    Type: 'Succeed',
+  },
+
+  fail(errorName=null, cause=null):: {
+    Type: 'Fail',
+    [if errorName != null then 'Error']: errorName,
+    [if cause != null then 'Cause']: cause,
+  },
+
+  // lambdaInvoke invokes the function with the state input as the payload, unless the payload is given.
+  lambdaInvoke(functionName, payload=null, retry=$.lambdaRetry):: {
+    Type: 'Task',
+    Resource: 'arn:aws:states:::lambda:invoke',
+    Parameters: {
+      FunctionName: functionName,
+    } + (if payload == null then { 'Payload.$': '$' } else { Payload: payload }),
+    [if retry != null && std.length(retry) > 0 then 'Retry']: retry,
+  },
+
+  // ecsRunTask runs the task and waits for it to stop.
+  ecsRunTask(cluster, taskDefinition, launchType='FARGATE', networkConfiguration=null, overrides=null):: {
+    Type: 'Task',
+    Resource: 'arn:aws:states:::ecs:runTask.sync',
+    Parameters: {
+      LaunchType: launchType,
+      Cluster: cluster,
+      TaskDefinition: taskDefinition,
+      [if networkConfiguration != null then 'NetworkConfiguration']: networkConfiguration,
+      [if overrides != null then 'Overrides']: overrides,
+    },
+  },
+
+  // sqsSendMessage sends the state input as the message, unless the message body is given.
+  sqsSendMessage(queueUrl, messageBody=null):: {
+    Type: 'Task',
+    Resource: 'arn:aws:states:::sqs:sendMessage',
+    Parameters: {
+      QueueUrl: queueUrl,
+    } + (if messageBody == null then { 'MessageBody.$': '$' } else { MessageBody: messageBody }),
+  },
+
+  // startExecution starts the nested state machine and waits for it to complete, the execution is associated with the parent.
+  startExecution(stateMachineArn, input=null):: {
+    Type: 'Task',
+    Resource: 'arn:aws:states:::states:startExecution.sync:2',
+    Parameters: {
+      StateMachineArn: stateMachineArn,
+      Input: {
+        'AWS_STEP_FUNCTIONS_STARTED_BY_EXECUTION_ID.$': '$$.Execution.Id',
+      } + (if input == null then {} else input),
+    },
+  },
+
+  // map runs the processor, such as chain([...]), for each item inline.
+  map(processor, itemsPath=null, maxConcurrency=0):: {
+    Type: 'Map',
+    ItemProcessor: {
+      ProcessorConfig: {
+        Mode: 'INLINE',
+      },
+    } + processor,
+    [if itemsPath != null then 'ItemsPath']: itemsPath,
+    MaxConcurrency: maxConcurrency,
+  },
+
+  // chain returns StartAt and States of the steps, such as [{ Hello: pass() }, { World: pass() }].
+  // Next is wired to the following step and End to the last, except for terminal states and states that have them.
+  chain(steps)::
+    local named = [
+      local fields = std.objectFields(step);
+      assert std.length(fields) == 1 : 'chain step must be an object with a state name, such as { Hello: stefunny.pass() }';
+      { name: fields[0], state: step[fields[0]] }
+      for step in steps
+    ];
+    local last = std.length(named) - 1;
+    local wire(i) =
+      local state = named[i].state;
+      local type = if std.objectHas(state, 'Type') then state.Type else '';
+      if std.member($.terminalTypes, type) || std.objectHas(state, 'Next') || std.objectHas(state, 'End') then {}
+      else if i == last then { End: true }
+      else { Next: named[i + 1].name };
+    assert last >= 0 : 'chain requires at least one step';
+    {
+      StartAt: named[0].name,
+      States: {
+        [named[i].name]: named[i].state + wire(i)
+        for i in std.range(0, last)
+      },
+    },
+}
@@ -0,0 +1,5 @@
+{
+  pass(next=null):: {
+    Type: 'Pass',
+  } + (if next == null then { End: true } else { Next: next }),
+}
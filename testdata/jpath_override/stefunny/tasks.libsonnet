@@ -0,0 +1,6 @@
+{
+  pass(next=null):: {
+    Type: 'Pass',
+    Comment: 'override',
+  } + (if next == null then { End: true } else { Next: next }),
+}
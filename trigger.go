@@ -0,0 +1,365 @@
+package stefunny
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+)
+
+type TriggerOption struct {
+	TestPattern TriggerTestPatternOption `cmd:"" name:"test-pattern" help:"Test event pattern of the rule trigger against a sample event" json:"test_pattern,omitempty"`
+	Enable      TriggerStateOption       `cmd:"" help:"Enable rules and schedules by name" json:"enable,omitempty"`
+	Disable     TriggerStateOption       `cmd:"" help:"Disable rules and schedules by name" json:"disable,omitempty"`
+	Replay      TriggerReplayOption      `cmd:"" help:"Replay archived events to the rule trigger" json:"replay,omitempty"`
+	SendEvent   TriggerSendEventOption   `cmd:"" name:"send-event" help:"Put a test event to the event bus of the rule trigger and wait for the execution" json:"send_event,omitempty"`
+}
+
+type TriggerStateOption struct {
+	Names  []string `arg:"" name:"name" optional:"" help:"rule or schedule names" json:"names,omitempty"`
+	All    bool     `name:"all" help:"all rules and schedules related to the state machine" json:"all,omitempty"`
+	DryRun bool     `name:"dry-run" help:"Dry run" json:"dry_run,omitempty"`
+}
+
+func (opt TriggerStateOption) DryRunString() string {
+	if opt.DryRun {
+		return dryRunStr
+	}
+	return ""
+}
+
+type TriggerTestPatternOption struct {
+	Rule   string `name:"rule" help:"rule name of trigger.event" required:"" json:"rule,omitempty"`
+	Event  string `name:"event" help:"path to sample event JSON file" required:"" type:"existingfile" json:"event,omitempty"`
+	Remote bool   `name:"remote" help:"cross-check with EventBridge TestEventPattern API" default:"false" json:"remote,omitempty"`
+}
+
+func (app *App) TriggerTestPattern(ctx context.Context, opt TriggerTestPatternOption) error {
+	rule, ok := app.cfg.NewEventBridgeRules().FindByName(opt.Rule)
+	if !ok {
+		return fmt.Errorf("rule `%s` not found in config", opt.Rule)
+	}
+	if coalesce(rule.EventPattern) == "" {
+		return fmt.Errorf("rule `%s` has no event_pattern", opt.Rule)
+	}
+	event, err := os.ReadFile(opt.Event)
+	if err != nil {
+		return fmt.Errorf("failed to read event file: %w", err)
+	}
+	matched, err := MatchEventPattern(*rule.EventPattern, event)
+	if err != nil {
+		return fmt.Errorf("failed to match event pattern of rule `%s`: %w", opt.Rule, err)
+	}
+	fmt.Printf("Rule: %s\n", opt.Rule)
+	fmt.Printf("Matched: %t\n", matched)
+	if opt.Remote {
+		remoteMatched, err := app.eventbridgeSvc.TestEventPattern(ctx, *rule.EventPattern, string(event))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Matched (TestEventPattern API): %t\n", remoteMatched)
+		if remoteMatched != matched {
+			log.Printf("[warn] local result of rule `%s` differs from TestEventPattern API result", opt.Rule)
+		}
+		matched = remoteMatched
+	}
+	if !matched {
+		return nil
+	}
+	input, err := RenderTargetInput(rule.Target, event, coalesce(rule.RuleArn), opt.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to render target input: %w", err)
+	}
+	fmt.Println("Input:")
+	fmt.Println(input)
+	return nil
+}
+
+// TriggerSetEnabled changes only the state of the named rules and schedules, without full deploy.
+func (app *App) TriggerSetEnabled(ctx context.Context, opt TriggerStateOption, enabled bool) error {
+	if !opt.All && len(opt.Names) == 0 {
+		return errors.New("rule or schedule names or --all is required")
+	}
+	if opt.All && len(opt.Names) > 0 {
+		return errors.New("names and --all can not be specified at the same time")
+	}
+	stateMachineArn, err := app.sfnSvc.GetStateMachineArn(ctx, &GetStateMachineArnInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get state machine arn: %w", err)
+	}
+	targetArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
+	rules, err := app.eventbridgeSvc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
+		StateMachineQualifiedArn: targetArn,
+		RuleNames:                unique(append(app.cfg.NewEventBridgeRules().Names(), opt.Names...)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search related rules: %w", err)
+	}
+	schedules, err := app.schedulerSvc.SearchRelatedSchedules(ctx, &SearchRelatedSchedulesInput{
+		StateMachineQualifiedArn: targetArn,
+		ScheduleNames:            unique(append(app.cfg.NewSchedules().keys(), opt.Names...)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search related schedules: %w", err)
+	}
+	schedules, _ = schedules.FilterOneOff()
+	rules, schedules = filterToggleableTriggers(rules, schedules, stateMachineArn, app.cfg.NewEventBridgeRules(), app.cfg.NewSchedules())
+	if !opt.All {
+		rules, schedules, err = filterTriggersByName(rules, schedules, opt.Names)
+		if err != nil {
+			return err
+		}
+	}
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+	for _, rule := range rules {
+		if (rule.State != eventbridgetypes.RuleStateDisabled) == enabled {
+			log.Printf("[info] rule `%s` is already %sd", coalesce(rule.Name), action)
+			continue
+		}
+		log.Printf("[notice] %s rule `%s` %s", action, coalesce(rule.Name), opt.DryRunString())
+		if opt.DryRun {
+			continue
+		}
+		if err := app.eventbridgeSvc.SetRuleEnabled(ctx, rule, enabled); err != nil {
+			return err
+		}
+	}
+	for _, schedule := range schedules {
+		if (schedule.State != schedulertypes.ScheduleStateDisabled) == enabled {
+			log.Printf("[info] schedule `%s` is already %sd", coalesce(schedule.Name), action)
+			continue
+		}
+		log.Printf("[notice] %s schedule `%s` %s", action, coalesce(schedule.Name), opt.DryRunString())
+		if opt.DryRun {
+			continue
+		}
+		if err := app.schedulerSvc.SetScheduleEnabled(ctx, schedule, enabled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterToggleableTriggers drops the rules and schedules that do not target the state machine,
+// and the ones that are not in the config and not managed by stefunny, not to toggle triggers of others by name.
+func filterToggleableTriggers(rules EventBridgeRules, schedules Schedules, stateMachineArn string, cfgRules EventBridgeRules, cfgSchedules Schedules) (EventBridgeRules, Schedules) {
+	filteredRules := make(EventBridgeRules, 0, len(rules))
+	for _, rule := range rules {
+		name := coalesce(rule.Name)
+		if !isStateMachineTarget(coalesce(rule.Target.Arn), stateMachineArn) {
+			log.Printf("[warn] rule `%s` does not target the state machine, skip", name)
+			continue
+		}
+		if _, ok := cfgRules.FindByName(name); !ok && !rule.IsManagedBy() {
+			log.Printf("[warn] rule `%s` is not in the config and not managed by %s, skip", name, appName)
+			continue
+		}
+		filteredRules = append(filteredRules, rule)
+	}
+	cfgScheduleKeys := cfgSchedules.keys()
+	filteredSchedules := make(Schedules, 0, len(schedules))
+	for _, schedule := range schedules {
+		name := coalesce(schedule.Name)
+		if schedule.Target == nil || !isStateMachineTarget(coalesce(schedule.Target.Arn), stateMachineArn) {
+			log.Printf("[warn] schedule `%s` does not target the state machine, skip", name)
+			continue
+		}
+		if !slices.Contains(cfgScheduleKeys, schedule.key()) && !schedule.IsManagedBy() {
+			log.Printf("[warn] schedule `%s` is not in the config and not managed by %s, skip", name, appName)
+			continue
+		}
+		filteredSchedules = append(filteredSchedules, schedule)
+	}
+	return filteredRules, filteredSchedules
+}
+
+// filterTriggersByName returns rules and schedules of the names, a name can match both of a rule and a schedule.
+func filterTriggersByName(rules EventBridgeRules, schedules Schedules, names []string) (EventBridgeRules, Schedules, error) {
+	filteredRules := make(EventBridgeRules, 0, len(names))
+	filteredSchedules := make(Schedules, 0, len(names))
+	for _, name := range unique(names) {
+		rule, isRule := rules.FindByName(name)
+		if isRule {
+			filteredRules = append(filteredRules, rule)
+		}
+		schedule, isSchedule := schedules.FindByName(name)
+		if isSchedule {
+			filteredSchedules = append(filteredSchedules, schedule)
+		}
+		if !isRule && !isSchedule {
+			return nil, nil, fmt.Errorf("rule or schedule `%s` not found", name)
+		}
+	}
+	return filteredRules, filteredSchedules, nil
+}
+
+type TriggerReplayOption struct {
+	Rule     string        `name:"rule" help:"rule name of trigger.event" required:"" json:"rule,omitempty"`
+	From     string        `name:"from" help:"start time of the events to replay (RFC3339 or YYYY-MM-DDThh:mm[:ss] in UTC)" required:"" json:"from,omitempty"`
+	To       string        `name:"to" help:"end time of the events to replay (RFC3339 or YYYY-MM-DDThh:mm[:ss] in UTC)" required:"" json:"to,omitempty"`
+	Archive  string        `name:"archive" help:"archive name, defaults to the archive of the rule in config or the rule name" json:"archive,omitempty"`
+	Timeout  time.Duration `name:"timeout" help:"how long to wait for the replay, 0 waits until the replay ends" default:"0s" json:"timeout,omitempty"`
+	Interval time.Duration `name:"interval" help:"interval to check the state of the replay" default:"5s" json:"interval,omitempty"`
+}
+
+func parseReplayTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return parseScheduleExecutionTime(s, "UTC")
+}
+
+// TriggerReplay replays the archived events between from and to into the rule, and waits until the replay ends.
+func (app *App) TriggerReplay(ctx context.Context, opt TriggerReplayOption) error {
+	from, err := parseReplayTime(opt.From)
+	if err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	to, err := parseReplayTime(opt.To)
+	if err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+	if !from.Before(to) {
+		return errors.New("--from must be before --to")
+	}
+	stateMachineArn, err := app.sfnSvc.GetStateMachineArn(ctx, &GetStateMachineArnInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get state machine arn: %w", err)
+	}
+	targetArn := addQualifierToArn(stateMachineArn, app.StateMachineAliasName())
+	rules, err := app.eventbridgeSvc.SearchRelatedRules(ctx, &SearchRelatedRulesInput{
+		StateMachineQualifiedArn: targetArn,
+		RuleNames:                []string{opt.Rule},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search related rules: %w", err)
+	}
+	rule, ok := rules.FindByName(opt.Rule)
+	if !ok {
+		return fmt.Errorf("rule `%s` is not deployed", opt.Rule)
+	}
+	if coalesce(rule.EventPattern) == "" {
+		return fmt.Errorf("rule `%s` has no event_pattern, scheduled rules can not be replayed", opt.Rule)
+	}
+	archiveName := opt.Archive
+	if archiveName == "" {
+		archive := &eventbridge.CreateArchiveInput{
+			ArchiveName:  rule.Name,
+			EventPattern: rule.EventPattern,
+		}
+		if cfgRule, ok := app.cfg.NewEventBridgeRules().FindByName(opt.Rule); ok && cfgRule.Archive != nil {
+			cfgRule.AppendArchiveManagedByMarker()
+			archive = cfgRule.Archive
+		}
+		archive.EventSourceArn = aws.String(eventBusArnFromRuleArn(coalesce(rule.RuleArn), coalesce(rule.EventBusName)))
+		if _, err := app.eventbridgeSvc.DeployArchive(ctx, archive); err != nil {
+			return fmt.Errorf("failed to deploy archive: %w", err)
+		}
+		archiveName = coalesce(archive.ArchiveName)
+	}
+	waitCtx := ctx
+	if opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, opt.Timeout)
+		defer cancel()
+	}
+	output, err := app.eventbridgeSvc.ReplayArchive(waitCtx, &ReplayArchiveInput{
+		ArchiveName:    archiveName,
+		RuleArn:        coalesce(rule.RuleArn),
+		EventStartTime: from,
+		EventEndTime:   to,
+		PollInterval:   opt.Interval,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil && output != nil {
+			return fmt.Errorf("replay `%s` does not end within %s, the replay continues", coalesce(output.ReplayName), opt.Timeout)
+		}
+		return fmt.Errorf("failed to replay archive `%s`: %w", archiveName, err)
+	}
+	if output.State != eventbridgetypes.ReplayStateCompleted {
+		return fmt.Errorf("replay `%s` is %s: %s", coalesce(output.ReplayName), output.State, coalesce(output.StateReason))
+	}
+	log.Printf("[info] replay `%s` completed", coalesce(output.ReplayName))
+	return nil
+}
+
+type TriggerSendEventOption struct {
+	Rule    string        `name:"rule" help:"rule name of trigger.event" required:"" json:"rule,omitempty"`
+	Event   string        `name:"event" help:"path to event JSON file to put" required:"" type:"existingfile" json:"event,omitempty"`
+	Timeout time.Duration `name:"timeout" help:"how long to wait for the execution triggered by the event" default:"1m" json:"timeout,omitempty"`
+}
+
+// TriggerSendEvent puts the event to the event bus of the rule, and waits for the execution that the rule started.
+func (app *App) TriggerSendEvent(ctx context.Context, opt TriggerSendEventOption) error {
+	rule, ok := app.cfg.NewEventBridgeRules().FindByName(opt.Rule)
+	if !ok {
+		return fmt.Errorf("rule `%s` not found in config", opt.Rule)
+	}
+	if coalesce(rule.EventPattern) == "" {
+		return fmt.Errorf("rule `%s` has no event_pattern", opt.Rule)
+	}
+	event, err := os.ReadFile(opt.Event)
+	if err != nil {
+		return fmt.Errorf("failed to read event file: %w", err)
+	}
+	if matched, err := MatchEventPattern(*rule.EventPattern, event); err != nil {
+		log.Printf("[warn] failed to match event pattern of rule `%s` locally: %v", opt.Rule, err)
+	} else if !matched {
+		log.Printf("[warn] the event does not match event_pattern of rule `%s`, the rule will not fire", opt.Rule)
+	}
+	stateMachine, err := app.sfnSvc.DescribeStateMachine(ctx, &DescribeStateMachineInput{
+		Name: app.cfg.StateMachineName(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe state machine: %w", err)
+	}
+	sentAt := time.Now().Truncate(time.Second)
+	eventID, err := app.eventbridgeSvc.PutEvent(ctx, coalesce(rule.EventBusName), event)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rule: %s\n", opt.Rule)
+	fmt.Printf("EventID: %s\n", eventID)
+	if stateMachine.Type == sfntypes.StateMachineTypeExpress {
+		log.Println("[warn] executions of EXPRESS state machine can not be listed, check the logs of the state machine")
+		return nil
+	}
+	output, err := app.sfnSvc.WaitTriggeredExecution(ctx, &WaitTriggeredExecutionInput{
+		StateMachineArn: coalesce(stateMachine.StateMachineArn),
+		After:           sentAt,
+		Timeout:         opt.Timeout,
+	})
+	if err != nil {
+		if errors.Is(err, ErrExecutionNotTriggered) {
+			fmt.Println("Fired: false")
+			return fmt.Errorf("no execution is started by rule `%s` within %s", opt.Rule, opt.Timeout)
+		}
+		return fmt.Errorf("failed to wait execution: %w", err)
+	}
+	fmt.Println("Fired: true")
+	fmt.Printf("ExecutionArn: %s\n", output.ExecutionArn)
+	if output.Failed != nil && *output.Failed {
+		if output.Datail != nil {
+			log.Printf("[info] execution detail:\n%s", MarshalJSONString(output.Datail))
+		}
+		return errors.New("state machine execution failed")
+	}
+	log.Printf("[info] execution success, execution time: %s", output.Elapsed())
+	return nil
+}
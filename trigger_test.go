@@ -0,0 +1,153 @@
+package stefunny_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/mashiike/stefunny"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTriggerSetEnabled(t *testing.T) {
+	const targetArn = "arn:aws:states:us-east-1:000000000000:stateMachine:Hello:current"
+	managedTags := []eventbridgetypes.Tag{
+		{Key: aws.String("ManagedBy"), Value: aws.String("stefunny")},
+	}
+	rule := func(name string, arn string, tags []eventbridgetypes.Tag) *stefunny.EventBridgeRule {
+		return &stefunny.EventBridgeRule{
+			PutRuleInput: eventbridge.PutRuleInput{
+				Name:  aws.String(name),
+				State: eventbridgetypes.RuleStateEnabled,
+				Tags:  tags,
+			},
+			Target: eventbridgetypes.Target{
+				Arn: aws.String(arn),
+			},
+		}
+	}
+	rules := func() stefunny.EventBridgeRules {
+		return stefunny.EventBridgeRules{
+			rule("Noisy", targetArn, managedTags),
+			rule("Quiet", targetArn, managedTags),
+			// created by hand
+			rule("Foreign", targetArn, nil),
+			// found by the name, but targets the other state machine
+			rule("Other", "arn:aws:states:us-east-1:000000000000:stateMachine:Other", managedTags),
+		}
+	}
+	schedule := func(name string, state schedulertypes.ScheduleState, arn string, description string) *stefunny.Schedule {
+		return &stefunny.Schedule{
+			CreateScheduleInput: scheduler.CreateScheduleInput{
+				Name:        aws.String(name),
+				State:       state,
+				Description: aws.String(description),
+				Target: &schedulertypes.Target{
+					Arn: aws.String(arn),
+				},
+			},
+		}
+	}
+	schedules := func() stefunny.Schedules {
+		return stefunny.Schedules{
+			schedule("Hourly", schedulertypes.ScheduleStateEnabled, targetArn, "[ManagedBy=stefunny]"),
+			schedule("Daily", schedulertypes.ScheduleStateDisabled, targetArn, "[ManagedBy=stefunny]"),
+			schedule("Handmade", schedulertypes.ScheduleStateEnabled, targetArn, "created by hand"),
+			schedule("Elsewhere", schedulertypes.ScheduleStateEnabled, "arn:aws:lambda:us-east-1:000000000000:function:Elsewhere", "[ManagedBy=stefunny]"),
+		}
+	}
+	cases := []struct {
+		name              string
+		opt               stefunny.TriggerStateOption
+		enabled           bool
+		expectedRules     []string
+		expectedSchedules []string
+		isErr             bool
+	}{
+		{
+			name:              "disable by name",
+			opt:               stefunny.TriggerStateOption{Names: []string{"Noisy", "Hourly"}},
+			expectedRules:     []string{"Noisy"},
+			expectedSchedules: []string{"Hourly"},
+		},
+		{
+			name:          "disable all",
+			opt:           stefunny.TriggerStateOption{All: true},
+			expectedRules: []string{"Noisy", "Quiet"},
+			// Daily is already disabled
+			expectedSchedules: []string{"Hourly"},
+		},
+		{
+			name:              "enable",
+			opt:               stefunny.TriggerStateOption{Names: []string{"Daily", "Quiet"}},
+			enabled:           true,
+			expectedSchedules: []string{"Daily"},
+		},
+		{
+			name: "dry run",
+			opt:  stefunny.TriggerStateOption{Names: []string{"Noisy"}, DryRun: true},
+		},
+		{
+			name:  "rule not managed",
+			opt:   stefunny.TriggerStateOption{Names: []string{"Foreign"}},
+			isErr: true,
+		},
+		{
+			name:  "rule of other state machine",
+			opt:   stefunny.TriggerStateOption{Names: []string{"Other"}},
+			isErr: true,
+		},
+		{
+			name:  "schedule not managed",
+			opt:   stefunny.TriggerStateOption{Names: []string{"Handmade"}},
+			isErr: true,
+		},
+		{
+			name:  "schedule of other target",
+			opt:   stefunny.TriggerStateOption{Names: []string{"Elsewhere"}},
+			isErr: true,
+		},
+		{
+			name:  "not found",
+			opt:   stefunny.TriggerStateOption{Names: []string{"Unknown"}},
+			isErr: true,
+		},
+		{
+			name:  "no names",
+			opt:   stefunny.TriggerStateOption{},
+			isErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			LoggerSetup(t, "debug")
+			m := NewMocks(t)
+			defer m.Finish()
+			app := newMockApp(t, "testdata/stefunny.yaml", m)
+			m.sfn.EXPECT().GetStateMachineArn(gomock.Any(), gomock.Any()).Return("arn:aws:states:us-east-1:000000000000:stateMachine:Hello", nil).AnyTimes()
+			m.eventBridge.EXPECT().SearchRelatedRules(gomock.Any(), gomock.Any()).Return(rules(), nil).AnyTimes()
+			m.scheduler.EXPECT().SearchRelatedSchedules(gomock.Any(), gomock.Any()).Return(schedules(), nil).AnyTimes()
+			for _, name := range c.expectedRules {
+				m.eventBridge.EXPECT().SetRuleEnabled(gomock.Any(), gomock.Cond(func(rule *stefunny.EventBridgeRule) bool {
+					return aws.ToString(rule.Name) == name
+				}), c.enabled).Return(nil).Times(1)
+			}
+			for _, name := range c.expectedSchedules {
+				m.scheduler.EXPECT().SetScheduleEnabled(gomock.Any(), gomock.Cond(func(schedule *stefunny.Schedule) bool {
+					return aws.ToString(schedule.Name) == name
+				}), c.enabled).Return(nil).Times(1)
+			}
+			err := app.TriggerSetEnabled(context.Background(), c.opt, c.enabled)
+			if c.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package stefunny
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/Songmu/prompter"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/fatih/color"
 )
@@ -81,6 +83,43 @@ func addQualifierToArn(arnStr string, name string) string {
 	return fmt.Sprintf("%s:%s", arnStr, name)
 }
 
+// qualifyTriggerTargetArn returns the target arn of a trigger that has own qualifier.
+// empty qualifier means the given qualified arn as is, and `$LATEST` means the unqualified arn.
+func qualifyTriggerTargetArn(stateMachineQualifiedArn string, qualifier *string) string {
+	if coalesce(qualifier) == "" {
+		return stateMachineQualifiedArn
+	}
+	unqualified := removeQualifierFromArn(stateMachineQualifiedArn)
+	if strings.HasPrefix(stateMachineQualifiedArn, knownAfterDeployArn) {
+		unqualified = knownAfterDeployArn
+	}
+	if *qualifier == latestQualifier {
+		return unqualified
+	}
+	return addQualifierToArn(unqualified, *qualifier)
+}
+
+// triggerQualifierFromArn returns the qualifier of the trigger that targets the arn, the reverse of qualifyTriggerTargetArn.
+// it returns nil if the target is the given qualified arn, or not the state machine.
+func triggerQualifierFromArn(targetArn string, stateMachineQualifiedArn string) *string {
+	if targetArn == "" || targetArn == stateMachineQualifiedArn {
+		return nil
+	}
+	unqualified := removeQualifierFromArn(stateMachineQualifiedArn)
+	if targetArn == unqualified {
+		return aws.String(latestQualifier)
+	}
+	if qualifier, ok := strings.CutPrefix(targetArn, unqualified+":"); ok {
+		return aws.String(qualifier)
+	}
+	return nil
+}
+
+// isStateMachineTarget returns true if the target arn is the state machine, its version or its alias.
+func isStateMachineTarget(targetArn string, stateMachineArn string) bool {
+	return targetArn != "" && removeQualifierFromArn(targetArn) == removeQualifierFromArn(stateMachineArn)
+}
+
 func removeQualifierFromArn(arnStr string) string {
 	arnObj, err := arn.Parse(arnStr)
 	if err != nil {
@@ -183,3 +222,27 @@ func createFileWithMkdir(path string) (*os.File, error) {
 	}
 	return file, nil
 }
+
+// executionArnFromStateMachineArn builds execution arn from state machine arn and execution name.
+// e.g. arn:aws:states:us-west-2:123456789012:execution:HelloWorld-StateMachine:name
+func executionArnFromStateMachineArn(stateMachineArn string, name string) (string, error) {
+	arnObj, err := arn.Parse(removeQualifierFromArn(stateMachineArn))
+	if err != nil {
+		return "", fmt.Errorf("parse arn failed: %w", err)
+	}
+	parts := strings.Split(arnObj.Resource, ":")
+	if len(parts) != 2 || parts[0] != "stateMachine" {
+		return "", fmt.Errorf("`%s` is not state machine arn", stateMachineArn)
+	}
+	arnObj.Resource = strings.Join([]string{"execution", parts[1], name}, ":")
+	return arnObj.String(), nil
+}
+
+// convertByJSON converts src to dst via JSON, for converting between similar AWS SDK types.
+func convertByJSON(src any, dst any) error {
+	bs, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, dst)
+}